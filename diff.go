@@ -0,0 +1,112 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"time"
+
+	"report/internal/domain"
+	"report/internal/infrastructure"
+	"report/internal/usecase"
+)
+
+// runDiff compara duas listagens ou dois períodos do histórico
+func runDiff(args []string) {
+	fs := flag.NewFlagSet("diff", flag.ExitOnError)
+	antes := fs.String("antes", "", "listagem (arquivo) ou período do histórico (AAAA-MM) de referência")
+	depois := fs.String("depois", "", "listagem (arquivo) ou período do histórico (AAAA-MM) a comparar")
+	aliasesPath := fs.String("aliases", "./files/aliases.csv", "tabela de apelidos de localidades")
+	historicoDir := fs.String("historico", defaultHistoricoDir, "diretório do histórico de períodos")
+	manutencaoPath := fs.String("manutencao", "./files/manutencao.csv", "plano de manutenção preventiva por localidade")
+	brigadaPath := fs.String("brigada", "./files/brigada.json", "registro dos treinamentos da Brigada de Incêndio")
+	brigadaValidade := fs.Int("brigada-validade", usecase.ValidadeBrigadaPadrao, "validade, em meses, do treinamento da Brigada de Incêndio")
+	periodo := time.Now().Format(domain.LayoutPeriodo)
+	fs.Func("periodo", "período de referência dos alertas das listagens: AAAA-MM (padrão: o mês atual)", func(valor string) error {
+		periodo = valor
+		return domain.ValidarPeriodo(valor)
+	})
+	formato := fs.String("formato", "console", "formato da saída: console, json ou pdf")
+	saida := fs.String("saida", "", "arquivo de saída para os formatos json e pdf")
+	privacidade := domain.PrivacidadeIniciais
+	fs.Func("privacidade", "nomes dos voluntários na comparação: iniciais (padrão), primeiro-nome ou completo", func(valor string) error {
+		var err error
+		privacidade, err = domain.ParsePrivacidade(valor)
+		return err
+	})
+	newLogger := logFlags(fs)
+	fs.Parse(args)
+
+	logger := newLogger()
+
+	if *antes == "" || *depois == "" {
+		fmt.Fprintln(os.Stderr, "informe -antes e -depois")
+		fs.Usage()
+		os.Exit(2)
+	}
+
+	// As listagens são avaliadas como na geração dos relatórios, para que os
+	// alertas do plano de manutenção e da brigada sejam comparáveis aos do
+	// histórico; o registro da brigada é só lido
+	historicoRepo := infrastructure.NewJSONHistoricoRepository(*historicoDir)
+	opts := []usecase.Option{
+		usecase.WithLogger(logger),
+		usecase.WithPeriodo(periodo),
+		usecase.WithAliasRepository(infrastructure.NewCSVAliasRepository(*aliasesPath, "")),
+		usecase.WithPlanoManutencao(infrastructure.NewCSVPlanoManutencaoRepository(*manutencaoPath)),
+		usecase.WithBrigada(infrastructure.NewJSONBrigadaRepository(*brigadaPath), *brigadaValidade),
+	}
+	carregar := func(origem string) *domain.Snapshot {
+		snapshot, err := carregarSnapshot(origem, historicoRepo, logger, opts...)
+		if err != nil {
+			fatal(logger, "erro ao carregar "+origem, err)
+		}
+		return snapshot
+	}
+
+	diferenca := usecase.CompararSnapshots(carregar(*antes), carregar(*depois), *antes, *depois, privacidade)
+
+	switch *formato {
+	case "console":
+		fmt.Print(diferenca)
+	case "json":
+		content, err := json.MarshalIndent(diferenca, "", "  ")
+		if err != nil {
+			fatal(logger, "erro ao gerar JSON", err)
+		}
+		if *saida == "" {
+			fmt.Println(string(content))
+			return
+		}
+		if err := os.WriteFile(*saida, append(content, '\n'), 0644); err != nil {
+			fatal(logger, "erro ao gravar "+*saida, err)
+		}
+		fmt.Printf("Diferenças gravadas em %s\n", *saida)
+	case "pdf":
+		caminho := *saida
+		if caminho == "" {
+			caminho = filepath.Join(usecase.DefaultOutputDir, "diferencas.pdf")
+		}
+		if err := infrastructure.NewGofpdfService(logger).GenerateDiffReport(diferenca, caminho); err != nil {
+			fatal(logger, "erro ao gerar PDF", err)
+		}
+		fmt.Printf("Diferenças gravadas em %s\n", caminho)
+	default:
+		fatal(logger, "formato inválido", fmt.Errorf("formato desconhecido: %s", *formato))
+	}
+}
+
+// carregarSnapshot lê os dados consolidados de uma listagem, quando origem é
+// um arquivo existente, ou do histórico, quando origem é um período
+func carregarSnapshot(origem string, historicoRepo domain.HistoricoRepository, logger *slog.Logger, opts ...usecase.Option) (*domain.Snapshot, error) {
+	if _, err := os.Stat(origem); err != nil {
+		return historicoRepo.Get(origem)
+	}
+
+	localidadeRepo, setorRepo, livroRepo := infrastructure.NewCSVRepositories(origem, "", logger)
+	generator := usecase.NewReportGenerator(localidadeRepo, setorRepo, livroRepo, nil, opts...)
+	return generator.Snapshot()
+}
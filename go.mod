@@ -4,5 +4,8 @@ go 1.21
 
 require (
 	github.com/jung-kurt/gofpdf/v2 v2.17.3
+	github.com/shakinm/xlsReader v0.9.12
 	golang.org/x/text v0.14.0
 )
+
+require github.com/metakeule/fmtdate v1.1.2 // indirect
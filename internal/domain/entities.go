@@ -1,8 +1,228 @@
 package domain
 
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+	"unicode/utf8"
+)
+
+// Livros com tratamento próprio nos alertas, no plano de manutenção e na brigada
+const (
+	LivroAdministracao = "4 - ADMINISTRAÇÃO"
+	LivroManutencao    = "2 - MANUTENÇÃO PREVENTIVA"
+	LivroBrigada       = "4 - BRIGADA DE INCÊNDIO"
+)
+
 // Summary representa o resumo de trabalhos de um livro
 type Summary struct {
 	TotalTrabalhos int
+	// Horas é a soma das horas trabalhadas nos apontamentos do livro
+	Horas float64
+	// Voluntarios contém o número de apontamentos de cada voluntário
+	Voluntarios map[string]int
+	// HorasVoluntarios contém as horas trabalhadas por cada voluntário
+	HorasVoluntarios map[string]float64 `json:",omitempty"`
+	// UltimoLancamento é a data do apontamento mais recente do livro
+	UltimoLancamento time.Time
+	// UltimoLancamentoVoluntarios é a data do apontamento mais recente de cada voluntário
+	UltimoLancamentoVoluntarios map[string]time.Time `json:",omitempty"`
+	// LancamentosDia contém o número de apontamentos de cada dia, no LayoutDia
+	LancamentosDia map[string]int `json:",omitempty"`
+	// HorasDia contém as horas trabalhadas em cada dia, no LayoutDia
+	HorasDia map[string]float64 `json:",omitempty"`
+}
+
+// LayoutDia é o formato das datas usadas como chave dos apontamentos por dia
+const LayoutDia = "2006-01-02"
+
+// TotalVoluntarios retorna o número de voluntários distintos com apontamentos no livro
+func (s *Summary) TotalVoluntarios() int {
+	return len(s.Voluntarios)
+}
+
+// Valor retorna o resumo do livro na métrica informada
+func (s *Summary) Valor(metrica Metrica) float64 {
+	switch metrica {
+	case MetricaHoras:
+		return s.Horas
+	case MetricaVoluntarios:
+		return float64(s.TotalVoluntarios())
+	default:
+		return float64(s.TotalTrabalhos)
+	}
+}
+
+// Metrica define como os apontamentos de um livro são medidos nas regras e relatórios
+type Metrica string
+
+// Métricas disponíveis
+const (
+	MetricaLancamentos Metrica = "lancamentos"
+	MetricaHoras       Metrica = "horas"
+	MetricaVoluntarios Metrica = "voluntarios"
+)
+
+// ParseMetrica converte o nome de uma métrica, aceitando vazio como lançamentos
+func ParseMetrica(nome string) (Metrica, error) {
+	switch metrica := Metrica(strings.ToLower(strings.TrimSpace(nome))); metrica {
+	case "":
+		return MetricaLancamentos, nil
+	case MetricaLancamentos, MetricaHoras, MetricaVoluntarios:
+		return metrica, nil
+	default:
+		return "", fmt.Errorf("métrica desconhecida: %s (use lancamentos, horas ou voluntarios)", nome)
+	}
+}
+
+// Unidade retorna o nome usado nas mensagens para a métrica
+func (m Metrica) Unidade() string {
+	switch m {
+	case MetricaHoras:
+		return "horas"
+	case MetricaVoluntarios:
+		return "voluntários"
+	default:
+		return "apontamentos"
+	}
+}
+
+// RegistroBrigada guarda as datas dos treinamentos de cada voluntário da
+// Brigada de Incêndio, por localidade e chave do voluntário (Privacidade.Chave),
+// em ordem cronológica
+type RegistroBrigada map[string]map[string][]time.Time
+
+// Registrar acrescenta o treinamento do voluntário, ignorando datas já registradas
+func (r RegistroBrigada) Registrar(localidade, voluntario string, data time.Time) {
+	if r[localidade] == nil {
+		r[localidade] = make(map[string][]time.Time)
+	}
+	datas := r[localidade][voluntario]
+	i := sort.Search(len(datas), func(i int) bool { return !datas[i].Before(data) })
+	if i < len(datas) && datas[i].Equal(data) {
+		return
+	}
+	datas = append(datas, time.Time{})
+	copy(datas[i+1:], datas[i:])
+	datas[i] = data
+	r[localidade][voluntario] = datas
+}
+
+// Treinamentos retorna o último treinamento de cada voluntário da localidade
+// até a data de referência; treinamentos posteriores, registrados por
+// períodos mais recentes, não contam
+func (r RegistroBrigada) Treinamentos(localidade string, referencia time.Time) map[string]time.Time {
+	treinamentos := make(map[string]time.Time)
+	for voluntario, datas := range r[localidade] {
+		for i := len(datas) - 1; i >= 0; i-- {
+			if !datas[i].After(referencia) {
+				treinamentos[voluntario] = datas[i]
+				break
+			}
+		}
+	}
+	return treinamentos
+}
+
+// Privacidade define como os nomes dos voluntários aparecem nos relatórios.
+// O CPF da listagem nunca é lido.
+type Privacidade string
+
+// Políticas de privacidade disponíveis
+const (
+	PrivacidadeIniciais     Privacidade = "iniciais"
+	PrivacidadePrimeiroNome Privacidade = "primeiro-nome"
+	PrivacidadeNomeCompleto Privacidade = "completo"
+)
+
+// ParsePrivacidade converte o nome da política; vazio equivale às iniciais
+func ParsePrivacidade(nome string) (Privacidade, error) {
+	switch privacidade := Privacidade(strings.ToLower(strings.TrimSpace(nome))); privacidade {
+	case "":
+		return PrivacidadeIniciais, nil
+	case PrivacidadeIniciais, PrivacidadePrimeiroNome, PrivacidadeNomeCompleto:
+		return privacidade, nil
+	default:
+		return "", fmt.Errorf("política de privacidade desconhecida: %s (use iniciais, primeiro-nome ou completo)", nome)
+	}
+}
+
+// preposicoes não geram inicial nos nomes abreviados
+var preposicoes = map[string]bool{"DA": true, "DAS": true, "DE": true, "DO": true, "DOS": true, "E": true}
+
+// Nome apresenta o nome do voluntário conforme a política: "J. B. M." com
+// iniciais, "JOSE B. M." com o primeiro nome ou o nome completo. Partes que
+// não começam por letra, como "(BARRAGEM)" ou "-", não são partes do nome e
+// ficam de fora da abreviação.
+func (p Privacidade) Nome(nome string) string {
+	partes := strings.Fields(nome)
+	if p == PrivacidadeNomeCompleto || len(partes) == 0 {
+		return strings.Join(partes, " ")
+	}
+
+	var abreviado []string
+	for _, parte := range partes {
+		inicial, _ := utf8.DecodeRuneInString(parte)
+		switch {
+		case !unicode.IsLetter(inicial):
+			continue
+		case len(abreviado) == 0 && p == PrivacidadePrimeiroNome:
+			abreviado = append(abreviado, parte)
+		case preposicoes[strings.ToUpper(parte)]:
+			continue
+		default:
+			abreviado = append(abreviado, string(inicial)+".")
+		}
+	}
+	return strings.Join(abreviado, " ")
+}
+
+// Chave identifica o voluntário nos dados gravados (histórico e registro da
+// brigada) sem expor o nome: o nome conforme a política seguido de um resumo
+// do nome completo, como "J. B. M. #1a2b3c4d", que distingue voluntários com
+// as mesmas iniciais e é o mesmo em todos os períodos. Com o nome completo a
+// chave é o próprio nome. Uma chave já calculada é retornada sem alteração.
+//
+// A chave é um pseudônimo, não anonimiza: o resumo não usa segredo e pode ser
+// recalculado a partir de uma lista de nomes candidatos, reidentificando o
+// voluntário. Os dados gravados com ela continuam sendo dados pessoais.
+func (p Privacidade) Chave(nome string) string {
+	if _, ok := resumoChave(nome); p == PrivacidadeNomeCompleto || ok {
+		return strings.Join(strings.Fields(nome), " ")
+	}
+	completo := strings.ToUpper(strings.Join(strings.Fields(nome), " "))
+	return strings.TrimSpace(p.Nome(completo) + " #" + ResumoNome(completo))
+}
+
+// ResumoNome identifica o voluntário independentemente da política: o resumo
+// do nome completo ou, para uma chave gerada por Chave, o resumo nela contido.
+// Chaves de políticas diferentes do mesmo voluntário têm o mesmo resumo. São
+// os primeiros 32 bits do SHA-256 do nome, sem sal, o bastante para separar
+// homônimos de iniciais mas não para ocultar o nome (veja Chave).
+func ResumoNome(nome string) string {
+	if resumo, ok := resumoChave(nome); ok {
+		return resumo
+	}
+	completo := strings.ToUpper(strings.Join(strings.Fields(nome), " "))
+	soma := sha256.Sum256([]byte(completo))
+	return hex.EncodeToString(soma[:4])
+}
+
+// resumoChave extrai o resumo de uma chave gerada por Chave
+func resumoChave(nome string) (string, bool) {
+	i := strings.LastIndex(nome, " #")
+	if i < 0 || len(nome)-i != 2+8 {
+		return "", false
+	}
+	if _, err := hex.DecodeString(nome[i+2:]); err != nil {
+		return "", false
+	}
+	return nome[i+2:], true
 }
 
 // Localidade representa uma casa de oração
@@ -11,17 +231,252 @@ type Localidade struct {
 	Livros map[string]*Summary
 }
 
+// Administracao representa uma administração regional, que reúne setores
+type Administracao struct {
+	Nome    string
+	Setores []*Setor
+}
+
 // Setor representa um setor administrativo
 type Setor struct {
 	Nome        string
 	Localidades []string
 	Responsavel string
+	// Administracao é o nome da administração a que o setor pertence
+	Administracao string
+	// Emails são os contatos que recebem os relatórios do setor
+	Emails []string
 }
 
 // RelatorioConfig representa a configuração do relatório
 type RelatorioConfig struct {
 	LarguraLivro          float64
 	LarguraTotalTrabalhos float64
+	LarguraHoras          float64
+	LarguraVoluntarios    float64
 	LarguraApontamentos   float64
+	LarguraMeta           float64
+	LarguraCumprimento    float64
 	MargemPagina          float64
 }
+
+// Meta representa o mínimo mensal esperado de um livro, medido na métrica
+// informada. Sem setor e sem localidade, vale para todas as localidades; com
+// um deles, substitui a meta geral do livro naquele setor ou localidade. Uma
+// meta com valor zero dispensa o livro.
+type Meta struct {
+	Livro      string
+	Metrica    Metrica
+	Valor      float64
+	Setor      string
+	Localidade string
+}
+
+// Frequencia é a periodicidade de uma tarefa do plano de manutenção
+type Frequencia string
+
+// Frequências disponíveis
+const (
+	FrequenciaSemanal    Frequencia = "semanal"
+	FrequenciaMensal     Frequencia = "mensal"
+	FrequenciaTrimestral Frequencia = "trimestral"
+)
+
+// ParseFrequencia converte o nome de uma frequência, em português ou inglês
+func ParseFrequencia(nome string) (Frequencia, error) {
+	switch strings.ToLower(strings.TrimSpace(nome)) {
+	case "semanal", "weekly":
+		return FrequenciaSemanal, nil
+	case "mensal", "monthly":
+		return FrequenciaMensal, nil
+	case "trimestral", "quarterly":
+		return FrequenciaTrimestral, nil
+	default:
+		return "", fmt.Errorf("frequência desconhecida: %s (use semanal, mensal ou trimestral)", nome)
+	}
+}
+
+// TarefaManutencao é uma tarefa do plano de manutenção preventiva. A tarefa é
+// cumprida em cada semana, mês ou trimestre com ao menos um apontamento no
+// livro; sem localidade, vale para todas as localidades.
+type TarefaManutencao struct {
+	Localidade string
+	Tarefa     string
+	Frequencia Frequencia
+	Livro      string
+}
+
+// AgendaCultos indica os dias da semana com culto em uma localidade; sem
+// localidade, vale para as localidades sem agenda própria
+type AgendaCultos struct {
+	Localidade string
+	Dias       []time.Weekday
+}
+
+// diasSemana associa os nomes aceitos aos dias da semana
+var diasSemana = map[string]time.Weekday{
+	"domingo": time.Sunday, "dom": time.Sunday,
+	"segunda": time.Monday, "seg": time.Monday,
+	"terca": time.Tuesday, "terça": time.Tuesday, "ter": time.Tuesday,
+	"quarta": time.Wednesday, "qua": time.Wednesday,
+	"quinta": time.Thursday, "qui": time.Thursday,
+	"sexta": time.Friday, "sex": time.Friday,
+	"sabado": time.Saturday, "sábado": time.Saturday, "sab": time.Saturday, "sáb": time.Saturday,
+}
+
+// ParseDiasSemana converte uma lista de dias da semana separados por espaço,
+// vírgula ou ponto e vírgula, como "domingo quarta" ou "dom;qui"
+func ParseDiasSemana(valor string) ([]time.Weekday, error) {
+	campos := strings.FieldsFunc(strings.ToLower(valor), func(r rune) bool {
+		return r == ' ' || r == ',' || r == ';' || r == '/'
+	})
+	var dias []time.Weekday
+	for _, campo := range campos {
+		campo = strings.TrimSuffix(campo, "-feira")
+		dia, exists := diasSemana[campo]
+		if !exists {
+			return nil, fmt.Errorf("dia da semana desconhecido: %s", campo)
+		}
+		dias = append(dias, dia)
+	}
+	return dias, nil
+}
+
+// Observacao é uma anotação livre sobre uma localidade, impressa em
+// OBSERVAÇÕES no relatório do período. Sem período, vale para todos.
+type Observacao struct {
+	Periodo    string
+	Localidade string
+	Texto      string
+}
+
+// SugestaoAlias representa uma associação sugerida entre um nome de localidade
+// não reconhecido e uma localidade cadastrada
+type SugestaoAlias struct {
+	Alias        string
+	Localidade   string
+	Similaridade float64
+	Confirmado   bool
+}
+
+// EstatisticasLeitura resume as linhas processadas de um arquivo de entrada
+type EstatisticasLeitura struct {
+	// LinhasLidas conta as linhas de dados, sem o cabeçalho
+	LinhasLidas int
+	Ignoradas   map[string]int
+	// Codificacao e Delimitador indicam como a listagem em CSV foi lida
+	Codificacao string
+	Delimitador string
+}
+
+// TotalIgnoradas retorna o número de linhas descartadas por qualquer motivo
+func (e EstatisticasLeitura) TotalIgnoradas() int {
+	total := 0
+	for _, n := range e.Ignoradas {
+		total += n
+	}
+	return total
+}
+
+// LinhasValidas retorna o número de linhas lidas que foram aproveitadas
+func (e EstatisticasLeitura) LinhasValidas() int {
+	return e.LinhasLidas - e.TotalIgnoradas()
+}
+
+// Alerta representa um ponto de atenção identificado nos trabalhos de uma localidade
+type Alerta struct {
+	Livro    string
+	Mensagem string
+	// Severidade pesa o alerta na nota de saúde; vazia equivale a média
+	Severidade Severidade `json:",omitempty"`
+}
+
+// Severidade classifica o impacto de um alerta
+type Severidade string
+
+// Severidades dos alertas
+const (
+	SeveridadeAlta  Severidade = "alta"
+	SeveridadeMedia Severidade = "media"
+	SeveridadeBaixa Severidade = "baixa"
+)
+
+// PesosSaude são os pesos dos componentes da nota de saúde das localidades
+type PesosSaude struct {
+	Cobertura float64
+	Metas     float64
+	Alertas   float64
+	Tendencia float64
+}
+
+// PesosSaudePadrao são os pesos usados quando nenhum é informado
+var PesosSaudePadrao = PesosSaude{Cobertura: 30, Metas: 30, Alertas: 25, Tendencia: 15}
+
+// ParsePesosSaude converte uma lista como "cobertura=30,metas=30,alertas=25,tendencia=15";
+// os componentes omitidos mantêm o peso padrão. Ao menos um peso deve ser
+// positivo, pois a nota é a média ponderada dos componentes.
+func ParsePesosSaude(valor string) (PesosSaude, error) {
+	pesos := PesosSaudePadrao
+	for _, item := range strings.Split(valor, ",") {
+		if strings.TrimSpace(item) == "" {
+			continue
+		}
+		nome, numero, ok := strings.Cut(item, "=")
+		if !ok {
+			return pesos, fmt.Errorf("peso inválido: %s (use componente=peso)", item)
+		}
+		peso, err := strconv.ParseFloat(strings.TrimSpace(numero), 64)
+		if err != nil || peso < 0 {
+			return pesos, fmt.Errorf("peso inválido para %s: %s", strings.TrimSpace(nome), numero)
+		}
+		switch strings.ToLower(strings.TrimSpace(nome)) {
+		case "cobertura":
+			pesos.Cobertura = peso
+		case "metas":
+			pesos.Metas = peso
+		case "alertas":
+			pesos.Alertas = peso
+		case "tendencia", "tendência":
+			pesos.Tendencia = peso
+		default:
+			return pesos, fmt.Errorf("componente desconhecido: %s (use cobertura, metas, alertas ou tendencia)", nome)
+		}
+	}
+	if pesos.Cobertura+pesos.Metas+pesos.Alertas+pesos.Tendencia == 0 {
+		return pesos, fmt.Errorf("pesos inválidos: %s (ao menos um peso deve ser positivo)", valor)
+	}
+	return pesos, nil
+}
+
+// LayoutPeriodo é o formato dos períodos de referência (AAAA-MM)
+const LayoutPeriodo = "2006-01"
+
+// ValidarPeriodo verifica se o período está no formato AAAA-MM
+func ValidarPeriodo(periodo string) error {
+	if _, err := time.Parse(LayoutPeriodo, periodo); err != nil {
+		return fmt.Errorf("período inválido: %q (use AAAA-MM)", periodo)
+	}
+	return nil
+}
+
+// Snapshot representa os dados consolidados de um período, usados para
+// comparar execuções e acompanhar a evolução das localidades
+type Snapshot struct {
+	Periodo     string
+	GeradoEm    time.Time
+	Localidades map[string]map[string]*Summary
+	Alertas     map[string][]Alerta
+	// Administracoes associa cada localidade à administração indicada na listagem
+	Administracoes map[string]string `json:",omitempty"`
+}
+
+// Envio registra a entrega dos relatórios de um setor em um período
+type Envio struct {
+	Setor         string
+	Periodo       string
+	Destinatarios []string
+	Anexos        []string
+	// SHA256 identifica o conteúdo dos anexos enviados
+	SHA256    string
+	EnviadoEm time.Time
+}
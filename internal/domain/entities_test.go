@@ -0,0 +1,184 @@
+package domain
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestSummaryValor(t *testing.T) {
+	summary := &Summary{TotalTrabalhos: 5, Horas: 12.5, Voluntarios: map[string]int{"A": 3, "B": 2}}
+	tests := []struct {
+		metrica  Metrica
+		esperado float64
+	}{
+		{MetricaLancamentos, 5},
+		{MetricaHoras, 12.5},
+		{MetricaVoluntarios, 2},
+		{"", 5},
+	}
+	for _, tt := range tests {
+		if valor := summary.Valor(tt.metrica); valor != tt.esperado {
+			t.Errorf("Valor(%q) = %g, esperado %g", tt.metrica, valor, tt.esperado)
+		}
+	}
+}
+
+func TestParseMetrica(t *testing.T) {
+	tests := []struct {
+		nome     string
+		esperado Metrica
+		erro     bool
+	}{
+		{"", MetricaLancamentos, false},
+		{" Horas ", MetricaHoras, false},
+		{"voluntarios", MetricaVoluntarios, false},
+		{"minutos", "", true},
+	}
+	for _, tt := range tests {
+		metrica, err := ParseMetrica(tt.nome)
+		if metrica != tt.esperado || (err != nil) != tt.erro {
+			t.Errorf("ParseMetrica(%q) = %q, %v", tt.nome, metrica, err)
+		}
+	}
+}
+
+func TestParsePrivacidade(t *testing.T) {
+	tests := []struct {
+		nome     string
+		esperado Privacidade
+		erro     bool
+	}{
+		{"", PrivacidadeIniciais, false},
+		{" Primeiro-Nome ", PrivacidadePrimeiroNome, false},
+		{"completo", PrivacidadeNomeCompleto, false},
+		{"anonimo", "", true},
+	}
+	for _, tt := range tests {
+		privacidade, err := ParsePrivacidade(tt.nome)
+		if (err != nil) != tt.erro || privacidade != tt.esperado {
+			t.Errorf("ParsePrivacidade(%q) = %q, %v; esperado %q", tt.nome, privacidade, err, tt.esperado)
+		}
+	}
+}
+
+func TestPrivacidadeNome(t *testing.T) {
+	tests := []struct {
+		privacidade Privacidade
+		nome        string
+		esperado    string
+	}{
+		{PrivacidadeIniciais, "JOSE DA SILVA", "J. S."},
+		{PrivacidadeIniciais, "JOSE BATISTA MOURA (BARRAGEM)", "J. B. M."},
+		{PrivacidadePrimeiroNome, "JOSE DOS SANTOS - MOURA", "JOSE S. M."},
+		{PrivacidadeNomeCompleto, "  JOSE   DA SILVA ", "JOSE DA SILVA"},
+		{PrivacidadeIniciais, "", ""},
+	}
+	for _, tt := range tests {
+		if nome := tt.privacidade.Nome(tt.nome); nome != tt.esperado {
+			t.Errorf("%s.Nome(%q) = %q, esperado %q", tt.privacidade, tt.nome, nome, tt.esperado)
+		}
+	}
+}
+
+func TestPrivacidadeChave(t *testing.T) {
+	tests := []struct {
+		privacidade Privacidade
+		nome        string
+		esperado    string
+	}{
+		{PrivacidadeIniciais, "JOSE DA SILVA", "J. S. #cc8e1293"},
+		// A chave não depende de caixa nem de espaços
+		{PrivacidadeIniciais, " jose  da silva", "J. S. #cc8e1293"},
+		{PrivacidadePrimeiroNome, "JOSE DA SILVA", "JOSE S. #cc8e1293"},
+		{PrivacidadeNomeCompleto, "JOSE  DA SILVA", "JOSE DA SILVA"},
+		// Uma chave já calculada não muda, mesmo com outra política
+		{PrivacidadePrimeiroNome, "J. S. #cc8e1293", "J. S. #cc8e1293"},
+		{PrivacidadeIniciais, "J. S. #cc8e1293", "J. S. #cc8e1293"},
+	}
+	for _, tt := range tests {
+		if chave := tt.privacidade.Chave(tt.nome); chave != tt.esperado {
+			t.Errorf("%s.Chave(%q) = %q, esperado %q", tt.privacidade, tt.nome, chave, tt.esperado)
+		}
+	}
+}
+
+func TestResumoNome(t *testing.T) {
+	tests := []struct {
+		nome     string
+		esperado string
+	}{
+		{"JOSE DA SILVA", "cc8e1293"},
+		{"jose da  silva", "cc8e1293"},
+		{"J. S. #cc8e1293", "cc8e1293"},
+		{"JOSE S. #cc8e1293", "cc8e1293"},
+		// Um sufixo que não é resumo faz parte do nome
+		{"JOSE #12", ResumoNome("JOSE #12 ")},
+	}
+	for _, tt := range tests {
+		if resumo := ResumoNome(tt.nome); resumo != tt.esperado {
+			t.Errorf("ResumoNome(%q) = %q, esperado %q", tt.nome, resumo, tt.esperado)
+		}
+	}
+	if ResumoNome("JOSE DA SILVA") == ResumoNome("JOAO DA SILVA") {
+		t.Error("nomes com as mesmas iniciais têm o mesmo resumo")
+	}
+}
+
+func TestRegistroBrigada(t *testing.T) {
+	dia := func(mes time.Month, dia int) time.Time { return time.Date(2024, mes, dia, 0, 0, 0, 0, time.UTC) }
+	registro := make(RegistroBrigada)
+	for _, data := range []time.Time{dia(5, 1), dia(2, 1), dia(9, 1), dia(5, 1)} {
+		registro.Registrar("VILA NOVA", "J. S. #cc8e1293", data)
+	}
+	registro.Registrar("VILA NOVA", "A. L. #00000000", dia(7, 1))
+
+	if datas := registro["VILA NOVA"]["J. S. #cc8e1293"]; !reflect.DeepEqual(datas, []time.Time{dia(2, 1), dia(5, 1), dia(9, 1)}) {
+		t.Errorf("datas = %v, esperado em ordem e sem repetição", datas)
+	}
+
+	tests := []struct {
+		referencia time.Time
+		esperado   map[string]time.Time
+	}{
+		{dia(1, 15), map[string]time.Time{}},
+		{dia(5, 1), map[string]time.Time{"J. S. #cc8e1293": dia(5, 1)}},
+		{dia(8, 31), map[string]time.Time{"J. S. #cc8e1293": dia(5, 1), "A. L. #00000000": dia(7, 1)}},
+		{dia(12, 31), map[string]time.Time{"J. S. #cc8e1293": dia(9, 1), "A. L. #00000000": dia(7, 1)}},
+	}
+	for _, tt := range tests {
+		if treinamentos := registro.Treinamentos("VILA NOVA", tt.referencia); !reflect.DeepEqual(treinamentos, tt.esperado) {
+			t.Errorf("Treinamentos(%s) = %v, esperado %v", tt.referencia.Format(LayoutDia), treinamentos, tt.esperado)
+		}
+	}
+	if treinamentos := registro.Treinamentos("CENTRO", dia(12, 31)); len(treinamentos) != 0 {
+		t.Errorf("Treinamentos de localidade sem registro = %v", treinamentos)
+	}
+}
+
+func TestParsePesosSaude(t *testing.T) {
+	tests := []struct {
+		valor    string
+		esperado PesosSaude
+		erro     bool
+	}{
+		{"", PesosSaudePadrao, false},
+		{"cobertura=50, metas=0", PesosSaude{Cobertura: 50, Metas: 0, Alertas: 25, Tendencia: 15}, false},
+		{"Tendência=2.5,alertas=10,", PesosSaude{Cobertura: 30, Metas: 30, Alertas: 10, Tendencia: 2.5}, false},
+		{"cobertura=0,metas=0,alertas=0,tendencia=0", PesosSaude{}, true},
+		{"cobertura", PesosSaude{}, true},
+		{"metas=-1", PesosSaude{}, true},
+		{"metas=abc", PesosSaude{}, true},
+		{"presenca=10", PesosSaude{}, true},
+	}
+	for _, tt := range tests {
+		pesos, err := ParsePesosSaude(tt.valor)
+		if (err != nil) != tt.erro {
+			t.Errorf("ParsePesosSaude(%q): erro = %v", tt.valor, err)
+			continue
+		}
+		if !tt.erro && pesos != tt.esperado {
+			t.Errorf("ParsePesosSaude(%q) = %+v, esperado %+v", tt.valor, pesos, tt.esperado)
+		}
+	}
+}
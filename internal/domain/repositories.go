@@ -4,12 +4,16 @@ package domain
 type LocalidadeRepository interface {
 	GetAll() (map[string]map[string]*Summary, error)
 	Save(localidade *Localidade) error
+	Estatisticas() EstatisticasLeitura
+	// Administracoes associa cada localidade da última leitura à sua administração
+	Administracoes() map[string]string
 }
 
 // SetorRepository define as operações de persistência para Setor
 type SetorRepository interface {
 	GetAll() (map[string]*Setor, error)
 	GetByLocalidade(localidade string) (*Setor, error)
+	GetAdministracoes() (map[string]*Administracao, error)
 }
 
 // LivroRepository define as operações de persistência para Livros
@@ -17,3 +21,53 @@ type LivroRepository interface {
 	GetByLocalidade(localidade string) (map[string]bool, error)
 	GetAll() (map[string]map[string]bool, error)
 }
+
+// AliasRepository define as operações de persistência para apelidos de localidades
+type AliasRepository interface {
+	GetAll() (map[string]string, error)
+	Save(alias, localidade string) error
+	GetPendentes() ([]SugestaoAlias, error)
+	SavePendentes(sugestoes []SugestaoAlias) error
+}
+
+// MetaRepository define as operações de persistência das metas por livro
+type MetaRepository interface {
+	GetAll() ([]Meta, error)
+}
+
+// PlanoManutencaoRepository define as operações de leitura do plano de manutenção preventiva
+type PlanoManutencaoRepository interface {
+	GetAll() ([]TarefaManutencao, error)
+}
+
+// AgendaCultosRepository define as operações de leitura dos dias de culto
+type AgendaCultosRepository interface {
+	GetAll() ([]AgendaCultos, error)
+}
+
+// BrigadaRepository define as operações de persistência do registro de
+// treinamentos da Brigada de Incêndio
+type BrigadaRepository interface {
+	Get() (RegistroBrigada, error)
+	Save(registro RegistroBrigada) error
+}
+
+// ObservacaoRepository define as operações de persistência das observações
+// das localidades
+type ObservacaoRepository interface {
+	GetAll() ([]Observacao, error)
+}
+
+// HistoricoRepository define as operações de persistência dos dados consolidados por período
+type HistoricoRepository interface {
+	Save(snapshot *Snapshot) error
+	Get(periodo string) (*Snapshot, error)
+	GetAnterior(periodo string) (*Snapshot, error)
+	List() ([]string, error)
+}
+
+// EnvioRepository define as operações de persistência do registro de envios
+type EnvioRepository interface {
+	Get(setor, periodo string) (*Envio, error)
+	Save(envio *Envio) error
+}
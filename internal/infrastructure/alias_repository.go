@@ -0,0 +1,181 @@
+package infrastructure
+
+import (
+	"encoding/csv"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"report/internal/domain"
+)
+
+// CSVAliasRepository implementa AliasRepository usando arquivos CSV
+type CSVAliasRepository struct {
+	aliasesPath   string
+	pendentesPath string
+}
+
+// NewCSVAliasRepository cria uma nova instância de CSVAliasRepository. Com
+// pendentesPath vazio, as sugestões não são lidas nem gravadas.
+func NewCSVAliasRepository(aliasesPath, pendentesPath string) *CSVAliasRepository {
+	return &CSVAliasRepository{
+		aliasesPath:   aliasesPath,
+		pendentesPath: pendentesPath,
+	}
+}
+
+// GetAll retorna a tabela de apelidos (apelido -> localidade)
+func (r *CSVAliasRepository) GetAll() (map[string]string, error) {
+	aliases := make(map[string]string)
+
+	records, err := readCSVIfExists(r.aliasesPath)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, record := range skipHeader(records) {
+		if len(record) < 2 {
+			continue
+		}
+		alias := normalizeLocalidade(record[0])
+		localidade := normalizeLocalidade(record[1])
+		if alias == "" || localidade == "" {
+			continue
+		}
+		aliases[alias] = localidade
+	}
+
+	return aliases, nil
+}
+
+// Save persiste um novo apelido na tabela. Apelidos já gravados para a mesma
+// localidade são ignorados, para que confirmações repetidas não dupliquem linhas.
+func (r *CSVAliasRepository) Save(alias, localidade string) error {
+	alias, localidade = normalizeLocalidade(alias), normalizeLocalidade(localidade)
+	aliases, err := r.GetAll()
+	if err != nil {
+		return err
+	}
+	if aliases[alias] == localidade {
+		return nil
+	}
+
+	_, err = os.Stat(r.aliasesPath)
+	novo := os.IsNotExist(err)
+
+	if err := os.MkdirAll(filepath.Dir(r.aliasesPath), os.ModePerm); err != nil {
+		return fmt.Errorf("erro ao criar diretório: %v", err)
+	}
+
+	file, err := os.OpenFile(r.aliasesPath, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0644)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	writer := csv.NewWriter(file)
+	if novo {
+		if err := writer.Write([]string{"alias", "localidade"}); err != nil {
+			return err
+		}
+	}
+	if err := writer.Write([]string{alias, localidade}); err != nil {
+		return err
+	}
+	writer.Flush()
+	return writer.Error()
+}
+
+// GetPendentes retorna as sugestões de apelidos aguardando confirmação
+func (r *CSVAliasRepository) GetPendentes() ([]domain.SugestaoAlias, error) {
+	if r.pendentesPath == "" {
+		return nil, nil
+	}
+
+	records, err := readCSVIfExists(r.pendentesPath)
+	if err != nil {
+		return nil, err
+	}
+
+	var sugestoes []domain.SugestaoAlias
+	for _, record := range skipHeader(records) {
+		if len(record) < 4 {
+			continue
+		}
+		similaridade, _ := strconv.ParseFloat(strings.TrimSpace(record[2]), 64)
+		confirmar := strings.ToUpper(strings.TrimSpace(record[3]))
+		sugestoes = append(sugestoes, domain.SugestaoAlias{
+			Alias:        normalizeLocalidade(record[0]),
+			Localidade:   normalizeLocalidade(record[1]),
+			Similaridade: similaridade,
+			Confirmado:   confirmar == "S" || confirmar == "SIM",
+		})
+	}
+
+	return sugestoes, nil
+}
+
+// SavePendentes grava as sugestões de apelidos para confirmação manual.
+// A coluna "confirmar" deve ser preenchida com S para que o apelido seja
+// incorporado na próxima execução.
+func (r *CSVAliasRepository) SavePendentes(sugestoes []domain.SugestaoAlias) error {
+	if r.pendentesPath == "" {
+		return nil
+	}
+
+	if len(sugestoes) == 0 {
+		if err := os.Remove(r.pendentesPath); err != nil && !os.IsNotExist(err) {
+			return err
+		}
+		return nil
+	}
+
+	if err := os.MkdirAll(filepath.Dir(r.pendentesPath), os.ModePerm); err != nil {
+		return fmt.Errorf("erro ao criar diretório: %v", err)
+	}
+
+	file, err := os.Create(r.pendentesPath)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	writer := csv.NewWriter(file)
+	if err := writer.Write([]string{"nome", "sugestao", "similaridade", "confirmar"}); err != nil {
+		return err
+	}
+	for _, s := range sugestoes {
+		confirmar := "N"
+		if s.Confirmado {
+			confirmar = "S"
+		}
+		record := []string{s.Alias, s.Localidade, strconv.FormatFloat(s.Similaridade, 'f', 2, 64), confirmar}
+		if err := writer.Write(record); err != nil {
+			return err
+		}
+	}
+	writer.Flush()
+	return writer.Error()
+}
+
+func readCSVIfExists(path string) ([][]string, error) {
+	arquivo, err := abrirCSV(path, CodificacaoAuto)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer arquivo.Close()
+
+	return arquivo.ReadAll()
+}
+
+func skipHeader(records [][]string) [][]string {
+	if len(records) == 0 {
+		return records
+	}
+	return records[1:]
+}
@@ -0,0 +1,70 @@
+package infrastructure
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestCSVAliasRepositorySave(t *testing.T) {
+	tests := []struct {
+		nome     string
+		gravados [][2]string
+		linhas   int
+		aliases  map[string]string
+	}{
+		{
+			nome:     "novo apelido",
+			gravados: [][2]string{{"Chácara Reunidas", "CHACARAS REUNIDAS"}},
+			linhas:   2,
+			aliases:  map[string]string{"CHACARA REUNIDAS": "CHACARAS REUNIDAS"},
+		},
+		{
+			nome: "apelido repetido não é duplicado",
+			gravados: [][2]string{
+				{"CHACARA REUNIDAS", "CHACARAS REUNIDAS"},
+				{"chacara  reunidas", "Chácaras Reunidas"},
+			},
+			linhas:  2,
+			aliases: map[string]string{"CHACARA REUNIDAS": "CHACARAS REUNIDAS"},
+		},
+		{
+			nome: "apelido associado a outra localidade prevalece",
+			gravados: [][2]string{
+				{"VL NOVA", "VILA NOVA"},
+				{"VL NOVA", "VILA NOVA II"},
+			},
+			linhas:  3,
+			aliases: map[string]string{"VL NOVA": "VILA NOVA II"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.nome, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "aliases.csv")
+			repo := NewCSVAliasRepository(path, "")
+			for _, g := range tt.gravados {
+				if err := repo.Save(g[0], g[1]); err != nil {
+					t.Fatalf("Save(%q, %q): %v", g[0], g[1], err)
+				}
+			}
+
+			conteudo, err := os.ReadFile(path)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if linhas := strings.Count(string(conteudo), "\n"); linhas != tt.linhas {
+				t.Errorf("%d linhas gravadas, esperado %d:\n%s", linhas, tt.linhas, conteudo)
+			}
+			aliases, err := repo.GetAll()
+			if err != nil {
+				t.Fatalf("GetAll: %v", err)
+			}
+			if !reflect.DeepEqual(aliases, tt.aliases) {
+				t.Errorf("GetAll = %v, esperado %v", aliases, tt.aliases)
+			}
+		})
+	}
+}
@@ -0,0 +1,70 @@
+package infrastructure
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"report/internal/domain"
+)
+
+// JSONBrigadaRepository implementa BrigadaRepository gravando o registro de
+// treinamentos da Brigada de Incêndio em um arquivo JSON
+type JSONBrigadaRepository struct {
+	path string
+}
+
+// NewJSONBrigadaRepository cria uma nova instância de JSONBrigadaRepository
+func NewJSONBrigadaRepository(path string) *JSONBrigadaRepository {
+	return &JSONBrigadaRepository{path: path}
+}
+
+// Get retorna o registro de treinamentos. Um arquivo inexistente equivale a
+// um registro vazio. Registros gravados por versões anteriores, com uma só
+// data por voluntário, também são aceitos.
+func (r *JSONBrigadaRepository) Get() (domain.RegistroBrigada, error) {
+	registro := make(domain.RegistroBrigada)
+	content, err := os.ReadFile(r.path)
+	if os.IsNotExist(err) {
+		return registro, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var gravado map[string]map[string]json.RawMessage
+	if err := json.Unmarshal(content, &gravado); err != nil {
+		return nil, fmt.Errorf("erro ao ler %s: %v", r.path, err)
+	}
+	for localidade, voluntarios := range gravado {
+		for voluntario, valor := range voluntarios {
+			var datas []time.Time
+			if err := json.Unmarshal(valor, &datas); err != nil {
+				var data time.Time
+				if err := json.Unmarshal(valor, &data); err != nil {
+					return nil, fmt.Errorf("erro ao ler %s: treinamento inválido em %s: %v", r.path, localidade, err)
+				}
+				datas = []time.Time{data}
+			}
+			for _, data := range datas {
+				registro.Registrar(localidade, voluntario, data)
+			}
+		}
+	}
+	return registro, nil
+}
+
+// Save grava o registro de treinamentos, substituindo o anterior
+func (r *JSONBrigadaRepository) Save(registro domain.RegistroBrigada) error {
+	if err := os.MkdirAll(filepath.Dir(r.path), os.ModePerm); err != nil {
+		return fmt.Errorf("erro ao criar diretório: %v", err)
+	}
+
+	content, err := json.MarshalIndent(registro, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(r.path, content, 0644)
+}
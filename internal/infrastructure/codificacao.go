@@ -0,0 +1,227 @@
+package infrastructure
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/csv"
+	"fmt"
+	"io"
+	"os"
+	"slices"
+	"strings"
+	"unicode/utf8"
+
+	"golang.org/x/text/encoding"
+	"golang.org/x/text/encoding/charmap"
+	"golang.org/x/text/encoding/unicode"
+	"golang.org/x/text/transform"
+)
+
+// Codificações aceitas para os arquivos CSV. Com CodificacaoAuto a codificação
+// é detectada pelo conteúdo: BOM, validade do UTF-8 e, por último,
+// Windows-1252 ou ISO-8859-1, usadas pelo Excel no Windows.
+const (
+	CodificacaoAuto        = "auto"
+	CodificacaoUTF8        = "utf-8"
+	CodificacaoUTF16LE     = "utf-16le"
+	CodificacaoUTF16BE     = "utf-16be"
+	CodificacaoWindows1252 = "windows-1252"
+	CodificacaoISO88591    = "iso-8859-1"
+)
+
+// apelidosCodificacao associa os nomes usuais às codificações aceitas
+var apelidosCodificacao = map[string]string{
+	"":             CodificacaoAuto,
+	"auto":         CodificacaoAuto,
+	"utf-8":        CodificacaoUTF8,
+	"utf8":         CodificacaoUTF8,
+	"utf-16le":     CodificacaoUTF16LE,
+	"utf-16be":     CodificacaoUTF16BE,
+	"windows-1252": CodificacaoWindows1252,
+	"cp1252":       CodificacaoWindows1252,
+	"iso-8859-1":   CodificacaoISO88591,
+	"latin1":       CodificacaoISO88591,
+	"latin-1":      CodificacaoISO88591,
+}
+
+// ParseCodificacao converte o nome informado pelo usuário em uma das
+// codificações aceitas
+func ParseCodificacao(valor string) (string, error) {
+	codificacao, exists := apelidosCodificacao[strings.ToLower(strings.TrimSpace(valor))]
+	if !exists {
+		return "", fmt.Errorf("codificação desconhecida: %s (use auto, utf-8, windows-1252 ou iso-8859-1)", valor)
+	}
+	return codificacao, nil
+}
+
+// delimitadores são os separadores de campo reconhecidos, em ordem de preferência
+var delimitadores = []rune{',', ';', '\t'}
+
+// leituraCSV descreve a codificação e o delimitador usados na leitura de um CSV
+type leituraCSV struct {
+	codificacao string
+	delimitador rune
+}
+
+// arquivoCSV é um leitor de CSV que converte o conteúdo para UTF-8
+type arquivoCSV struct {
+	*csv.Reader
+	formato leituraCSV
+	file    *os.File
+}
+
+func (a *arquivoCSV) Close() error {
+	return a.file.Close()
+}
+
+// abrirCSV abre um arquivo CSV na codificação informada, ou na detectada
+// quando ela é CodificacaoAuto, e detecta o delimitador pelas primeiras linhas
+func abrirCSV(path, codificacao string) (*arquivoCSV, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+
+	if codificacao == "" || codificacao == CodificacaoAuto {
+		if codificacao, err = detectarCodificacao(file); err != nil {
+			file.Close()
+			return nil, fmt.Errorf("erro ao detectar a codificação de %s: %v", path, err)
+		}
+		if _, err := file.Seek(0, io.SeekStart); err != nil {
+			file.Close()
+			return nil, err
+		}
+	}
+
+	decoder, err := decodificador(codificacao)
+	if err != nil {
+		file.Close()
+		return nil, err
+	}
+
+	buffer := bufio.NewReaderSize(transform.NewReader(file, decoder.NewDecoder()), 64*1024)
+	amostra, _ := buffer.Peek(buffer.Size())
+	delimitador := detectarDelimitador(amostra)
+
+	reader := csv.NewReader(buffer)
+	reader.Comma = delimitador
+	reader.FieldsPerRecord = -1
+	return &arquivoCSV{
+		Reader:  reader,
+		formato: leituraCSV{codificacao: codificacao, delimitador: delimitador},
+		file:    file,
+	}, nil
+}
+
+func decodificador(codificacao string) (encoding.Encoding, error) {
+	switch codificacao {
+	case CodificacaoUTF8:
+		// Remove o BOM, quando presente
+		return unicode.UTF8BOM, nil
+	case CodificacaoUTF16LE:
+		return unicode.UTF16(unicode.LittleEndian, unicode.UseBOM), nil
+	case CodificacaoUTF16BE:
+		return unicode.UTF16(unicode.BigEndian, unicode.UseBOM), nil
+	case CodificacaoWindows1252:
+		return charmap.Windows1252, nil
+	case CodificacaoISO88591:
+		return charmap.ISO8859_1, nil
+	}
+	return nil, fmt.Errorf("codificação desconhecida: %s", codificacao)
+}
+
+// detectarCodificacao identifica a codificação pelo BOM ou, sem ele,
+// percorrendo o arquivo: UTF-8 quando todo o conteúdo é válido; caso
+// contrário Windows-1252 se houver bytes entre 0x80 e 0x9F, que são
+// caracteres de controle em ISO-8859-1, e ISO-8859-1 se não houver
+func detectarCodificacao(r io.Reader) (string, error) {
+	buffer := bufio.NewReaderSize(r, 64*1024)
+	inicio, _ := buffer.Peek(3)
+	switch {
+	case bytes.HasPrefix(inicio, []byte{0xEF, 0xBB, 0xBF}):
+		return CodificacaoUTF8, nil
+	case bytes.HasPrefix(inicio, []byte{0xFF, 0xFE}):
+		return CodificacaoUTF16LE, nil
+	case bytes.HasPrefix(inicio, []byte{0xFE, 0xFF}):
+		return CodificacaoUTF16BE, nil
+	}
+
+	valido, controle := true, false
+	bloco := make([]byte, 64*1024)
+	var pendente []byte
+	for {
+		n, err := buffer.Read(bloco)
+		fim := err == io.EOF
+		if err != nil && !fim {
+			return "", err
+		}
+
+		dados := bloco[:n]
+		if pendente != nil {
+			dados = append(pendente, dados...)
+			pendente = nil
+		}
+		for _, b := range dados {
+			if b >= 0x80 && b <= 0x9F {
+				controle = true
+				break
+			}
+		}
+		for valido && len(dados) > 0 {
+			r, tamanho := utf8.DecodeRune(dados)
+			if r == utf8.RuneError && tamanho <= 1 {
+				// Um caractere dividido entre dois blocos é completado na
+				// próxima leitura
+				if !fim && !utf8.FullRune(dados) {
+					pendente = append([]byte(nil), dados...)
+					break
+				}
+				valido = false
+			}
+			dados = dados[tamanho:]
+		}
+
+		if fim || (!valido && controle) {
+			break
+		}
+	}
+
+	switch {
+	case valido:
+		return CodificacaoUTF8, nil
+	case controle:
+		return CodificacaoWindows1252, nil
+	default:
+		return CodificacaoISO88591, nil
+	}
+}
+
+// detectarDelimitador escolhe o separador mais frequente fora de aspas nas
+// primeiras linhas da amostra; o Excel em português grava CSV com ";"
+func detectarDelimitador(amostra []byte) rune {
+	const maxLinhas = 20
+
+	contagem := make(map[rune]int, len(delimitadores))
+	linhas, aspas := 0, false
+	for _, r := range string(amostra) {
+		switch {
+		case r == '"':
+			aspas = !aspas
+		case r == '\n' && !aspas:
+			linhas++
+		case !aspas && slices.Contains(delimitadores, r):
+			contagem[r]++
+		}
+		if linhas >= maxLinhas {
+			break
+		}
+	}
+
+	escolhido := delimitadores[0]
+	for _, d := range delimitadores[1:] {
+		if contagem[d] > contagem[escolhido] {
+			escolhido = d
+		}
+	}
+	return escolhido
+}
@@ -0,0 +1,140 @@
+package infrastructure
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// codificar converte o texto para a codificação informada
+func codificar(t *testing.T, texto, codificacao string) []byte {
+	t.Helper()
+	encoder, err := decodificador(codificacao)
+	if err != nil {
+		t.Fatal(err)
+	}
+	conteudo, err := encoder.NewEncoder().Bytes([]byte(texto))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return conteudo
+}
+
+func TestDetectarCodificacao(t *testing.T) {
+	// Um "ç" em UTF-8 dividido entre os dois primeiros blocos lidos
+	dividido := append(bytes.Repeat([]byte("a"), 64*1024-1), []byte("ção")...)
+
+	tests := []struct {
+		nome     string
+		conteudo []byte
+		esperado string
+	}{
+		{"ascii", []byte("VILA NOVA;Limpeza"), CodificacaoUTF8},
+		{"utf-8", []byte("São João;Manutenção"), CodificacaoUTF8},
+		{"utf-8 com BOM", append([]byte{0xEF, 0xBB, 0xBF}, "São João"...), CodificacaoUTF8},
+		{"utf-16le", append([]byte{0xFF, 0xFE}, "S\x00"...), CodificacaoUTF16LE},
+		{"utf-16be", append([]byte{0xFE, 0xFF}, "\x00S"...), CodificacaoUTF16BE},
+		{"windows-1252 com aspas tipográficas", codificar(t, "“São João”;Manutenção", CodificacaoWindows1252), CodificacaoWindows1252},
+		{"iso-8859-1", codificar(t, "São João;Manutenção", CodificacaoISO88591), CodificacaoISO88591},
+		{"utf-8 dividido entre blocos", dividido, CodificacaoUTF8},
+	}
+	for _, tt := range tests {
+		t.Run(tt.nome, func(t *testing.T) {
+			codificacao, err := detectarCodificacao(bytes.NewReader(tt.conteudo))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if codificacao != tt.esperado {
+				t.Errorf("codificação = %s, esperado %s", codificacao, tt.esperado)
+			}
+		})
+	}
+}
+
+func TestDetectarDelimitador(t *testing.T) {
+	tests := []struct {
+		nome     string
+		amostra  string
+		esperado rune
+	}{
+		{"vírgula", "a,b,c\n1,2,3\n", ','},
+		{"ponto e vírgula do Excel", "a;b;c\n1;2,5;3\n", ';'},
+		{"tabulação", "a\tb\tc\n", '\t'},
+		{"separadores entre aspas não contam", "\"a;b;c;d\",e\n\"1;2;3;4\",5\n", ','},
+		{"sem separadores", "abc\n", ','},
+	}
+	for _, tt := range tests {
+		t.Run(tt.nome, func(t *testing.T) {
+			if d := detectarDelimitador([]byte(tt.amostra)); d != tt.esperado {
+				t.Errorf("delimitador = %q, esperado %q", d, tt.esperado)
+			}
+		})
+	}
+}
+
+func TestParseCodificacao(t *testing.T) {
+	tests := []struct {
+		valor    string
+		esperado string
+		erro     bool
+	}{
+		{"", CodificacaoAuto, false},
+		{" UTF8 ", CodificacaoUTF8, false},
+		{"cp1252", CodificacaoWindows1252, false},
+		{"Latin1", CodificacaoISO88591, false},
+		{"ebcdic", "", true},
+	}
+	for _, tt := range tests {
+		codificacao, err := ParseCodificacao(tt.valor)
+		if (err != nil) != tt.erro || codificacao != tt.esperado {
+			t.Errorf("ParseCodificacao(%q) = %q, %v; esperado %q", tt.valor, codificacao, err, tt.esperado)
+		}
+	}
+}
+
+func TestAbrirCSV(t *testing.T) {
+	texto := "Localidade;Livro\nSÃO JOÃO;Manutenção\n"
+
+	tests := []struct {
+		nome        string
+		conteudo    []byte
+		codificacao string
+		formato     leituraCSV
+	}{
+		{"windows-1252 detectado", codificar(t, "“"+texto, CodificacaoWindows1252), CodificacaoAuto, leituraCSV{CodificacaoWindows1252, ';'}},
+		{"iso-8859-1 detectado", codificar(t, texto, CodificacaoISO88591), CodificacaoAuto, leituraCSV{CodificacaoISO88591, ';'}},
+		{"utf-16le com BOM", codificar(t, texto, CodificacaoUTF16LE), CodificacaoAuto, leituraCSV{CodificacaoUTF16LE, ';'}},
+		{"codificação informada", codificar(t, texto, CodificacaoISO88591), CodificacaoWindows1252, leituraCSV{CodificacaoWindows1252, ';'}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.nome, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "listagem.csv")
+			if err := os.WriteFile(path, tt.conteudo, 0644); err != nil {
+				t.Fatal(err)
+			}
+			arquivo, err := abrirCSV(path, tt.codificacao)
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer arquivo.Close()
+
+			if arquivo.formato != tt.formato {
+				t.Errorf("formato = %+v, esperado %+v", arquivo.formato, tt.formato)
+			}
+			records, err := arquivo.ReadAll()
+			if err != nil {
+				t.Fatal(err)
+			}
+			ultima := records[len(records)-1]
+			if esperado := []string{"SÃO JOÃO", "Manutenção"}; !reflect.DeepEqual(ultima, esperado) {
+				t.Errorf("linha = %q, esperado %q", ultima, esperado)
+			}
+			if !strings.HasSuffix(records[0][0], "Localidade") {
+				t.Errorf("cabeçalho = %q", records[0])
+			}
+		})
+	}
+}
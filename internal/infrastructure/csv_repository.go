@@ -1,20 +1,47 @@
 package infrastructure
 
 import (
-	"encoding/csv"
-	"os"
+	"fmt"
+	"log/slog"
+	"regexp"
+	"sort"
 	"strings"
+	"time"
 	"unicode"
 
 	"report/internal/domain"
+	"report/internal/usecase"
 
 	"golang.org/x/text/transform"
 	"golang.org/x/text/unicode/norm"
 )
 
-// CSVLocalidadeRepository implementa LocalidadeRepository
+// Motivos de descarte de linhas da listagem
+const (
+	motivoIncompleta  = "linha incompleta"
+	motivoSemLivro    = "livro vazio"
+	motivoForaPeriodo = "fora do período"
+)
+
+// linhasCabecalho é o número de linhas de cabeçalho da listagem exportada pelo portal
+const linhasCabecalho = 12
+
+// CSVLocalidadeRepository implementa LocalidadeRepository a partir da
+// listagem de horas exportada em CSV, XLS ou XLSX
 type CSVLocalidadeRepository struct {
-	inputPath string
+	inputPath   string
+	codificacao string
+	periodo     string
+	logger      *slog.Logger
+	indice      indiceArquivo[*listagem]
+	ultima      *listagem
+}
+
+// listagem é o resultado agregado de uma leitura da listagem de horas
+type listagem struct {
+	dados          map[string]map[string]*domain.Summary
+	estatisticas   domain.EstatisticasLeitura
+	administracoes map[string]string
 }
 
 // CSVSetorRepository implementa SetorRepository
@@ -25,13 +52,15 @@ type CSVSetorRepository struct {
 // CSVLivroRepository implementa LivroRepository
 type CSVLivroRepository struct {
 	booksPath string
+	logger    *slog.Logger
+	indice    indiceArquivo[map[string]map[string]bool]
 }
 
 // NewCSVRepositories cria novas instâncias dos repositórios
-func NewCSVRepositories(inputPath, booksPath string) (*CSVLocalidadeRepository, *CSVSetorRepository, *CSVLivroRepository) {
-	return &CSVLocalidadeRepository{inputPath: inputPath},
+func NewCSVRepositories(inputPath, booksPath string, logger *slog.Logger) (*CSVLocalidadeRepository, *CSVSetorRepository, *CSVLivroRepository) {
+	return &CSVLocalidadeRepository{inputPath: inputPath, codificacao: CodificacaoAuto, logger: logger.With("repositorio", "localidades")},
 		&CSVSetorRepository{setoresMap: initSetoresMap()},
-		&CSVLivroRepository{booksPath: booksPath}
+		&CSVLivroRepository{booksPath: booksPath, logger: logger.With("repositorio", "livros")}
 }
 
 func initSetoresMap() map[string]*domain.Setor {
@@ -113,41 +142,171 @@ func initSetoresMap() map[string]*domain.Setor {
 	return setores
 }
 
-// GetAll retorna todas as localidades
+// GetAll retorna todas as localidades. A listagem é lida uma única vez
+// enquanto o arquivo não muda; os mapas retornados são compartilhados e não
+// devem ser alterados.
 func (r *CSVLocalidadeRepository) GetAll() (map[string]map[string]*domain.Summary, error) {
-	file, err := os.Open(r.inputPath)
+	l, err := r.indice.obter(r.inputPath, r.ler)
 	if err != nil {
 		return nil, err
 	}
-	defer file.Close()
+	r.ultima = l
+	return l.dados, nil
+}
 
-	reader := csv.NewReader(file)
-	reader.Comma = ','
-	records, err := reader.ReadAll()
-	if err != nil {
-		return nil, err
+// ler percorre a listagem linha a linha, agregando os lançamentos por
+// localidade e livro sem manter as linhas em memória. Com um período
+// definido, os lançamentos datados de outros meses são ignorados.
+func (r *CSVLocalidadeRepository) ler() (*listagem, error) {
+	inicio := time.Now()
+	var primeira, ultima time.Time
+	l := &listagem{
+		dados:          make(map[string]map[string]*domain.Summary),
+		estatisticas:   domain.EstatisticasLeitura{Ignoradas: make(map[string]int)},
+		administracoes: make(map[string]string),
 	}
 
-	data := make(map[string]map[string]*domain.Summary)
-	for _, record := range records[12:] {
-		localidade := extractMiddleName(removeAccents(record[0]))
-		livro := strings.TrimSpace(record[2])
+	// As linhas de cabeçalho não entram nas estatísticas, de modo que as
+	// linhas lidas são sempre as válidas mais as ignoradas
+	var colunas colunasListagem
+	linha := 0
+	formato, err := percorrerRegistros(r.inputPath, r.codificacao, func(record []string) error {
+		linha++
+		if linha <= linhasCabecalho {
+			if linha == linhasCabecalho {
+				colunas = detectarColunas(record)
+			}
+			return nil
+		}
+		l.estatisticas.LinhasLidas++
+
+		if len(record) <= colunas.livro {
+			r.ignorar(l, motivoIncompleta, linha)
+			return nil
+		}
+
+		nomeCompleto := removeAccents(colunas.valor(record, colunas.localidade))
+		localidade := extractMiddleName(nomeCompleto)
+		livro := colunas.valor(record, colunas.livro)
 		if livro == "" {
-			continue
+			r.ignorar(l, motivoSemLivro, linha)
+			return nil
+		}
+		data, comData := colunas.dataLancamento(record)
+		if comData && r.periodo != "" && data.Format(domain.LayoutPeriodo) != r.periodo {
+			if primeira.IsZero() || data.Before(primeira) {
+				primeira = data
+			}
+			if data.After(ultima) {
+				ultima = data
+			}
+			r.ignorar(l, motivoForaPeriodo, linha)
+			return nil
 		}
 
-		if _, exists := data[localidade]; !exists {
-			data[localidade] = make(map[string]*domain.Summary)
+		// As chaves são copiadas para não reter a linha inteira lida do arquivo
+		livros, exists := l.dados[localidade]
+		if !exists {
+			localidade = strings.Clone(localidade)
+			livros = make(map[string]*domain.Summary)
+			l.dados[localidade] = livros
+		}
+		if _, exists := l.administracoes[localidade]; !exists {
+			if administracao := extractAdministracao(nomeCompleto); administracao != "" {
+				l.administracoes[localidade] = administracao
+			}
 		}
 
-		if _, exists := data[localidade][livro]; !exists {
-			data[localidade][livro] = &domain.Summary{}
+		summary, exists := livros[livro]
+		if !exists {
+			summary = &domain.Summary{
+				Voluntarios:                 make(map[string]int),
+				HorasVoluntarios:            make(map[string]float64),
+				UltimoLancamentoVoluntarios: make(map[string]time.Time),
+				LancamentosDia:              make(map[string]int),
+				HorasDia:                    make(map[string]float64),
+			}
+			livros[strings.Clone(livro)] = summary
 		}
+		horas := colunas.horasTrabalhadas(record)
+		summary.TotalTrabalhos++
+		summary.Horas += horas
+		if comData {
+			if data.After(summary.UltimoLancamento) {
+				summary.UltimoLancamento = data
+			}
+			dia := data.Format(domain.LayoutDia)
+			summary.LancamentosDia[dia]++
+			summary.HorasDia[dia] += horas
+		}
+		if voluntario := strings.ToUpper(colunas.valor(record, colunas.voluntario)); voluntario != "" {
+			if _, exists := summary.Voluntarios[voluntario]; !exists {
+				voluntario = strings.Clone(voluntario)
+			}
+			summary.Voluntarios[voluntario]++
+			summary.HorasVoluntarios[voluntario] += horas
+			if comData && data.After(summary.UltimoLancamentoVoluntarios[voluntario]) {
+				summary.UltimoLancamentoVoluntarios[voluntario] = data
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	if len(l.dados) == 0 && l.estatisticas.Ignoradas[motivoForaPeriodo] > 0 {
+		return nil, fmt.Errorf("nenhum lançamento de %s em %s: a listagem vai de %s a %s",
+			r.periodo, r.inputPath, primeira.Format(domain.LayoutDia), ultima.Format(domain.LayoutDia))
+	}
+	if formato.codificacao != "" {
+		l.estatisticas.Codificacao = formato.codificacao
+		l.estatisticas.Delimitador = string(formato.delimitador)
+	}
+
+	r.logger.Info("listagem lida",
+		"arquivo", r.inputPath,
+		"codificacao", l.estatisticas.Codificacao,
+		"linhas", l.estatisticas.LinhasLidas,
+		"ignoradas", l.estatisticas.TotalIgnoradas(),
+		"localidades", len(l.dados),
+		"duracao", time.Since(inicio))
 
-		data[localidade][livro].TotalTrabalhos++
+	return l, nil
+}
+
+// SetCodificacao define a codificação da listagem em CSV, substituindo a
+// detecção automática
+func (r *CSVLocalidadeRepository) SetCodificacao(codificacao string) {
+	r.codificacao = codificacao
+}
+
+// SetPeriodo restringe a leitura aos lançamentos do período (AAAA-MM);
+// lançamentos sem data são mantidos. Vazio, a listagem é lida por inteiro.
+func (r *CSVLocalidadeRepository) SetPeriodo(periodo string) {
+	r.indice.invalidar()
+	r.periodo = periodo
+}
+
+// Estatisticas retorna o resumo da última leitura da listagem
+func (r *CSVLocalidadeRepository) Estatisticas() domain.EstatisticasLeitura {
+	if r.ultima == nil {
+		return domain.EstatisticasLeitura{}
+	}
+	return r.ultima.estatisticas
+}
+
+// Administracoes retorna a administração de cada localidade da última leitura,
+// indicada após o nome da localidade na listagem
+func (r *CSVLocalidadeRepository) Administracoes() map[string]string {
+	if r.ultima == nil {
+		return nil
 	}
+	return r.ultima.administracoes
+}
 
-	return data, nil
+func (r *CSVLocalidadeRepository) ignorar(l *listagem, motivo string, linha int) {
+	l.estatisticas.Ignoradas[motivo]++
+	r.logger.Debug("linha ignorada", "arquivo", r.inputPath, "linha", linha, "motivo", motivo)
 }
 
 // Save implementa a interface LocalidadeRepository
@@ -168,38 +327,100 @@ func (r *CSVSetorRepository) GetByLocalidade(localidade string) (*domain.Setor,
 	return nil, nil
 }
 
-// GetAll retorna todos os livros
-func (r *CSVLivroRepository) GetAll() (map[string]map[string]bool, error) {
-	file, err := os.Open(r.booksPath)
-	if err != nil {
-		return nil, err
+// GetAdministracoes retorna as administrações com os seus setores. Setores
+// sem administração cadastrada ficam de fora.
+func (r *CSVSetorRepository) GetAdministracoes() (map[string]*domain.Administracao, error) {
+	administracoes := make(map[string]*domain.Administracao)
+	vistos := make(map[*domain.Setor]bool)
+	for _, localidade := range usecase.OrdenarChaves(r.setoresMap) {
+		setor := r.setoresMap[localidade]
+		if vistos[setor] {
+			continue
+		}
+		vistos[setor] = true
+		if setor.Administracao == "" {
+			continue
+		}
+
+		administracao, exists := administracoes[setor.Administracao]
+		if !exists {
+			administracao = &domain.Administracao{Nome: setor.Administracao}
+			administracoes[setor.Administracao] = administracao
+		}
+		administracao.Setores = append(administracao.Setores, setor)
 	}
-	defer file.Close()
 
-	reader := csv.NewReader(file)
-	reader.Comma = ','
-	records, err := reader.ReadAll()
+	for _, administracao := range administracoes {
+		sort.Slice(administracao.Setores, func(i, j int) bool {
+			return administracao.Setores[i].Nome < administracao.Setores[j].Nome
+		})
+	}
+	return administracoes, nil
+}
+
+// LoadAdministracoes lê a administração de cada setor de um CSV com as
+// colunas setor e administracao. Um arquivo inexistente não é considerado
+// erro; os setores ficam sem administração cadastrada.
+func (r *CSVSetorRepository) LoadAdministracoes(path string) error {
+	records, err := readCSVIfExists(path)
 	if err != nil {
-		return nil, err
+		return fmt.Errorf("erro ao ler administrações: %v", err)
 	}
 
-	booksMap := make(map[string]map[string]bool)
-	for _, record := range records[12:] {
-		livro := strings.TrimSpace(record[0])
-		localidade := strings.TrimSpace(removeAccents(record[2]))
+	porNome := make(map[string]*domain.Setor)
+	for _, setor := range r.setoresMap {
+		porNome[strings.ToUpper(setor.Nome)] = setor
+	}
 
-		if livro == "" {
+	for _, record := range skipHeader(records) {
+		if len(record) < 2 {
 			continue
 		}
-
-		if _, exists := booksMap[localidade]; !exists {
-			booksMap[localidade] = make(map[string]bool)
+		nome := strings.ToUpper(strings.TrimSpace(record[0]))
+		setor, exists := porNome[nome]
+		if !exists {
+			return fmt.Errorf("setor desconhecido nas administrações: %s", record[0])
 		}
+		setor.Administracao = normalizeLocalidade(record[1])
+	}
+	return nil
+}
+
+// LoadContatos lê os e-mails de cada setor de um CSV com as colunas setor e
+// email, uma linha por contato. Um arquivo inexistente não é considerado erro.
+func (r *CSVSetorRepository) LoadContatos(path string) error {
+	records, err := readCSVIfExists(path)
+	if err != nil {
+		return fmt.Errorf("erro ao ler contatos: %v", err)
+	}
 
-		booksMap[localidade][livro] = true
+	porNome := make(map[string]*domain.Setor)
+	for _, setor := range r.setoresMap {
+		porNome[strings.ToUpper(setor.Nome)] = setor
 	}
 
-	return booksMap, nil
+	for _, record := range skipHeader(records) {
+		if len(record) < 2 {
+			continue
+		}
+		nome := strings.ToUpper(strings.TrimSpace(record[0]))
+		email := strings.TrimSpace(record[1])
+		setor, exists := porNome[nome]
+		if !exists {
+			return fmt.Errorf("setor desconhecido nos contatos: %s", record[0])
+		}
+		if email != "" {
+			setor.Emails = append(setor.Emails, email)
+		}
+	}
+	return nil
+}
+
+// GetAll retorna todos os livros. O catálogo é lido uma única vez enquanto o
+// arquivo não muda e o índice é compartilhado com GetByLocalidade; o mapa
+// retornado não deve ser alterado.
+func (r *CSVLivroRepository) GetAll() (map[string]map[string]bool, error) {
+	return r.indice.obter(r.booksPath, r.ler)
 }
 
 // GetByLocalidade retorna os livros de uma localidade
@@ -211,6 +432,50 @@ func (r *CSVLivroRepository) GetByLocalidade(localidade string) (map[string]bool
 	return allBooks[localidade], nil
 }
 
+// ler monta o índice de livros por localidade a partir do catálogo. O
+// catálogo tem uma única linha de cabeçalho (livro,codigo,localidade); as
+// linhas seguintes são todas cadastros, diferente da listagem de horas, que
+// tem linhasCabecalho linhas antes dos dados.
+func (r *CSVLivroRepository) ler() (map[string]map[string]bool, error) {
+	inicio := time.Now()
+
+	booksMap := make(map[string]map[string]bool)
+	cabecalho := true
+	formato, err := percorrerCSV(r.booksPath, CodificacaoAuto, func(record []string) error {
+		if cabecalho {
+			cabecalho = false
+			return nil
+		}
+		if len(record) < 3 {
+			return nil
+		}
+		livro := strings.TrimSpace(record[0])
+		localidade := normalizeLocalidade(record[2])
+
+		if livro == "" || localidade == "" {
+			return nil
+		}
+
+		if _, exists := booksMap[localidade]; !exists {
+			booksMap[localidade] = make(map[string]bool)
+		}
+
+		booksMap[localidade][strings.Clone(livro)] = true
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	r.logger.Debug("catálogo de livros lido",
+		"arquivo", r.booksPath,
+		"localidades", len(booksMap),
+		"codificacao", formato.codificacao,
+		"duracao", time.Since(inicio))
+
+	return booksMap, nil
+}
+
 func removeAccents(input string) string {
 	t := transform.Chain(norm.NFD, transform.RemoveFunc(isNonSpacingMark), norm.NFC)
 	result, _, _ := transform.String(t, input)
@@ -228,3 +493,22 @@ func extractMiddleName(localidade string) string {
 	}
 	return localidade
 }
+
+// codigoImovel identifica o código das casas de oração ("BR 21-0196")
+var codigoImovel = regexp.MustCompile(`^[A-Z]{2} \d{2}-\d{4}$`)
+
+// extractAdministracao retorna a administração indicada na última parte do
+// nome da localidade ("BR 21-0196 - PARQUE GRAJAU - SANTO AMARO"). Nomes sem
+// o código do imóvel, como os lançamentos do setor ("SET - IPORANGA - SP"),
+// não indicam administração.
+func extractAdministracao(localidade string) string {
+	parts := strings.Split(localidade, " - ")
+	if len(parts) > 2 && codigoImovel.MatchString(strings.TrimSpace(parts[0])) {
+		return normalizeLocalidade(parts[len(parts)-1])
+	}
+	return ""
+}
+
+func normalizeLocalidade(localidade string) string {
+	return strings.ToUpper(strings.Join(strings.Fields(removeAccents(localidade)), " "))
+}
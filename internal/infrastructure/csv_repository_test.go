@@ -0,0 +1,81 @@
+package infrastructure
+
+import (
+	"io"
+	"log/slog"
+	"strings"
+	"testing"
+)
+
+func TestLerListagemPeriodo(t *testing.T) {
+	// Com 1680 lançamentos, cada mês de 2021 a 2025 recebe 28
+	path := gerarListagemCSV(t, 1680)
+
+	tests := []struct {
+		periodo     string
+		validas     int
+		foraPeriodo int
+		erro        string
+	}{
+		{periodo: "", validas: 1680},
+		{periodo: "2023-03", validas: 28, foraPeriodo: 1652},
+		{periodo: "2025-12", validas: 28, foraPeriodo: 1652},
+		{periodo: "2020-01", erro: "a listagem vai de 2021-01-01 a 2025-12-28"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.periodo, func(t *testing.T) {
+			repo, _, _ := NewCSVRepositories(path, "", slog.New(slog.NewTextHandler(io.Discard, nil)))
+			repo.SetPeriodo(tt.periodo)
+			dados, err := repo.GetAll()
+			if tt.erro != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.erro) {
+					t.Fatalf("erro = %v, esperado %q", err, tt.erro)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("GetAll: %v", err)
+			}
+
+			estatisticas := repo.Estatisticas()
+			if n := estatisticas.LinhasValidas(); n != tt.validas {
+				t.Errorf("LinhasValidas = %d, esperado %d", n, tt.validas)
+			}
+			if n := estatisticas.Ignoradas[motivoForaPeriodo]; n != tt.foraPeriodo {
+				t.Errorf("Ignoradas[%q] = %d, esperado %d", motivoForaPeriodo, n, tt.foraPeriodo)
+			}
+			total := 0
+			for _, livros := range dados {
+				for _, summary := range livros {
+					total += summary.TotalTrabalhos
+					if tt.periodo == "" {
+						continue
+					}
+					for dia := range summary.LancamentosDia {
+						if !strings.HasPrefix(dia, tt.periodo) {
+							t.Errorf("lançamento de %s no período %s", dia, tt.periodo)
+						}
+					}
+				}
+			}
+			if total != tt.validas {
+				t.Errorf("%d trabalhos agregados, esperado %d", total, tt.validas)
+			}
+		})
+	}
+}
+
+func TestSetPeriodoReleListagem(t *testing.T) {
+	path := gerarListagemCSV(t, 1680)
+	repo, _, _ := NewCSVRepositories(path, "", slog.New(slog.NewTextHandler(io.Discard, nil)))
+	if _, err := repo.GetAll(); err != nil {
+		t.Fatal(err)
+	}
+	repo.SetPeriodo("2022-07")
+	if _, err := repo.GetAll(); err != nil {
+		t.Fatal(err)
+	}
+	if n := repo.Estatisticas().LinhasValidas(); n != 28 {
+		t.Errorf("LinhasValidas = %d depois de SetPeriodo, esperado 28", n)
+	}
+}
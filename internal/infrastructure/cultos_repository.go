@@ -0,0 +1,47 @@
+package infrastructure
+
+import (
+	"fmt"
+
+	"report/internal/domain"
+)
+
+// CSVAgendaCultosRepository implementa AgendaCultosRepository a partir de um
+// CSV com as colunas localidade e dias, como "domingo quarta"
+type CSVAgendaCultosRepository struct {
+	cultosPath string
+}
+
+// NewCSVAgendaCultosRepository cria uma nova instância de CSVAgendaCultosRepository
+func NewCSVAgendaCultosRepository(cultosPath string) *CSVAgendaCultosRepository {
+	return &CSVAgendaCultosRepository{cultosPath: cultosPath}
+}
+
+// GetAll retorna as agendas cadastradas. Um arquivo inexistente não é
+// considerado erro: sem agenda, o calendário destaca apenas os fins de semana.
+func (r *CSVAgendaCultosRepository) GetAll() ([]domain.AgendaCultos, error) {
+	records, err := readCSVIfExists(r.cultosPath)
+	if err != nil {
+		return nil, fmt.Errorf("erro ao ler agenda de cultos: %v", err)
+	}
+
+	var agendas []domain.AgendaCultos
+	for i, record := range skipHeader(records) {
+		if len(record) < 2 {
+			continue
+		}
+		dias, err := domain.ParseDiasSemana(record[1])
+		if err != nil {
+			return nil, fmt.Errorf("linha %d de %s: %v", i+2, r.cultosPath, err)
+		}
+		if len(dias) == 0 {
+			continue
+		}
+		agendas = append(agendas, domain.AgendaCultos{
+			Localidade: normalizeLocalidade(record[0]),
+			Dias:       dias,
+		})
+	}
+
+	return agendas, nil
+}
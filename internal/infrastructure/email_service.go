@@ -0,0 +1,206 @@
+package infrastructure
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"crypto/tls"
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"log/slog"
+	"mime"
+	"net"
+	"net/smtp"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"report/internal/usecase"
+)
+
+// SMTPConfig define o servidor usado no envio das mensagens
+type SMTPConfig struct {
+	Host    string
+	Porta   int
+	Usuario string
+	Senha   string
+}
+
+// SMTPEmailService implementa EmailService enviando as mensagens por SMTP.
+// A conexão usa STARTTLS quando o servidor oferece; a autenticação só é
+// feita com usuário informado.
+type SMTPEmailService struct {
+	config SMTPConfig
+	logger *slog.Logger
+}
+
+// NewSMTPEmailService cria uma nova instância de SMTPEmailService
+func NewSMTPEmailService(config SMTPConfig, logger *slog.Logger) *SMTPEmailService {
+	return &SMTPEmailService{config: config, logger: logger.With("servico", "smtp")}
+}
+
+// Send envia a mensagem ao servidor SMTP configurado
+func (s *SMTPEmailService) Send(ctx context.Context, mensagem *usecase.Mensagem) error {
+	conteudo, err := buildMensagem(mensagem)
+	if err != nil {
+		return err
+	}
+
+	addr := net.JoinHostPort(s.config.Host, strconv.Itoa(s.config.Porta))
+	var dialer net.Dialer
+	conn, err := dialer.DialContext(ctx, "tcp", addr)
+	if err != nil {
+		return fmt.Errorf("erro ao conectar ao servidor SMTP: %v", err)
+	}
+	if deadline, ok := ctx.Deadline(); ok {
+		conn.SetDeadline(deadline)
+	}
+
+	client, err := smtp.NewClient(conn, s.config.Host)
+	if err != nil {
+		conn.Close()
+		return fmt.Errorf("erro ao iniciar sessão SMTP: %v", err)
+	}
+	defer client.Close()
+
+	if ok, _ := client.Extension("STARTTLS"); ok {
+		if err := client.StartTLS(&tls.Config{ServerName: s.config.Host}); err != nil {
+			return fmt.Errorf("erro ao iniciar TLS: %v", err)
+		}
+	}
+	if s.config.Usuario != "" {
+		auth := smtp.PlainAuth("", s.config.Usuario, s.config.Senha, s.config.Host)
+		if err := client.Auth(auth); err != nil {
+			return fmt.Errorf("erro na autenticação SMTP: %v", err)
+		}
+	}
+
+	if err := client.Mail(enderecoEmail(mensagem.De)); err != nil {
+		return fmt.Errorf("remetente recusado: %v", err)
+	}
+	for _, para := range mensagem.Para {
+		if err := client.Rcpt(enderecoEmail(para)); err != nil {
+			return fmt.Errorf("destinatário %s recusado: %v", para, err)
+		}
+	}
+
+	writer, err := client.Data()
+	if err != nil {
+		return fmt.Errorf("erro ao enviar mensagem: %v", err)
+	}
+	if _, err := writer.Write(conteudo); err != nil {
+		return fmt.Errorf("erro ao enviar mensagem: %v", err)
+	}
+	if err := writer.Close(); err != nil {
+		return fmt.Errorf("erro ao enviar mensagem: %v", err)
+	}
+
+	s.logger.Debug("mensagem enviada", "para", strings.Join(mensagem.Para, ", "), "tamanho", len(conteudo))
+	return client.Quit()
+}
+
+// EMLEmailService implementa EmailService gravando cada mensagem em um
+// arquivo .eml, usado para conferir as mensagens antes do envio
+type EMLEmailService struct {
+	dir string
+}
+
+// NewEMLEmailService cria uma nova instância de EMLEmailService
+func NewEMLEmailService(dir string) *EMLEmailService {
+	return &EMLEmailService{dir: dir}
+}
+
+// Send grava a mensagem em <dir>/<assunto>.eml
+func (s *EMLEmailService) Send(ctx context.Context, mensagem *usecase.Mensagem) error {
+	conteudo, err := buildMensagem(mensagem)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(s.dir, os.ModePerm); err != nil {
+		return fmt.Errorf("erro ao criar diretório: %v", err)
+	}
+
+	nome := strings.Map(func(r rune) rune {
+		if strings.ContainsRune(`/\:*?"<>|`, r) {
+			return '_'
+		}
+		return r
+	}, mensagem.Assunto)
+	return os.WriteFile(filepath.Join(s.dir, nome+".eml"), conteudo, 0644)
+}
+
+// buildMensagem monta a mensagem MIME com o corpo em texto e os anexos em base64
+func buildMensagem(mensagem *usecase.Mensagem) ([]byte, error) {
+	limite := make([]byte, 12)
+	rand.Read(limite)
+	boundary := "relatorios-" + hex.EncodeToString(limite)
+
+	var b bytes.Buffer
+	fmt.Fprintf(&b, "From: %s\r\n", mensagem.De)
+	fmt.Fprintf(&b, "To: %s\r\n", strings.Join(mensagem.Para, ", "))
+	fmt.Fprintf(&b, "Subject: %s\r\n", mime.QEncoding.Encode("utf-8", mensagem.Assunto))
+	fmt.Fprintf(&b, "Date: %s\r\n", time.Now().Format(time.RFC1123Z))
+	fmt.Fprintf(&b, "Message-ID: %s\r\n", messageID(mensagem.De))
+	b.WriteString("MIME-Version: 1.0\r\n")
+	fmt.Fprintf(&b, "Content-Type: multipart/mixed; boundary=%q\r\n\r\n", boundary)
+
+	fmt.Fprintf(&b, "--%s\r\n", boundary)
+	b.WriteString("Content-Type: text/plain; charset=utf-8\r\n")
+	b.WriteString("Content-Transfer-Encoding: base64\r\n\r\n")
+	writeBase64(&b, []byte(strings.ReplaceAll(mensagem.Corpo, "\n", "\r\n")))
+
+	for _, anexo := range mensagem.Anexos {
+		conteudo, err := os.ReadFile(anexo)
+		if err != nil {
+			return nil, fmt.Errorf("erro ao ler anexo %s: %v", anexo, err)
+		}
+		nome := mime.QEncoding.Encode("utf-8", filepath.Base(anexo))
+		tipo := mime.TypeByExtension(filepath.Ext(anexo))
+		if tipo == "" {
+			tipo = "application/octet-stream"
+		}
+
+		fmt.Fprintf(&b, "--%s\r\n", boundary)
+		fmt.Fprintf(&b, "Content-Type: %s; name=\"%s\"\r\n", tipo, nome)
+		b.WriteString("Content-Transfer-Encoding: base64\r\n")
+		fmt.Fprintf(&b, "Content-Disposition: attachment; filename=\"%s\"\r\n\r\n", nome)
+		writeBase64(&b, conteudo)
+	}
+	fmt.Fprintf(&b, "--%s--\r\n", boundary)
+
+	return b.Bytes(), nil
+}
+
+// messageID gera um identificador único para a mensagem no domínio do remetente
+func messageID(de string) string {
+	aleatorio := make([]byte, 16)
+	rand.Read(aleatorio)
+	dominio := "localhost"
+	if _, depois, ok := strings.Cut(enderecoEmail(de), "@"); ok && depois != "" {
+		dominio = depois
+	}
+	return fmt.Sprintf("<%d.%s@%s>", time.Now().UnixNano(), hex.EncodeToString(aleatorio), dominio)
+}
+
+// writeBase64 codifica o conteúdo em linhas de 76 caracteres
+func writeBase64(b *bytes.Buffer, conteudo []byte) {
+	codificado := base64.StdEncoding.EncodeToString(conteudo)
+	for len(codificado) > 76 {
+		b.WriteString(codificado[:76] + "\r\n")
+		codificado = codificado[76:]
+	}
+	b.WriteString(codificado + "\r\n")
+}
+
+// enderecoEmail extrai o endereço de "Nome <endereco>"
+func enderecoEmail(contato string) string {
+	if inicio := strings.LastIndex(contato, "<"); inicio >= 0 {
+		if fim := strings.LastIndex(contato, ">"); fim > inicio {
+			return contato[inicio+1 : fim]
+		}
+	}
+	return strings.TrimSpace(contato)
+}
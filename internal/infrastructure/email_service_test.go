@@ -0,0 +1,197 @@
+package infrastructure
+
+import (
+	"bufio"
+	"context"
+	"io"
+	"log/slog"
+	"mime"
+	"net"
+	"net/mail"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"report/internal/usecase"
+)
+
+// servidorSMTP é um servidor SMTP mínimo que aceita uma sessão e guarda o
+// envelope e o conteúdo da mensagem recebida
+type servidorSMTP struct {
+	listener net.Listener
+	de       string
+	para     []string
+	dados    string
+	comandos []string
+	feito    chan struct{}
+}
+
+func novoServidorSMTP(t *testing.T) *servidorSMTP {
+	t.Helper()
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("erro ao abrir servidor SMTP: %v", err)
+	}
+	s := &servidorSMTP{listener: listener, feito: make(chan struct{})}
+	t.Cleanup(func() { listener.Close() })
+	go s.atender()
+	return s
+}
+
+func (s *servidorSMTP) porta() int {
+	return s.listener.Addr().(*net.TCPAddr).Port
+}
+
+func (s *servidorSMTP) atender() {
+	defer close(s.feito)
+	conn, err := s.listener.Accept()
+	if err != nil {
+		return
+	}
+	defer conn.Close()
+	conn.SetDeadline(time.Now().Add(5 * time.Second))
+
+	leitor := bufio.NewReader(conn)
+	responder := func(linha string) {
+		io.WriteString(conn, linha+"\r\n")
+	}
+	responder("220 teste ESMTP")
+	for {
+		linha, err := leitor.ReadString('\n')
+		if err != nil {
+			return
+		}
+		comando := strings.TrimRight(linha, "\r\n")
+		s.comandos = append(s.comandos, comando)
+		verbo := strings.ToUpper(strings.SplitN(comando, " ", 2)[0])
+		switch {
+		case verbo == "EHLO" || verbo == "HELO":
+			responder("250 teste")
+		case strings.HasPrefix(strings.ToUpper(comando), "MAIL FROM:"):
+			s.de = strings.Trim(comando[len("MAIL FROM:"):], "<> ")
+			responder("250 ok")
+		case strings.HasPrefix(strings.ToUpper(comando), "RCPT TO:"):
+			s.para = append(s.para, strings.Trim(comando[len("RCPT TO:"):], "<> "))
+			responder("250 ok")
+		case verbo == "DATA":
+			responder("354 envie a mensagem")
+			var dados strings.Builder
+			for {
+				linha, err := leitor.ReadString('\n')
+				if err != nil {
+					return
+				}
+				if linha == ".\r\n" {
+					break
+				}
+				dados.WriteString(linha)
+			}
+			s.dados = dados.String()
+			responder("250 ok")
+		case verbo == "QUIT":
+			responder("221 até logo")
+			return
+		default:
+			responder("502 comando não implementado")
+		}
+	}
+}
+
+func TestSMTPEmailServiceSend(t *testing.T) {
+	servidor := novoServidorSMTP(t)
+
+	anexo := filepath.Join(t.TempDir(), "Setor 9.1_2025-02.zip")
+	if err := os.WriteFile(anexo, []byte("conteúdo do pacote"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	service := NewSMTPEmailService(SMTPConfig{Host: "127.0.0.1", Porta: servidor.porta()}, slog.New(slog.NewTextHandler(io.Discard, nil)))
+	mensagem := &usecase.Mensagem{
+		De:      "Relatórios <relatorios@example.org>",
+		Para:    []string{"Responsável <responsavel91@example.org>", "secretaria@example.org"},
+		Assunto: "Relatórios do Setor 9.1 - 2025-02",
+		Corpo:   "Seguem em anexo os relatórios.\n",
+		Anexos:  []string{anexo},
+	}
+	if err := service.Send(context.Background(), mensagem); err != nil {
+		t.Fatalf("Send: %v", err)
+	}
+
+	select {
+	case <-servidor.feito:
+	case <-time.After(5 * time.Second):
+		t.Fatal("o servidor não concluiu a sessão")
+	}
+
+	if servidor.de != "relatorios@example.org" {
+		t.Errorf("MAIL FROM = %q, esperado relatorios@example.org", servidor.de)
+	}
+	if got := strings.Join(servidor.para, ","); got != "responsavel91@example.org,secretaria@example.org" {
+		t.Errorf("RCPT TO = %q", got)
+	}
+
+	recebida, err := mail.ReadMessage(strings.NewReader(servidor.dados))
+	if err != nil {
+		t.Fatalf("mensagem recebida inválida: %v", err)
+	}
+	if got := recebida.Header.Get("From"); got != mensagem.De {
+		t.Errorf("From = %q, esperado %q", got, mensagem.De)
+	}
+	id := recebida.Header.Get("Message-ID")
+	if !strings.HasPrefix(id, "<") || !strings.HasSuffix(id, "@example.org>") {
+		t.Errorf("Message-ID = %q, esperado <...@example.org>", id)
+	}
+	assunto, err := new(mime.WordDecoder).DecodeHeader(recebida.Header.Get("Subject"))
+	if err != nil || assunto != mensagem.Assunto {
+		t.Errorf("Subject = %q (%v), esperado %q", assunto, err, mensagem.Assunto)
+	}
+	if !strings.Contains(servidor.dados, `filename="Setor 9.1_2025-02.zip"`) {
+		t.Error("anexo ausente da mensagem")
+	}
+	if ultimo := servidor.comandos[len(servidor.comandos)-1]; ultimo != "QUIT" {
+		t.Errorf("último comando = %q, esperado QUIT", ultimo)
+	}
+}
+
+func TestSMTPEmailServiceSendRecusado(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer listener.Close()
+	go func() {
+		conn, err := listener.Accept()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+		leitor := bufio.NewReader(conn)
+		io.WriteString(conn, "220 teste ESMTP\r\n")
+		for {
+			linha, err := leitor.ReadString('\n')
+			if err != nil {
+				return
+			}
+			if strings.HasPrefix(strings.ToUpper(linha), "MAIL FROM:") {
+				io.WriteString(conn, "550 remetente não autorizado\r\n")
+				continue
+			}
+			io.WriteString(conn, "250 ok\r\n")
+		}
+	}()
+
+	service := NewSMTPEmailService(SMTPConfig{
+		Host:  "127.0.0.1",
+		Porta: listener.Addr().(*net.TCPAddr).Port,
+	}, slog.New(slog.NewTextHandler(io.Discard, nil)))
+	err = service.Send(context.Background(), &usecase.Mensagem{
+		De:      "relatorios@example.org",
+		Para:    []string{"responsavel91@example.org"},
+		Assunto: "Relatórios",
+	})
+	if err == nil || !strings.Contains(err.Error(), "remetente recusado") {
+		t.Fatalf("Send = %v, esperado remetente recusado", err)
+	}
+}
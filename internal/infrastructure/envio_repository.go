@@ -0,0 +1,86 @@
+package infrastructure
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+
+	"report/internal/domain"
+)
+
+// JSONEnvioRepository implementa EnvioRepository gravando os envios em um arquivo JSON
+type JSONEnvioRepository struct {
+	path string
+	mu   sync.Mutex
+}
+
+// NewJSONEnvioRepository cria uma nova instância de JSONEnvioRepository
+func NewJSONEnvioRepository(path string) *JSONEnvioRepository {
+	return &JSONEnvioRepository{path: path}
+}
+
+// Get retorna o envio do setor no período, ou nil quando ainda não houve envio
+func (r *JSONEnvioRepository) Get(setor, periodo string) (*domain.Envio, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	envios, err := r.load()
+	if err != nil {
+		return nil, err
+	}
+	for _, envio := range envios {
+		if envio.Setor == setor && envio.Periodo == periodo {
+			return envio, nil
+		}
+	}
+	return nil, nil
+}
+
+// Save registra o envio, substituindo o registro anterior do setor no período
+func (r *JSONEnvioRepository) Save(envio *domain.Envio) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	envios, err := r.load()
+	if err != nil {
+		return err
+	}
+
+	substituido := false
+	for i, anterior := range envios {
+		if anterior.Setor == envio.Setor && anterior.Periodo == envio.Periodo {
+			envios[i] = envio
+			substituido = true
+		}
+	}
+	if !substituido {
+		envios = append(envios, envio)
+	}
+
+	if err := os.MkdirAll(filepath.Dir(r.path), os.ModePerm); err != nil {
+		return fmt.Errorf("erro ao criar diretório: %v", err)
+	}
+	content, err := json.MarshalIndent(envios, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(r.path, content, 0644)
+}
+
+func (r *JSONEnvioRepository) load() ([]*domain.Envio, error) {
+	content, err := os.ReadFile(r.path)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var envios []*domain.Envio
+	if err := json.Unmarshal(content, &envios); err != nil {
+		return nil, fmt.Errorf("erro ao ler registro de envios: %v", err)
+	}
+	return envios, nil
+}
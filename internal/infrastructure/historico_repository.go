@@ -0,0 +1,103 @@
+package infrastructure
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"report/internal/domain"
+)
+
+// JSONHistoricoRepository implementa HistoricoRepository gravando um arquivo JSON por período
+type JSONHistoricoRepository struct {
+	dir string
+}
+
+// NewJSONHistoricoRepository cria uma nova instância de JSONHistoricoRepository
+func NewJSONHistoricoRepository(dir string) *JSONHistoricoRepository {
+	return &JSONHistoricoRepository{dir: dir}
+}
+
+// Save grava os dados consolidados do período, substituindo os anteriores
+func (r *JSONHistoricoRepository) Save(snapshot *domain.Snapshot) error {
+	if err := domain.ValidarPeriodo(snapshot.Periodo); err != nil {
+		return err
+	}
+	if err := os.MkdirAll(r.dir, os.ModePerm); err != nil {
+		return fmt.Errorf("erro ao criar diretório: %v", err)
+	}
+
+	content, err := json.MarshalIndent(snapshot, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(r.path(snapshot.Periodo), content, 0644)
+}
+
+// Get retorna os dados consolidados de um período. Um período sem histórico
+// retorna um erro compatível com fs.ErrNotExist.
+func (r *JSONHistoricoRepository) Get(periodo string) (*domain.Snapshot, error) {
+	if err := domain.ValidarPeriodo(periodo); err != nil {
+		return nil, err
+	}
+	content, err := os.ReadFile(r.path(periodo))
+	if err != nil {
+		return nil, err
+	}
+
+	var snapshot domain.Snapshot
+	if err := json.Unmarshal(content, &snapshot); err != nil {
+		return nil, fmt.Errorf("erro ao ler histórico de %s: %v", periodo, err)
+	}
+	return &snapshot, nil
+}
+
+// GetAnterior retorna os dados do período mais recente anterior ao informado,
+// ou nil quando não há histórico
+func (r *JSONHistoricoRepository) GetAnterior(periodo string) (*domain.Snapshot, error) {
+	periodos, err := r.List()
+	if err != nil {
+		return nil, err
+	}
+
+	for i := len(periodos) - 1; i >= 0; i-- {
+		if periodos[i] < periodo {
+			return r.Get(periodos[i])
+		}
+	}
+	return nil, nil
+}
+
+// List retorna os períodos disponíveis em ordem cronológica
+func (r *JSONHistoricoRepository) List() ([]string, error) {
+	entries, err := os.ReadDir(r.dir)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var periodos []string
+	for _, entry := range entries {
+		if entry.IsDir() || filepath.Ext(entry.Name()) != ".json" {
+			continue
+		}
+		periodo := strings.TrimSuffix(entry.Name(), ".json")
+		if domain.ValidarPeriodo(periodo) != nil {
+			continue
+		}
+		periodos = append(periodos, periodo)
+	}
+	sort.Strings(periodos)
+	return periodos, nil
+}
+
+// path retorna o arquivo do período, que deve ter sido validado antes para
+// não apontar para fora do diretório do histórico
+func (r *JSONHistoricoRepository) path(periodo string) string {
+	return filepath.Join(r.dir, periodo+".json")
+}
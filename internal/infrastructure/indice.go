@@ -0,0 +1,53 @@
+package infrastructure
+
+import (
+	"os"
+	"sync"
+)
+
+// estadoDe retorna o estado atual do arquivo; um arquivo inacessível é
+// tratado como inexistente
+func estadoDe(caminho string) estadoArquivo {
+	info, err := os.Stat(caminho)
+	if err != nil {
+		return estadoArquivo{}
+	}
+	return estadoArquivo{existe: true, tamanho: info.Size(), modificado: info.ModTime()}
+}
+
+// indiceArquivo guarda o resultado da leitura de um arquivo e o reaproveita
+// enquanto o arquivo não muda, para que os métodos de um repositório
+// compartilhem uma única leitura por execução
+type indiceArquivo[T any] struct {
+	mu     sync.Mutex
+	lido   bool
+	estado estadoArquivo
+	valor  T
+}
+
+// obter retorna o índice guardado ou chama ler quando o arquivo foi alterado
+// desde a última leitura. Erros de leitura não são guardados.
+func (i *indiceArquivo[T]) obter(caminho string, ler func() (T, error)) (T, error) {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+
+	estado := estadoDe(caminho)
+	if i.lido && estado == i.estado {
+		return i.valor, nil
+	}
+
+	valor, err := ler()
+	if err != nil {
+		var vazio T
+		return vazio, err
+	}
+	i.lido, i.estado, i.valor = true, estado, valor
+	return valor, nil
+}
+
+// invalidar descarta o índice guardado, forçando uma nova leitura
+func (i *indiceArquivo[T]) invalidar() {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+	i.lido = false
+}
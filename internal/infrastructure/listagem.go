@@ -0,0 +1,125 @@
+package infrastructure
+
+import (
+	"strconv"
+	"strings"
+	"time"
+)
+
+// colunasListagem identifica as colunas da listagem de horas exportada pelo portal
+type colunasListagem struct {
+	localidade int
+	livro      int
+	voluntario int
+	data       int
+	inicio     int
+	fim        int
+	horas      int
+}
+
+// colunasPadrao corresponde ao leiaute da "Listagem de Horas de Trabalho Voluntário"
+var colunasPadrao = colunasListagem{
+	localidade: 0,
+	livro:      2,
+	voluntario: 7,
+	data:       12,
+	inicio:     14,
+	fim:        17,
+	horas:      18,
+}
+
+// detectarColunas localiza as colunas pelo texto do cabeçalho, mantendo a
+// posição padrão das que não forem encontradas
+func detectarColunas(cabecalho []string) colunasListagem {
+	colunas := colunasPadrao
+	for i, titulo := range cabecalho {
+		switch normalizeLocalidade(titulo) {
+		case "LOCALIDADE":
+			colunas.localidade = i
+		case "LIVRO":
+			colunas.livro = i
+		case "VOLUNTARIO", "NOME":
+			colunas.voluntario = i
+		case "DATA":
+			colunas.data = i
+		case "INICIO", "ENTRADA":
+			colunas.inicio = i
+		case "FIM", "SAIDA":
+			colunas.fim = i
+		case "HORAS":
+			colunas.horas = i
+		}
+	}
+	return colunas
+}
+
+// valor retorna o conteúdo da coluna, ou vazio quando a linha é mais curta
+func (c colunasListagem) valor(record []string, coluna int) string {
+	if coluna < 0 || coluna >= len(record) {
+		return ""
+	}
+	return strings.TrimSpace(record[coluna])
+}
+
+// horasTrabalhadas retorna as horas trabalhadas na linha, lidas da coluna Horas (HH:MM
+// ou decimal) ou, sem ela, calculadas pela entrada e saída. Uma saída menor
+// que a entrada é considerada no dia seguinte.
+func (c colunasListagem) horasTrabalhadas(record []string) float64 {
+	if horas, ok := parseHoras(c.valor(record, c.horas)); ok {
+		return horas
+	}
+
+	inicio, okInicio := parseHoras(c.valor(record, c.inicio))
+	fim, okFim := parseHoras(c.valor(record, c.fim))
+	if !okInicio || !okFim {
+		return 0
+	}
+	if fim < inicio {
+		fim += 24
+	}
+	return fim - inicio
+}
+
+// dataLancamento retorna a data do apontamento, no formato DD/MM/AA ou
+// DD/MM/AAAA usado pelo portal
+func (c colunasListagem) dataLancamento(record []string) (time.Time, bool) {
+	valor := c.valor(record, c.data)
+	for _, layout := range []string{"02/01/06", "02/01/2006", "2006-01-02"} {
+		if data, err := time.Parse(layout, valor); err == nil {
+			return data, true
+		}
+	}
+	return time.Time{}, false
+}
+
+// parseHoras interpreta "HH:MM", "HH:MM:SS" ou um número decimal com ponto ou vírgula
+func parseHoras(valor string) (float64, bool) {
+	if valor == "" {
+		return 0, false
+	}
+
+	if partes := strings.Split(valor, ":"); len(partes) > 1 {
+		var horas float64
+		for i, parte := range partes[:min(len(partes), 3)] {
+			n, err := strconv.Atoi(strings.TrimSpace(parte))
+			if err != nil || n < 0 {
+				return 0, false
+			}
+			switch i {
+			case 0:
+				horas += float64(n)
+			case 1:
+				horas += float64(n) / 60
+			case 2:
+				horas += float64(n) / 3600
+			}
+		}
+		return horas, true
+	}
+
+	horas, err := strconv.ParseFloat(strings.ReplaceAll(valor, ",", "."), 64)
+	if err != nil || horas < 0 {
+		return 0, false
+	}
+	return horas, true
+}
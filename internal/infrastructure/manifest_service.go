@@ -0,0 +1,79 @@
+package infrastructure
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"report/internal/usecase"
+)
+
+// pdfPageObject identifica os objetos de página de um PDF, sem incluir o nó /Pages
+var pdfPageObject = regexp.MustCompile(`/Type\s*/Page[^s]`)
+
+// JSONManifestService implementa ManifestService gravando o manifesto em JSON
+type JSONManifestService struct{}
+
+// NewJSONManifestService cria uma nova instância de JSONManifestService
+func NewJSONManifestService() *JSONManifestService {
+	return &JSONManifestService{}
+}
+
+// Inspect calcula o hash SHA-256, o tamanho e, para PDFs, o número de páginas de um arquivo
+func (s *JSONManifestService) Inspect(path string) (usecase.ArquivoManifest, error) {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return usecase.ArquivoManifest{}, err
+	}
+
+	hash := sha256.Sum256(content)
+	arquivo := usecase.ArquivoManifest{
+		Caminho: path,
+		SHA256:  hex.EncodeToString(hash[:]),
+		Tamanho: int64(len(content)),
+	}
+	if strings.EqualFold(filepath.Ext(path), ".pdf") {
+		arquivo.Paginas = countPDFPages(content)
+	}
+
+	return arquivo, nil
+}
+
+// Save grava o manifesto em JSON indentado
+func (s *JSONManifestService) Save(manifest *usecase.Manifest, path string) error {
+	if err := ensureDir(path); err != nil {
+		return err
+	}
+
+	content, err := json.MarshalIndent(manifest, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, append(content, '\n'), 0644)
+}
+
+// Load lê um manifesto gravado anteriormente
+func (s *JSONManifestService) Load(path string) (*usecase.Manifest, error) {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var manifest usecase.Manifest
+	if err := json.Unmarshal(content, &manifest); err != nil {
+		return nil, err
+	}
+	return &manifest, nil
+}
+
+func countPDFPages(content []byte) int {
+	if !bytes.HasPrefix(content, []byte("%PDF")) {
+		return 0
+	}
+	return len(pdfPageObject.FindAll(content, -1))
+}
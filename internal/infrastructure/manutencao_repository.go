@@ -0,0 +1,54 @@
+package infrastructure
+
+import (
+	"fmt"
+	"strings"
+
+	"report/internal/domain"
+)
+
+// CSVPlanoManutencaoRepository implementa PlanoManutencaoRepository a partir
+// de um CSV com as colunas localidade, tarefa, frequencia e livro
+type CSVPlanoManutencaoRepository struct {
+	planoPath string
+}
+
+// NewCSVPlanoManutencaoRepository cria uma nova instância de CSVPlanoManutencaoRepository
+func NewCSVPlanoManutencaoRepository(planoPath string) *CSVPlanoManutencaoRepository {
+	return &CSVPlanoManutencaoRepository{planoPath: planoPath}
+}
+
+// GetAll retorna as tarefas do plano. Um arquivo inexistente não é
+// considerado erro: sem plano, vale apenas o mínimo de manutenção dos alertas.
+func (r *CSVPlanoManutencaoRepository) GetAll() ([]domain.TarefaManutencao, error) {
+	records, err := readCSVIfExists(r.planoPath)
+	if err != nil {
+		return nil, fmt.Errorf("erro ao ler plano de manutenção: %v", err)
+	}
+
+	var tarefas []domain.TarefaManutencao
+	for i, record := range skipHeader(records) {
+		if len(record) < 3 {
+			continue
+		}
+		tarefa := domain.TarefaManutencao{
+			Localidade: normalizeLocalidade(record[0]),
+			Tarefa:     strings.TrimSpace(record[1]),
+			Livro:      domain.LivroManutencao,
+		}
+		if tarefa.Tarefa == "" {
+			continue
+		}
+		frequencia, err := domain.ParseFrequencia(record[2])
+		if err != nil {
+			return nil, fmt.Errorf("linha %d de %s: %v", i+2, r.planoPath, err)
+		}
+		tarefa.Frequencia = frequencia
+		if len(record) > 3 && strings.TrimSpace(record[3]) != "" {
+			tarefa.Livro = strings.TrimSpace(record[3])
+		}
+		tarefas = append(tarefas, tarefa)
+	}
+
+	return tarefas, nil
+}
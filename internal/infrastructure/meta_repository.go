@@ -0,0 +1,61 @@
+package infrastructure
+
+import (
+	"fmt"
+	"strings"
+
+	"report/internal/domain"
+)
+
+// CSVMetaRepository implementa MetaRepository a partir de um CSV com as
+// colunas livro, metrica, meta, setor e localidade
+type CSVMetaRepository struct {
+	metasPath string
+}
+
+// NewCSVMetaRepository cria uma nova instância de CSVMetaRepository
+func NewCSVMetaRepository(metasPath string) *CSVMetaRepository {
+	return &CSVMetaRepository{metasPath: metasPath}
+}
+
+// GetAll retorna as metas cadastradas. Um arquivo inexistente não é
+// considerado erro: sem metas, o cumprimento não é calculado.
+func (r *CSVMetaRepository) GetAll() ([]domain.Meta, error) {
+	records, err := readCSVIfExists(r.metasPath)
+	if err != nil {
+		return nil, fmt.Errorf("erro ao ler metas: %v", err)
+	}
+
+	var metas []domain.Meta
+	for i, record := range skipHeader(records) {
+		linha := i + 2
+		if len(record) < 3 {
+			continue
+		}
+		livro := strings.TrimSpace(record[0])
+		if livro == "" {
+			continue
+		}
+
+		metrica, err := domain.ParseMetrica(record[1])
+		if err != nil {
+			return nil, fmt.Errorf("linha %d de %s: %v", linha, r.metasPath, err)
+		}
+		// Aceita horas no formato HH:MM e valores decimais com vírgula
+		valor, ok := parseHoras(strings.TrimSpace(record[2]))
+		if !ok {
+			return nil, fmt.Errorf("linha %d de %s: meta inválida: %s", linha, r.metasPath, record[2])
+		}
+
+		meta := domain.Meta{Livro: livro, Metrica: metrica, Valor: valor}
+		if len(record) > 3 {
+			meta.Setor = strings.TrimSpace(record[3])
+		}
+		if len(record) > 4 {
+			meta.Localidade = normalizeLocalidade(record[4])
+		}
+		metas = append(metas, meta)
+	}
+
+	return metas, nil
+}
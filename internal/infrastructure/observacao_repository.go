@@ -0,0 +1,51 @@
+package infrastructure
+
+import (
+	"fmt"
+	"strings"
+
+	"report/internal/domain"
+)
+
+// CSVObservacaoRepository implementa ObservacaoRepository a partir de um CSV
+// com as colunas periodo, localidade e observacao, uma linha por observação
+type CSVObservacaoRepository struct {
+	observacoesPath string
+}
+
+// NewCSVObservacaoRepository cria uma nova instância de CSVObservacaoRepository
+func NewCSVObservacaoRepository(observacoesPath string) *CSVObservacaoRepository {
+	return &CSVObservacaoRepository{observacoesPath: observacoesPath}
+}
+
+// GetAll retorna as observações cadastradas. Um arquivo inexistente não é
+// considerado erro: sem observações, o quadro é impresso em branco.
+func (r *CSVObservacaoRepository) GetAll() ([]domain.Observacao, error) {
+	records, err := readCSVIfExists(r.observacoesPath)
+	if err != nil {
+		return nil, fmt.Errorf("erro ao ler observações: %v", err)
+	}
+
+	var observacoes []domain.Observacao
+	for i, record := range skipHeader(records) {
+		if len(record) < 3 {
+			continue
+		}
+		observacao := domain.Observacao{
+			Periodo:    strings.TrimSpace(record[0]),
+			Localidade: normalizeLocalidade(record[1]),
+			Texto:      strings.TrimSpace(record[2]),
+		}
+		if observacao.Localidade == "" || observacao.Texto == "" {
+			continue
+		}
+		if observacao.Periodo != "" {
+			if err := domain.ValidarPeriodo(observacao.Periodo); err != nil {
+				return nil, fmt.Errorf("linha %d de %s: %v", i+2, r.observacoesPath, err)
+			}
+		}
+		observacoes = append(observacoes, observacao)
+	}
+
+	return observacoes, nil
+}
@@ -0,0 +1,89 @@
+package infrastructure
+
+import (
+	"context"
+	"log/slog"
+	"sort"
+	"time"
+)
+
+// estadoArquivo resume um arquivo observado; a mudança de qualquer campo
+// indica alteração
+type estadoArquivo struct {
+	existe     bool
+	tamanho    int64
+	modificado time.Time
+}
+
+// Observador acompanha arquivos por polling e avisa quando eles mudam,
+// aguardando que as alterações cessem antes de avisar
+type Observador struct {
+	caminhos  []string
+	intervalo time.Duration
+	espera    time.Duration
+	logger    *slog.Logger
+}
+
+// NewObservador cria um observador que verifica os arquivos a cada intervalo
+// e avisa depois que eles ficam sem alteração pelo tempo de espera
+func NewObservador(caminhos []string, intervalo, espera time.Duration, logger *slog.Logger) *Observador {
+	return &Observador{
+		caminhos:  caminhos,
+		intervalo: intervalo,
+		espera:    espera,
+		logger:    logger.With("servico", "observador"),
+	}
+}
+
+// Observar chama alterado com os arquivos modificados desde o último aviso.
+// Alterações feitas durante a execução de alterado não geram novo aviso.
+// Retorna quando o contexto é cancelado.
+func (o *Observador) Observar(ctx context.Context, alterado func(arquivos []string)) {
+	estados := o.estados()
+	pendentes := make(map[string]bool)
+	var ultimaMudanca time.Time
+
+	ticker := time.NewTicker(o.intervalo)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case agora := <-ticker.C:
+			atuais := o.estados()
+			for caminho, estado := range atuais {
+				if estado != estados[caminho] {
+					o.logger.Debug("arquivo alterado", "arquivo", caminho)
+					pendentes[caminho] = true
+					ultimaMudanca = agora
+				}
+			}
+			estados = atuais
+
+			// Aguarda a gravação terminar: a listagem costuma ser
+			// exportada mais de uma vez em sequência
+			if len(pendentes) == 0 || agora.Sub(ultimaMudanca) < o.espera {
+				continue
+			}
+
+			arquivos := make([]string, 0, len(pendentes))
+			for caminho := range pendentes {
+				arquivos = append(arquivos, caminho)
+			}
+			sort.Strings(arquivos)
+			pendentes = make(map[string]bool)
+
+			alterado(arquivos)
+			estados = o.estados()
+		}
+	}
+}
+
+func (o *Observador) estados() map[string]estadoArquivo {
+	estados := make(map[string]estadoArquivo, len(o.caminhos))
+	for _, caminho := range o.caminhos {
+		estados[caminho] = estadoDe(caminho)
+	}
+	return estados
+}
@@ -0,0 +1,45 @@
+package infrastructure
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestObservadorObservar(t *testing.T) {
+	dir := t.TempDir()
+	listagem := filepath.Join(dir, "listagem.csv")
+	pendentes := filepath.Join(dir, "aliases_pendentes.csv")
+	os.WriteFile(listagem, []byte("a"), 0o644)
+
+	observador := NewObservador([]string{listagem, pendentes}, 5*time.Millisecond, 20*time.Millisecond,
+		slog.New(slog.NewTextHandler(io.Discard, nil)))
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+
+	var avisos [][]string
+	go func() {
+		time.Sleep(30 * time.Millisecond)
+		// Um arquivo criado depois do início também é observado
+		os.WriteFile(pendentes, []byte("nome,sugestao,similaridade,confirmar\n"), 0o644)
+		os.WriteFile(listagem, []byte("ab"), 0o644)
+	}()
+	observador.Observar(ctx, func(arquivos []string) {
+		avisos = append(avisos, arquivos)
+		if len(avisos) == 1 {
+			// A gravação feita pela própria geração não gera novo aviso
+			os.WriteFile(pendentes, []byte("outro conteúdo"), 0o644)
+			time.AfterFunc(100*time.Millisecond, cancel)
+		}
+	})
+
+	esperados := [][]string{{pendentes, listagem}}
+	if !reflect.DeepEqual(avisos, esperados) {
+		t.Errorf("avisos = %v, esperado %v", avisos, esperados)
+	}
+}
@@ -0,0 +1,82 @@
+package infrastructure
+
+import (
+	"archive/zip"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+
+	"report/internal/usecase"
+)
+
+// ZIPPacoteService implementa PacoteService gravando arquivos ZIP
+type ZIPPacoteService struct {
+	logger *slog.Logger
+}
+
+// NewZIPPacoteService cria uma nova instância de ZIPPacoteService
+func NewZIPPacoteService(logger *slog.Logger) *ZIPPacoteService {
+	return &ZIPPacoteService{logger: logger.With("servico", "zip")}
+}
+
+// Create grava o pacote com os arquivos informados. Em caso de erro o
+// pacote incompleto é removido.
+func (s *ZIPPacoteService) Create(outputPath string, arquivos []usecase.ArquivoPacote) (err error) {
+	if err := ensureDir(outputPath); err != nil {
+		return err
+	}
+
+	file, err := os.Create(outputPath)
+	if err != nil {
+		return fmt.Errorf("erro ao criar pacote: %v", err)
+	}
+	defer func() {
+		file.Close()
+		if err != nil {
+			os.Remove(outputPath)
+		}
+	}()
+
+	archive := zip.NewWriter(file)
+	for _, arquivo := range arquivos {
+		if err := addFileToZip(archive, arquivo); err != nil {
+			return fmt.Errorf("erro ao incluir %s no pacote: %v", arquivo.Nome, err)
+		}
+	}
+	if err := archive.Close(); err != nil {
+		return fmt.Errorf("erro ao finalizar pacote: %v", err)
+	}
+	if err := file.Close(); err != nil {
+		return err
+	}
+
+	s.logger.Debug("pacote gravado", "arquivo", outputPath, "arquivos", len(arquivos))
+	return nil
+}
+
+func addFileToZip(archive *zip.Writer, arquivo usecase.ArquivoPacote) error {
+	file, err := os.Open(arquivo.Caminho)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	info, err := file.Stat()
+	if err != nil {
+		return err
+	}
+	header, err := zip.FileInfoHeader(info)
+	if err != nil {
+		return err
+	}
+	header.Name = arquivo.Nome
+	header.Method = zip.Deflate
+
+	entry, err := archive.CreateHeader(header)
+	if err != nil {
+		return err
+	}
+	_, err = io.Copy(entry, file)
+	return err
+}
@@ -0,0 +1,125 @@
+package infrastructure
+
+import (
+	"fmt"
+	"time"
+
+	"report/internal/domain"
+	"report/internal/usecase"
+
+	"github.com/jung-kurt/gofpdf/v2"
+)
+
+// GenerateAdministracaoReport gera o relatório consolidado de uma
+// administração: totais por livro da administração, comparação entre os
+// setores e os totais por livro de cada setor
+func (s *GofpdfService) GenerateAdministracaoReport(data *usecase.AdministracaoReportData, outputPath string) error {
+	inicio := time.Now()
+	pdf := gofpdf.New("P", "mm", "A4", "")
+	tr := pdf.UnicodeTranslatorFromDescriptor("")
+
+	pdf.SetTitle(data.Titulo, true)
+	pdf.SetAuthor("Phellipe Rodrigues", true)
+	pdf.AddPage()
+
+	// Cabeçalho
+	pdf.SetFont("Arial", "B", 16)
+	pdf.Cell(40, 10, tr("Relatório da Administração"))
+	pdf.Ln(10)
+	pdf.SetFont("Arial", "B", 14)
+	pdf.Cell(40, 10, tr(fmt.Sprintf("Administração: %s", data.Administracao)))
+	pdf.Ln(10)
+	pdf.SetFont("Arial", "", 10)
+	consolidado := data.Consolidado
+	pdf.MultiCell(0, 6, tr(fmt.Sprintf("Período: %s - %d setores, %d localidades, %d pontos de atenção",
+		data.Periodo, len(data.Setores), consolidado.Localidades, consolidado.Alertas)), "", "", false)
+	pdf.Ln(4)
+
+	// Totais da administração
+	pdf.SetFont("Arial", "B", 12)
+	pdf.CellFormat(0, 8, tr("Totais por Livro"), "", 1, "", false, 0, "")
+	s.addTotaisLivros(pdf, tr, consolidado.Livros)
+	pdf.Ln(6)
+
+	// Comparação entre os setores
+	pdf.SetFont("Arial", "B", 12)
+	pdf.CellFormat(0, 8, tr("Totais por Setor"), "", 1, "", false, 0, "")
+	pdf.SetFont("Arial", "B", 9)
+	pdf.CellFormat(50, 7, "Setor", "1", 0, "C", false, 0, "")
+	pdf.CellFormat(22, 7, "Localidades", "1", 0, "C", false, 0, "")
+	pdf.CellFormat(25, 7, tr("Lançamentos"), "1", 0, "C", false, 0, "")
+	pdf.CellFormat(22, 7, "Horas", "1", 0, "C", false, 0, "")
+	pdf.CellFormat(22, 7, tr("Voluntários"), "1", 0, "C", false, 0, "")
+	pdf.CellFormat(22, 7, "Alertas", "1", 0, "C", false, 0, "")
+	pdf.CellFormat(27, 7, "Cumprimento", "1", 1, "C", false, 0, "")
+	pdf.SetFont("Arial", "", 9)
+	for _, setor := range data.Setores {
+		s.addLinhaConsolidada(pdf, tr, setor)
+	}
+	pdf.SetFont("Arial", "B", 9)
+	s.addLinhaConsolidada(pdf, tr, consolidado)
+
+	// Totais de cada setor
+	for _, setor := range data.Setores {
+		if setor.Localidades == 0 {
+			continue
+		}
+		pdf.Ln(6)
+		pdf.SetFont("Arial", "B", 12)
+		pdf.CellFormat(0, 8, tr(setor.Nome), "", 1, "", false, 0, "")
+		s.addTotaisLivros(pdf, tr, setor.Livros)
+	}
+
+	pdf.Ln(8)
+	pdf.SetFont("Arial", "", 10)
+	pdf.MultiCell(0, 6, tr(fmt.Sprintf("Relatório gerado em %s", data.Data.Format("02/01/2006 15:04"))), "", "", false)
+
+	if err := ensureDir(outputPath); err != nil {
+		return err
+	}
+
+	return s.output(pdf, outputPath, inicio)
+}
+
+// addTotaisLivros desenha a tabela de lançamentos, horas e voluntários por livro
+func (s *GofpdfService) addTotaisLivros(pdf *gofpdf.Fpdf, tr func(string) string, livros map[string]*domain.Summary) {
+	pdf.SetFont("Arial", "B", 10)
+	pdf.CellFormat(100, 7, "Livro", "1", 0, "C", false, 0, "")
+	pdf.CellFormat(30, 7, tr("Lançamentos"), "1", 0, "C", false, 0, "")
+	pdf.CellFormat(30, 7, "Horas", "1", 0, "C", false, 0, "")
+	pdf.CellFormat(30, 7, tr("Voluntários"), "1", 1, "C", false, 0, "")
+
+	pdf.SetFont("Arial", "", 10)
+	for _, livro := range usecase.OrdenarChaves(livros) {
+		summary := livros[livro]
+		pdf.CellFormat(100, 7, tr(livro), "1", 0, "", false, 0, "")
+		pdf.CellFormat(30, 7, fmt.Sprintf("%d", summary.TotalTrabalhos), "1", 0, "C", false, 0, "")
+		pdf.CellFormat(30, 7, formatarHoras(summary.Horas), "1", 0, "C", false, 0, "")
+		pdf.CellFormat(30, 7, fmt.Sprintf("%d", summary.TotalVoluntarios()), "1", 1, "C", false, 0, "")
+	}
+}
+
+// addLinhaConsolidada desenha uma linha da comparação entre setores
+func (s *GofpdfService) addLinhaConsolidada(pdf *gofpdf.Fpdf, tr func(string) string, resumo usecase.ResumoConsolidado) {
+	lancamentos, horas := 0, 0.0
+	voluntarios := make(map[string]bool)
+	for _, summary := range resumo.Livros {
+		lancamentos += summary.TotalTrabalhos
+		horas += summary.Horas
+		for voluntario := range summary.Voluntarios {
+			voluntarios[voluntario] = true
+		}
+	}
+
+	pdf.CellFormat(50, 7, tr(resumo.Nome), "1", 0, "", false, 0, "")
+	pdf.CellFormat(22, 7, fmt.Sprintf("%d", resumo.Localidades), "1", 0, "C", false, 0, "")
+	pdf.CellFormat(25, 7, fmt.Sprintf("%d", lancamentos), "1", 0, "C", false, 0, "")
+	pdf.CellFormat(22, 7, formatarHoras(horas), "1", 0, "C", false, 0, "")
+	pdf.CellFormat(22, 7, fmt.Sprintf("%d", len(voluntarios)), "1", 0, "C", false, 0, "")
+	pdf.CellFormat(22, 7, fmt.Sprintf("%d", resumo.Alertas), "1", 0, "C", false, 0, "")
+	if resumo.ComMetas > 0 {
+		s.celulaCumprimento(pdf, 27, 7, resumo.Cumprimento, 1)
+	} else {
+		pdf.CellFormat(27, 7, "-", "1", 1, "C", false, 0, "")
+	}
+}
@@ -0,0 +1,131 @@
+package infrastructure
+
+import (
+	"fmt"
+	"math"
+	"time"
+
+	"report/internal/usecase"
+
+	"github.com/jung-kurt/gofpdf/v2"
+)
+
+// GenerateClassificacaoReport gera a classificação das localidades: a geral
+// e a de cada setor, com a variação de posição desde o período anterior
+func (s *GofpdfService) GenerateClassificacaoReport(data *usecase.ClassificacaoReportData, outputPath string) error {
+	inicio := time.Now()
+	pdf := gofpdf.New("P", "mm", "A4", "")
+	tr := pdf.UnicodeTranslatorFromDescriptor("")
+
+	pdf.SetTitle(data.Titulo, true)
+	pdf.SetAuthor("Phellipe Rodrigues", true)
+	pdf.AddPage()
+
+	// Cabeçalho
+	pdf.SetFont("Arial", "B", 16)
+	pdf.Cell(40, 10, tr(data.Titulo))
+	pdf.Ln(10)
+	pdf.SetFont("Arial", "", 10)
+	comparacao := "sem histórico para comparar as posições"
+	if data.PeriodoAnterior != "" {
+		comparacao = fmt.Sprintf("variação de posição em relação a %s", data.PeriodoAnterior)
+	}
+	pdf.MultiCell(0, 6, tr(fmt.Sprintf("Período: %s - %d localidades, %s. "+
+		"A classificação geral e a dos setores seguem o cumprimento das metas e, no empate, os alertas abertos, "+
+		"as horas e os voluntários; as classificações por critério ordenam pelas horas, pelos voluntários distintos "+
+		"ou pelos alertas abertos, com os mesmos desempates. "+
+		"Sem metas (*), o cumprimento é o percentual dos livros cadastrados com apontamentos.",
+		data.Periodo, len(data.Geral), comparacao)), "", "", false)
+	pdf.Ln(4)
+
+	pdf.SetFont("Arial", "B", 12)
+	pdf.CellFormat(0, 8, tr("Classificação Geral"), "", 1, "", false, 0, "")
+	s.addClassificacao(pdf, tr, data.Geral, data.PeriodoAnterior != "")
+
+	for _, criterio := range data.Criterios {
+		tituloClassificacao(pdf, tr, "Classificação por "+criterio.Criterio.Titulo())
+		s.addClassificacao(pdf, tr, criterio.Posicoes, data.PeriodoAnterior != "")
+	}
+
+	for _, setor := range data.Setores {
+		tituloClassificacao(pdf, tr, setor.Nome)
+		s.addClassificacao(pdf, tr, setor.Posicoes, data.PeriodoAnterior != "")
+	}
+
+	pdf.Ln(8)
+	pdf.SetFont("Arial", "", 10)
+	pdf.MultiCell(0, 6, tr(fmt.Sprintf("Relatório gerado em %s", data.Data.Format("02/01/2006 15:04"))), "", "", false)
+
+	if err := ensureDir(outputPath); err != nil {
+		return err
+	}
+
+	return s.output(pdf, outputPath, inicio)
+}
+
+// tituloClassificacao escreve o título de uma tabela, mantendo-o junto ao
+// cabeçalho e às primeiras linhas dela
+func tituloClassificacao(pdf *gofpdf.Fpdf, tr func(string) string, titulo string) {
+	_, altura := pdf.GetPageSize()
+	_, _, _, margemInferior := pdf.GetMargins()
+	if pdf.GetY()+40 > altura-margemInferior {
+		pdf.AddPage()
+	} else {
+		pdf.Ln(6)
+	}
+	pdf.SetFont("Arial", "B", 12)
+	pdf.CellFormat(0, 8, tr(titulo), "", 1, "", false, 0, "")
+}
+
+// addClassificacao desenha uma tabela da classificação. Com histórico, a
+// coluna de variação mostra as posições ganhas ou perdidas.
+func (s *GofpdfService) addClassificacao(pdf *gofpdf.Fpdf, tr func(string) string, posicoes []usecase.PosicaoLocalidade, comAnterior bool) {
+	pdf.SetFont("Arial", "B", 9)
+	pdf.CellFormat(12, 7, "Pos.", "1", 0, "C", false, 0, "")
+	pdf.CellFormat(16, 7, tr("Variação"), "1", 0, "C", false, 0, "")
+	pdf.CellFormat(76, 7, "Localidade", "1", 0, "C", false, 0, "")
+	pdf.CellFormat(26, 7, "Cumprimento", "1", 0, "C", false, 0, "")
+	pdf.CellFormat(22, 7, "Horas", "1", 0, "C", false, 0, "")
+	pdf.CellFormat(22, 7, tr("Voluntários"), "1", 0, "C", false, 0, "")
+	pdf.CellFormat(16, 7, "Alertas", "1", 1, "C", false, 0, "")
+
+	pdf.SetFont("Arial", "", 9)
+	for _, posicao := range posicoes {
+		pdf.CellFormat(12, 7, fmt.Sprintf("%d", posicao.Posicao), "1", 0, "C", false, 0, "")
+		s.celulaVariacao(pdf, posicao, comAnterior)
+		pdf.CellFormat(76, 7, ajustarTexto(pdf, tr, posicao.Localidade, 74), "1", 0, "", false, 0, "")
+		texto := fmt.Sprintf("%.0f%%", math.Floor(posicao.Cumprimento))
+		if !posicao.ComMetas {
+			texto += "*"
+		}
+		s.celulaPercentual(pdf, 26, 7, posicao.Cumprimento, texto, 0)
+		pdf.CellFormat(22, 7, formatarHoras(posicao.Horas), "1", 0, "C", false, 0, "")
+		pdf.CellFormat(22, 7, fmt.Sprintf("%d", posicao.Voluntarios), "1", 0, "C", false, 0, "")
+		pdf.CellFormat(16, 7, fmt.Sprintf("%d", posicao.Alertas), "1", 1, "C", false, 0, "")
+	}
+}
+
+// celulaVariacao escreve as posições ganhas ou perdidas com a seta da
+// tendência; localidades ausentes do período anterior aparecem como novas
+func (s *GofpdfService) celulaVariacao(pdf *gofpdf.Fpdf, posicao usecase.PosicaoLocalidade, comAnterior bool) {
+	const largura, altura = 16.0, 7.0
+
+	x, y := pdf.GetXY()
+	switch {
+	case !comAnterior:
+		pdf.CellFormat(largura, altura, "-", "1", 0, "C", false, 0, "")
+	case posicao.PosicaoAnterior == 0:
+		pdf.CellFormat(largura, altura, "nova", "1", 0, "C", false, 0, "")
+	default:
+		variacao := posicao.Variacao()
+		tendencia := usecase.TendenciaEstavel
+		texto := "0"
+		if variacao > 0 {
+			tendencia, texto = usecase.TendenciaAlta, fmt.Sprintf("+%d", variacao)
+		} else if variacao < 0 {
+			tendencia, texto = usecase.TendenciaBaixa, fmt.Sprintf("%d", variacao)
+		}
+		pdf.CellFormat(largura, altura, texto+"  ", "1", 0, "R", false, 0, "")
+		s.desenharTendencia(pdf, x+4, y+altura/2, tendencia)
+	}
+}
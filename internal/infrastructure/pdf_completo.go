@@ -0,0 +1,155 @@
+package infrastructure
+
+import (
+	"fmt"
+	"time"
+
+	"report/internal/usecase"
+
+	"github.com/jung-kurt/gofpdf/v2"
+)
+
+// linhasSumario é o número de entradas do sumário por página
+const linhasSumario = 38
+
+// entradaSumario é uma linha do sumário do documento completo
+type entradaSumario struct {
+	titulo string
+	nivel  int
+	link   int
+	pagina int
+}
+
+// GenerateCombinedReport gera o documento único com capa, sumário, matriz
+// resumo e os relatórios das localidades agrupados por setor. O sumário e a
+// matriz resumo têm links para as páginas, e o documento tem marcadores
+// setor → localidade.
+func (s *GofpdfService) GenerateCombinedReport(data *usecase.CombinedReportData, outputPath string) error {
+	inicio := time.Now()
+	pdf := gofpdf.New("P", "mm", "A4", "")
+	tr := pdf.UnicodeTranslatorFromDescriptor("")
+
+	pdf.SetTitle(data.Titulo, true)
+	pdf.SetAuthor("Phellipe Rodrigues", true)
+	pdf.SetFooterFunc(func() {
+		if pdf.PageNo() == 1 {
+			return
+		}
+		pdf.SetY(-12)
+		pdf.SetFont("Arial", "", 8)
+		pdf.SetTextColor(0, 0, 0)
+		pdf.CellFormat(0, 5, tr(fmt.Sprintf("Página %d", pdf.PageNo())), "", 0, "C", false, 0, "")
+	})
+
+	// As entradas do sumário são conhecidas antes da geração; as páginas
+	// são preenchidas à medida que as seções são desenhadas
+	resumo := &entradaSumario{titulo: "Resumo de Todas as Localidades", link: pdf.AddLink()}
+	entradas := []*entradaSumario{resumo}
+	linksLocalidades := make(map[string]int)
+	setores := make([]*entradaSumario, len(data.Setores))
+	localidades := make([][]*entradaSumario, len(data.Setores))
+	totalLocalidades := 0
+	for i, setor := range data.Setores {
+		setores[i] = &entradaSumario{titulo: setor.Nome, link: pdf.AddLink()}
+		entradas = append(entradas, setores[i])
+		for _, localidade := range setor.Localidades {
+			entrada := &entradaSumario{titulo: localidade.Localidade, nivel: 1, link: pdf.AddLink()}
+			linksLocalidades[localidade.Localidade] = entrada.link
+			localidades[i] = append(localidades[i], entrada)
+			entradas = append(entradas, entrada)
+			totalLocalidades++
+		}
+	}
+
+	// Capa
+	pdf.AddPage()
+	pdf.SetY(100)
+	pdf.SetFont("Arial", "B", 24)
+	pdf.CellFormat(0, 14, tr(data.Titulo), "", 1, "C", false, 0, "")
+	pdf.SetFont("Arial", "", 16)
+	pdf.CellFormat(0, 10, tr("Período: "+data.Periodo), "", 1, "C", false, 0, "")
+	pdf.Ln(10)
+	pdf.SetFont("Arial", "", 12)
+	pdf.CellFormat(0, 7, tr(fmt.Sprintf("%d setores, %d localidades", len(data.Setores), totalLocalidades)), "", 1, "C", false, 0, "")
+	pdf.CellFormat(0, 7, tr(fmt.Sprintf("Gerado em %s", data.Data.Format("02/01/2006 15:04"))), "", 1, "C", false, 0, "")
+
+	// Páginas reservadas para o sumário
+	primeiraSumario := pdf.PageNo() + 1
+	paginasSumario := (len(entradas) + linhasSumario - 1) / linhasSumario
+	// O marcador aponta para a primeira página do sumário
+	for i := 0; i < paginasSumario; i++ {
+		pdf.AddPage()
+		if i == 0 {
+			pdf.Bookmark(tr("Sumário"), 0, 0)
+		}
+	}
+
+	// Matriz resumo
+	pdf.AddPage()
+	s.abrirSecao(pdf, tr, resumo, 0)
+	pdf.SetFont("Arial", "B", 14)
+	pdf.CellFormat(0, 10, tr(resumo.titulo), "", 1, "", false, 0, "")
+	s.addSummaryMatrix(pdf, tr, data.Resumo, linksLocalidades)
+	if len(data.Resumo.Ranking) > 0 {
+		pdf.AddPage()
+		s.addRanking(pdf, tr, data.Resumo.Ranking, linksLocalidades)
+	}
+
+	// Setores e localidades
+	for i, setor := range data.Setores {
+		pdf.AddPage()
+		s.abrirSecao(pdf, tr, setores[i], 0)
+		pdf.SetY(40)
+		pdf.SetFont("Arial", "B", 20)
+		pdf.CellFormat(0, 12, tr(setor.Nome), "", 1, "C", false, 0, "")
+		pdf.SetFont("Arial", "", 12)
+		pdf.CellFormat(0, 8, tr(fmt.Sprintf("%d localidades", len(setor.Localidades))), "", 1, "C", false, 0, "")
+
+		for j, localidade := range setor.Localidades {
+			pdf.AddPage()
+			s.abrirSecao(pdf, tr, localidades[i][j], 1)
+			s.addLocalidade(pdf, tr, localidade)
+		}
+	}
+	ultima := pdf.PageNo()
+
+	// Sumário
+	pdf.SetAutoPageBreak(false, 0)
+	for i, entrada := range entradas {
+		if i%linhasSumario == 0 {
+			pdf.SetPage(primeiraSumario + i/linhasSumario)
+			pdf.SetXY(10, 15)
+			pdf.SetFont("Arial", "B", 16)
+			titulo := "Sumário"
+			if i > 0 {
+				titulo = "Sumário (continuação)"
+			}
+			pdf.CellFormat(0, 12, tr(titulo), "", 1, "", false, 0, "")
+		}
+
+		recuo := float64(entrada.nivel) * 8
+		estilo := "B"
+		if entrada.nivel > 0 {
+			estilo = ""
+		}
+		pdf.SetFont("Arial", estilo, 11)
+		pdf.SetX(10 + recuo)
+		pdf.CellFormat(170-recuo, 6.5, tr(entrada.titulo), "", 0, "", false, entrada.link, "")
+		pdf.CellFormat(20, 6.5, fmt.Sprintf("%d", entrada.pagina), "", 1, "R", false, entrada.link, "")
+	}
+	pdf.SetPage(ultima)
+
+	if err := ensureDir(outputPath); err != nil {
+		return err
+	}
+
+	return s.output(pdf, outputPath, inicio)
+}
+
+// abrirSecao registra a página atual como destino da entrada do sumário e
+// cria o marcador correspondente
+func (s *GofpdfService) abrirSecao(pdf *gofpdf.Fpdf, tr func(string) string, entrada *entradaSumario, nivel int) {
+	entrada.pagina = pdf.PageNo()
+	pdf.SetLink(entrada.link, 0, -1)
+	pdf.Bookmark(tr(entrada.titulo), nivel, 0)
+}
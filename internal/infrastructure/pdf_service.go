@@ -2,9 +2,12 @@ package infrastructure
 
 import (
 	"fmt"
+	"log/slog"
+	"math"
 	"os"
 	"path/filepath"
 	"strings"
+	"time"
 
 	"report/internal/domain"
 	"report/internal/usecase"
@@ -13,15 +16,27 @@ import (
 )
 
 // GofpdfService implementa o PDFService usando a biblioteca gofpdf
-type GofpdfService struct{}
+type GofpdfService struct {
+	logger *slog.Logger
+}
+
+// versaoModelo identifica o layout dos documentos nas chaves do cache;
+// altere ao mudar o layout para que os documentos sejam refeitos
+const versaoModelo = "4"
 
 // NewGofpdfService cria uma nova instância de GofpdfService
-func NewGofpdfService() *GofpdfService {
-	return &GofpdfService{}
+func NewGofpdfService(logger *slog.Logger) *GofpdfService {
+	return &GofpdfService{logger: logger.With("servico", "pdf")}
+}
+
+// Modelo retorna a versão do layout dos documentos
+func (s *GofpdfService) Modelo() string {
+	return versaoModelo
 }
 
 // GenerateLocalidadeReport gera o relatório de uma localidade
 func (s *GofpdfService) GenerateLocalidadeReport(data *usecase.ReportData, outputPath string) error {
+	inicio := time.Now()
 	pdf := gofpdf.New("P", "mm", "A4", "")
 	tr := pdf.UnicodeTranslatorFromDescriptor("")
 
@@ -29,56 +44,19 @@ func (s *GofpdfService) GenerateLocalidadeReport(data *usecase.ReportData, outpu
 	pdf.SetTitle(data.Titulo, true)
 	pdf.SetAuthor("Phellipe Rodrigues", true)
 	pdf.AddPage()
-
-	// Cabeçalho
-	pdf.SetFont("Arial", "B", 16)
-	pdf.Cell(40, 10, tr("Relatório de Trabalhos"))
-	pdf.Ln(10)
-	pdf.SetFont("Arial", "B", 14)
-	pdf.Cell(40, 10, tr("Localidade: "+data.Localidade))
-	pdf.Ln(15)
-
-	// Cabeçalhos da tabela
-	pdf.SetFont("Arial", "B", 12)
-	pdf.CellFormat(data.Config.LarguraLivro, 7, "Livro", "1", 0, "C", false, 0, "")
-	pdf.CellFormat(data.Config.LarguraTotalTrabalhos, 7, tr("Total Lançados"), "1", 0, "C", false, 0, "")
-	pdf.CellFormat(data.Config.LarguraApontamentos, 7, "Apontamentos", "1", 1, "C", false, 0, "")
-
-	// Dados da tabela
-	pdf.SetFont("Arial", "", 12)
-	for livro, summary := range data.Livros {
-		if summary.TotalTrabalhos < 1 {
-			pdf.SetTextColor(255, 0, 0)
-		}
-		pdf.CellFormat(data.Config.LarguraLivro, 7, tr(livro), "1", 0, "", false, 0, "")
-		pdf.CellFormat(data.Config.LarguraTotalTrabalhos, 7, fmt.Sprintf("%d", summary.TotalTrabalhos), "1", 0, "C", false, 0, "")
-		pdf.CellFormat(data.Config.LarguraApontamentos, 7, "", "1", 1, "R", false, 0, "")
-		pdf.SetTextColor(0, 0, 0)
-	}
-
-	// Adiciona alertas
-	s.addAlerts(pdf, tr, data.Livros)
-
-	// Adiciona data do relatório
-	pdf.SetTextColor(0, 0, 0)
-	pdf.Ln(10)
-	pdf.SetFont("Arial", "", 10)
-	pdf.MultiCell(0, 6, tr(fmt.Sprintf("Relatório gerado em %s", data.Data.Format("02/01/2006 15:04"))), "", "", false)
-
-	// Adiciona observações
-	s.addObservacoes(pdf, tr)
+	s.addLocalidade(pdf, tr, data)
 
 	// Cria o diretório se não existir
-	dir := strings.TrimSuffix(outputPath, filepath.Base(outputPath))
-	if err := os.MkdirAll(dir, os.ModePerm); err != nil {
-		return fmt.Errorf("erro ao criar diretório: %v", err)
+	if err := ensureDir(outputPath); err != nil {
+		return err
 	}
 
-	return pdf.OutputFileAndClose(outputPath)
+	return s.output(pdf, outputPath, inicio)
 }
 
 // GenerateSummaryReport gera o relatório resumo
 func (s *GofpdfService) GenerateSummaryReport(data *usecase.ReportData, outputPath string) error {
+	inicio := time.Now()
 	pdf := gofpdf.New("P", "mm", "A4", "")
 	tr := pdf.UnicodeTranslatorFromDescriptor("")
 
@@ -86,10 +64,126 @@ func (s *GofpdfService) GenerateSummaryReport(data *usecase.ReportData, outputPa
 	pdf.SetTitle(data.Titulo, true)
 	pdf.SetAuthor("Phellipe Rodrigues", true)
 	pdf.AddPage()
+	s.addSummaryMatrix(pdf, tr, data, nil)
+	if len(data.Ranking) > 0 {
+		pdf.AddPage()
+		s.addRanking(pdf, tr, data.Ranking, nil)
+	}
+	if data.Calendario != nil {
+		pdf.AddPage()
+		s.addCalendario(pdf, tr, data.Calendario)
+	}
+
+	if err := ensureDir(outputPath); err != nil {
+		return err
+	}
+
+	return s.output(pdf, outputPath, inicio)
+}
+
+// GenerateDiffReport gera o relatório de diferenças entre duas entradas
+func (s *GofpdfService) GenerateDiffReport(diff *usecase.Diferenca, outputPath string) error {
+	inicio := time.Now()
+	pdf := gofpdf.New("P", "mm", "A4", "")
+	tr := pdf.UnicodeTranslatorFromDescriptor("")
+
+	titulo := fmt.Sprintf("Diferenças: %s x %s", diff.Antes, diff.Depois)
+	pdf.SetTitle(tr(titulo), true)
+	pdf.SetAuthor("Phellipe Rodrigues", true)
+	pdf.AddPage()
+
+	pdf.SetFont("Arial", "B", 16)
+	pdf.Cell(40, 10, tr("Relatório de Diferenças"))
+	pdf.Ln(10)
+	pdf.SetFont("Arial", "", 10)
+	pdf.MultiCell(0, 6, tr(fmt.Sprintf("Antes: %s", diff.Antes)), "", "", false)
+	pdf.MultiCell(0, 6, tr(fmt.Sprintf("Depois: %s", diff.Depois)), "", "", false)
+	pdf.Ln(4)
+
+	if diff.Vazia() {
+		pdf.SetFont("Arial", "", 12)
+		pdf.MultiCell(0, 8, tr("Nenhuma diferença encontrada."), "", "", false)
+	}
+
+	for _, loc := range diff.Localidades {
+		titulo := loc.Localidade
+		if loc.Situacao != "" {
+			titulo = fmt.Sprintf("%s (%s)", loc.Localidade, loc.Situacao)
+		}
+		pdf.SetFont("Arial", "B", 12)
+		pdf.MultiCell(0, 8, tr(titulo), "", "", false)
+
+		if len(loc.Livros) > 0 {
+			pdf.SetFont("Arial", "B", 10)
+			pdf.CellFormat(100, 6, "Livro", "1", 0, "C", false, 0, "")
+			pdf.CellFormat(25, 6, "Antes", "1", 0, "C", false, 0, "")
+			pdf.CellFormat(25, 6, "Depois", "1", 0, "C", false, 0, "")
+			pdf.CellFormat(40, 6, tr("Variação"), "1", 1, "C", false, 0, "")
+
+			pdf.SetFont("Arial", "", 10)
+			for _, livro := range loc.Livros {
+				pdf.CellFormat(100, 6, tr(livro.Livro), "1", 0, "", false, 0, "")
+				pdf.CellFormat(25, 6, fmt.Sprintf("%d", livro.Antes), "1", 0, "C", false, 0, "")
+				pdf.CellFormat(25, 6, fmt.Sprintf("%d", livro.Depois), "1", 0, "C", false, 0, "")
+				if livro.Variacao() < 0 {
+					pdf.SetTextColor(255, 0, 0)
+				}
+				pdf.CellFormat(40, 6, tr(fmt.Sprintf("%+d (%s)", livro.Variacao(), livro.Situacao)), "1", 1, "C", false, 0, "")
+				pdf.SetTextColor(0, 0, 0)
+			}
+		}
+
+		if len(loc.Voluntarios) > 0 {
+			pdf.Ln(2)
+			pdf.SetFont("Arial", "B", 10)
+			pdf.CellFormat(70, 6, tr("Voluntário"), "1", 0, "C", false, 0, "")
+			pdf.CellFormat(70, 6, "Livro", "1", 0, "C", false, 0, "")
+			pdf.CellFormat(25, 6, tr("Lançamentos"), "1", 0, "C", false, 0, "")
+			pdf.CellFormat(25, 6, tr("Situação"), "1", 1, "C", false, 0, "")
+
+			pdf.SetFont("Arial", "", 10)
+			for _, voluntario := range loc.Voluntarios {
+				if voluntario.Situacao == usecase.SituacaoRemovido {
+					pdf.SetTextColor(255, 0, 0)
+				}
+				pdf.CellFormat(70, 6, ajustarTexto(pdf, tr, voluntario.Voluntario, 68), "1", 0, "", false, 0, "")
+				pdf.CellFormat(70, 6, ajustarTexto(pdf, tr, voluntario.Livro, 68), "1", 0, "", false, 0, "")
+				pdf.CellFormat(25, 6, fmt.Sprintf("%d", voluntario.Lancamentos), "1", 0, "C", false, 0, "")
+				pdf.CellFormat(25, 6, tr(voluntario.Situacao), "1", 1, "C", false, 0, "")
+				pdf.SetTextColor(0, 0, 0)
+			}
+		}
+
+		pdf.SetFont("Arial", "", 10)
+		for _, alerta := range loc.AlertasNovos {
+			pdf.SetTextColor(237, 81, 14)
+			pdf.MultiCell(0, 6, tr("+ "+alerta), "", "", false)
+		}
+		for _, alerta := range loc.AlertasResolvidos {
+			pdf.SetTextColor(0, 128, 0)
+			pdf.MultiCell(0, 6, tr("- "+alerta+" (resolvido)"), "", "", false)
+		}
+		pdf.SetTextColor(0, 0, 0)
+		pdf.Ln(4)
+	}
+
+	if err := ensureDir(outputPath); err != nil {
+		return err
+	}
+
+	return s.output(pdf, outputPath, inicio)
+}
 
+// addSummaryMatrix desenha a matriz de localidades por livro. Com links, o
+// nome de cada localidade aponta para a página do seu relatório.
+func (s *GofpdfService) addSummaryMatrix(pdf *gofpdf.Fpdf, tr func(string) string, data *usecase.ReportData, links map[string]int) {
 	// Cabeçalhos
 	pdf.SetFont("Arial", "B", 8)
-	pdf.CellFormat(50, 50, "Localidade", "1", 0, "C", false, 0, "")
+	cabecalho := "Localidade"
+	if data.Metrica != "" && data.Metrica != domain.MetricaLancamentos {
+		cabecalho = fmt.Sprintf("Localidade (%s)", data.Metrica.Unidade())
+	}
+	pdf.CellFormat(50, 50, tr(cabecalho), "1", 0, "C", false, 0, "")
 
 	// Cabeçalhos das colunas para os livros
 	livrosEncontrados := make(map[string]bool)
@@ -99,9 +193,8 @@ func (s *GofpdfService) GenerateSummaryReport(data *usecase.ReportData, outputPa
 		}
 	}
 
-	livroOrdem := make([]string, 0, len(livrosEncontrados))
-	for livro := range livrosEncontrados {
-		livroOrdem = append(livroOrdem, livro)
+	livroOrdem := usecase.OrdenarChaves(livrosEncontrados)
+	for _, livro := range livroOrdem {
 		x, y := pdf.GetXY()
 		pdf.TransformBegin()
 		pdf.TransformRotate(90, x+0.5, y+22.5)
@@ -109,59 +202,755 @@ func (s *GofpdfService) GenerateSummaryReport(data *usecase.ReportData, outputPa
 		pdf.TransformEnd()
 		pdf.SetXY(x+7, y)
 	}
+	comSaude := len(data.SaudeLocalidades) > 0
+	if comSaude {
+		x, y := pdf.GetXY()
+		pdf.TransformBegin()
+		pdf.TransformRotate(90, x+0.5, y+22.5)
+		pdf.CellFormat(12, 50, tr("Saúde"), "", 0, "C", false, 0, "")
+		pdf.TransformEnd()
+		pdf.SetXY(x+9, y)
+	}
 	pdf.Ln(-1)
 
 	// Dados
 	pdf.SetFont("Arial", "", 8)
-	for localidade, livros := range data.Localidades {
-		pdf.CellFormat(50, 5, localidade, "1", 0, "", false, 0, "")
+	for _, localidade := range usecase.OrdenarChaves(data.Localidades) {
+		livros := data.Localidades[localidade]
+		if link, exists := links[localidade]; exists {
+			pdf.SetTextColor(0, 0, 160)
+			pdf.CellFormat(50, 5, tr(localidade), "1", 0, "", false, link, "")
+			pdf.SetTextColor(0, 0, 0)
+		} else {
+			pdf.CellFormat(50, 5, tr(localidade), "1", 0, "", false, 0, "")
+		}
 		for _, livro := range livroOrdem {
 			if summary, exists := livros[livro]; exists {
-				if summary.TotalTrabalhos == 0 {
+				valor := summary.Valor(data.Metrica)
+				if valor == 0 {
 					pdf.SetTextColor(255, 0, 0)
 				}
-				pdf.CellFormat(7, 5, fmt.Sprintf("%d", summary.TotalTrabalhos), "1", 0, "C", false, 0, "")
+				pdf.CellFormat(7, 5, fmt.Sprintf("%.0f", valor), "1", 0, "C", false, 0, "")
+				pdf.SetTextColor(0, 0, 0)
+			} else if data.LivrosMap[localidade][livro] {
+				// Livro cadastrado para a localidade e sem apontamentos
+				pdf.SetTextColor(255, 0, 0)
+				pdf.CellFormat(7, 5, "0", "1", 0, "C", false, 0, "")
 				pdf.SetTextColor(0, 0, 0)
 			} else {
 				pdf.CellFormat(7, 5, "X", "1", 0, "C", false, 0, "")
 			}
 		}
+		if comSaude {
+			if saude := data.SaudeLocalidades[localidade]; saude != nil {
+				pdf.SetFillColor(corSaude(saude))
+				pdf.CellFormat(9, 5, fmt.Sprintf("%d", saude.Nota), "1", 0, "C", true, 0, "")
+				pdf.SetFillColor(255, 255, 255)
+			} else {
+				pdf.CellFormat(9, 5, "-", "1", 0, "C", false, 0, "")
+			}
+		}
 		pdf.Ln(-1)
 	}
 
-	return pdf.OutputFileAndClose(outputPath)
+	// Totais das localidades do resumo
+	if data.Totais != nil {
+		pdf.SetFont("Arial", "B", 8)
+		pdf.CellFormat(50, 5, "Total", "1", 0, "", false, 0, "")
+		for _, livro := range livroOrdem {
+			valor := 0.0
+			if summary, exists := data.Totais[livro]; exists {
+				valor = summary.Valor(data.Metrica)
+			}
+			pdf.CellFormat(7, 5, fmt.Sprintf("%.0f", valor), "1", 0, "C", false, 0, "")
+		}
+		if comSaude {
+			// A nota não se soma: a linha de totais traz a média das localidades
+			soma, n := 0, 0
+			for localidade := range data.Localidades {
+				if saude := data.SaudeLocalidades[localidade]; saude != nil {
+					soma += saude.Nota
+					n++
+				}
+			}
+			if n > 0 {
+				media := &usecase.SaudeLocalidade{Nota: int(math.Round(float64(soma) / float64(n)))}
+				pdf.SetFillColor(corSaude(media))
+				pdf.CellFormat(9, 5, fmt.Sprintf("%d", media.Nota), "1", 0, "C", true, 0, "")
+				pdf.SetFillColor(255, 255, 255)
+			} else {
+				pdf.CellFormat(9, 5, "-", "1", 0, "C", false, 0, "")
+			}
+		}
+		pdf.Ln(-1)
+		pdf.SetFont("Arial", "", 8)
+	}
 }
 
-func (s *GofpdfService) addAlerts(pdf *gofpdf.Fpdf, tr func(string) string, livros map[string]*domain.Summary) {
-	_, adminExists := livros["4 - ADMINISTRAÇÃO"]
-	mp, manutencaoExists := livros["2 - MANUTENÇÃO PREVENTIVA"]
-	_, brigadaExists := livros["4 - BRIGADA DE INCÊNDIO"]
+// addLocalidade desenha o relatório de uma localidade a partir da página atual
+func (s *GofpdfService) addLocalidade(pdf *gofpdf.Fpdf, tr func(string) string, data *usecase.ReportData) {
+	// Cabeçalho
+	pdf.SetFont("Arial", "B", 16)
+	pdf.Cell(40, 10, tr("Relatório de Trabalhos"))
+	pdf.Ln(10)
+	pdf.SetFont("Arial", "B", 14)
+	pdf.Cell(40, 10, tr("Localidade: "+data.Localidade))
+	if data.Saude != nil {
+		s.addSeloSaude(pdf, tr, data.Saude)
+	}
+	pdf.Ln(15)
 
-	if !adminExists || (!manutencaoExists || (manutencaoExists && mp.TotalTrabalhos < 8)) || !brigadaExists {
-		pdf.SetFont("Arial", "B", 12)
-		pdf.SetTextColor(255, 0, 0)
-		pdf.MultiCell(0, 8, tr("PONTOS DE ATENÇÃO:"), "", "", false)
+	// Cabeçalhos da tabela
+	pdf.SetFont("Arial", "B", 10)
+	pdf.CellFormat(data.Config.LarguraLivro, 7, "Livro", "1", 0, "C", false, 0, "")
+	pdf.CellFormat(data.Config.LarguraTotalTrabalhos, 7, tr("Lançamentos"), "1", 0, "C", false, 0, "")
+	pdf.CellFormat(data.Config.LarguraHoras, 7, "Horas", "1", 0, "C", false, 0, "")
+	pdf.CellFormat(data.Config.LarguraVoluntarios, 7, tr("Voluntários"), "1", 0, "C", false, 0, "")
+	if data.Cumprimento == nil {
+		pdf.CellFormat(data.Config.LarguraApontamentos, 7, "Apontamentos", "1", 1, "C", false, 0, "")
+	} else {
+		pdf.CellFormat(data.Config.LarguraApontamentos, 7, "Apontamentos", "1", 0, "C", false, 0, "")
+		pdf.CellFormat(data.Config.LarguraMeta, 7, "Meta", "1", 0, "C", false, 0, "")
+		pdf.CellFormat(data.Config.LarguraCumprimento, 7, "Cumpr.", "1", 1, "C", false, 0, "")
+	}
 
-		if !adminExists {
-			pdf.SetTextColor(237, 81, 14)
-			pdf.MultiCell(0, 8, tr("> Não há apontamentos de ADMINISTRAÇÃO."), "", "", false)
+	// Dados da tabela; livros cadastrados ou com meta e sem apontamentos
+	// também são listados
+	livros := make(map[string]bool, len(data.Livros))
+	for livro := range data.Livros {
+		livros[livro] = true
+	}
+	for livro := range data.Esperados {
+		livros[livro] = true
+	}
+	if data.Cumprimento != nil {
+		for livro := range data.Cumprimento.Livros {
+			livros[livro] = true
 		}
-		if !manutencaoExists || (manutencaoExists && mp.TotalTrabalhos < 8) {
-			pdf.SetTextColor(237, 81, 14)
-			pdf.MultiCell(0, 8, tr("> Menos de 8 apontamentos de MANUTENÇÃO."), "", "", false)
+	}
+
+	semCadastro := make(map[string]bool, len(data.SemCadastro))
+	for _, livro := range data.SemCadastro {
+		semCadastro[livro] = true
+	}
+
+	pdf.SetFont("Arial", "", 10)
+	for _, livro := range usecase.OrdenarChaves(livros) {
+		summary, exists := data.Livros[livro]
+		if !exists {
+			summary = &domain.Summary{}
 		}
-		if !brigadaExists {
+		nome := livro
+		if semCadastro[livro] {
+			nome += " *"
 			pdf.SetTextColor(237, 81, 14)
-			pdf.MultiCell(0, 8, tr("> Não há apontamentos de BRIGADA DE INCÊNDIO."), "", "", false)
 		}
+		if summary.TotalTrabalhos < 1 {
+			pdf.SetTextColor(255, 0, 0)
+		}
+		pdf.CellFormat(data.Config.LarguraLivro, 7, tr(nome), "1", 0, "", false, 0, "")
+		pdf.CellFormat(data.Config.LarguraTotalTrabalhos, 7, fmt.Sprintf("%d", summary.TotalTrabalhos), "1", 0, "C", false, 0, "")
+		pdf.CellFormat(data.Config.LarguraHoras, 7, formatarHoras(summary.Horas), "1", 0, "C", false, 0, "")
+		pdf.CellFormat(data.Config.LarguraVoluntarios, 7, fmt.Sprintf("%d", summary.TotalVoluntarios()), "1", 0, "C", false, 0, "")
+		pdf.SetTextColor(0, 0, 0)
+		if data.Cumprimento == nil {
+			s.celulaApontamento(pdf, tr, data.Config.LarguraApontamentos, 7, data.Apontamentos[livro], 1)
+			continue
+		}
+		s.celulaApontamento(pdf, tr, data.Config.LarguraApontamentos, 7, data.Apontamentos[livro], 0)
+		if cumprimento, exists := data.Cumprimento.Livros[livro]; exists {
+			pdf.CellFormat(data.Config.LarguraMeta, 7, tr(formatarMeta(cumprimento.Meta)), "1", 0, "C", false, 0, "")
+			s.celulaCumprimento(pdf, data.Config.LarguraCumprimento, 7, cumprimento.Percentual, 1)
+		} else {
+			pdf.CellFormat(data.Config.LarguraMeta, 7, "-", "1", 0, "C", false, 0, "")
+			pdf.CellFormat(data.Config.LarguraCumprimento, 7, "-", "1", 1, "C", false, 0, "")
+		}
+	}
+
+	if len(data.Apontamentos) > 0 {
+		pdf.SetFont("Arial", "", 8)
+		pdf.MultiCell(0, 5, tr("Apontamentos: data do último lançamento (dias desde então) · voluntários; a seta compara com o período anterior."), "", "", false)
+	}
+
+	if len(data.SemCadastro) > 0 {
+		pdf.SetFont("Arial", "", 8)
+		pdf.SetTextColor(237, 81, 14)
+		pdf.MultiCell(0, 5, tr("* Livro com apontamentos que não está cadastrado para a localidade."), "", "", false)
+		pdf.SetTextColor(0, 0, 0)
+	}
+
+	if data.Cumprimento != nil {
+		pdf.Ln(2)
+		pdf.SetFont("Arial", "B", 10)
+		pdf.CellFormat(60, 7, tr("Cumprimento geral das metas:"), "", 0, "", false, 0, "")
+		s.celulaCumprimento(pdf, 20, 7, data.Cumprimento.Percentual, 1)
+		pdf.Ln(3)
+	}
+
+	// Adiciona alertas
+	s.addAlerts(pdf, tr, data.Alertas)
+
+	// Adiciona data do relatório
+	pdf.SetTextColor(0, 0, 0)
+	pdf.Ln(10)
+	pdf.SetFont("Arial", "", 10)
+	pdf.MultiCell(0, 6, tr(fmt.Sprintf("Relatório gerado em %s", data.Data.Format("02/01/2006 15:04"))), "", "", false)
+
+	// Adiciona observações
+	s.addObservacoes(pdf, tr, data.Observacoes)
+
+	// Nota de saúde, calendário, plano de manutenção, cobertura, escala de
+	// voluntários e brigada em página própria
+	var secoes []func()
+	if data.Saude != nil {
+		secoes = append(secoes, func() { s.addComposicaoSaude(pdf, tr, data.Saude) })
+	}
+	if data.Calendario != nil {
+		secoes = append(secoes, func() { s.addCalendario(pdf, tr, data.Calendario) })
+	}
+	if data.Manutencao != nil && len(data.Manutencao.Tarefas) > 0 {
+		secoes = append(secoes, func() { s.addManutencao(pdf, tr, data.Manutencao) })
+	}
+	if data.Cobertura != nil && len(data.Cobertura.Escala) > 0 {
+		secoes = append(secoes, func() { s.addCobertura(pdf, tr, data.Cobertura) })
+	}
+	if data.Brigada != nil && len(data.Brigada.Voluntarios) > 0 {
+		secoes = append(secoes, func() { s.addBrigada(pdf, tr, data.Brigada) })
+	}
+	for i, secao := range secoes {
+		if i == 0 {
+			pdf.AddPage()
+		} else {
+			pdf.Ln(6)
+		}
+		secao()
+	}
+}
+
+// addSeloSaude desenha a nota de saúde no canto superior direito da página,
+// na cor da faixa da nota
+func (s *GofpdfService) addSeloSaude(pdf *gofpdf.Fpdf, tr func(string) string, saude *usecase.SaudeLocalidade) {
+	const largura, altura = 36.0, 18.0
+
+	x, y := pdf.GetXY()
+	larguraPagina, _ := pdf.GetPageSize()
+	_, topo, direita, _ := pdf.GetMargins()
+	xSelo := larguraPagina - direita - largura
+
+	pdf.SetFillColor(corSaude(saude))
+	pdf.RoundedRect(xSelo, topo, largura, altura, 3, "1234", "FD")
+	pdf.SetFillColor(255, 255, 255)
+	pdf.SetXY(xSelo, topo+1)
+	pdf.SetFont("Arial", "B", 7)
+	pdf.CellFormat(largura, 4, tr("SAÚDE DA LOCALIDADE"), "", 2, "C", false, 0, "")
+	pdf.SetFont("Arial", "B", 16)
+	pdf.CellFormat(largura, 8, fmt.Sprintf("%d/100", saude.Nota), "", 2, "C", false, 0, "")
+	pdf.SetFont("Arial", "", 8)
+	pdf.CellFormat(largura, 4, tr(saude.Faixa()), "", 0, "C", false, 0, "")
+	pdf.SetXY(x, y)
+	pdf.SetFont("Arial", "B", 14)
+}
+
+// addComposicaoSaude explica a nota de saúde: o valor, o peso e os pontos de
+// cada componente
+func (s *GofpdfService) addComposicaoSaude(pdf *gofpdf.Fpdf, tr func(string) string, saude *usecase.SaudeLocalidade) {
+	pdf.SetFont("Arial", "B", 14)
+	pdf.CellFormat(0, 10, tr(fmt.Sprintf("Nota de Saúde: %d/100 (%s)", saude.Nota, saude.Faixa())), "", 1, "", false, 0, "")
+	pdf.SetFont("Arial", "", 10)
+	pdf.MultiCell(0, 6, tr("Cada componente vale de 0 a 100 e contribui com a sua parte do peso total. "+
+		"Componentes sem dados ficam de fora e o peso deles é dividido entre os demais. "+
+		"Nos alertas, cada um desconta 40 pontos se de severidade alta, 20 se média e 10 se baixa."), "", "", false)
+	pdf.Ln(3)
+
+	pdf.SetFont("Arial", "B", 10)
+	pdf.CellFormat(44, 7, "Componente", "1", 0, "C", false, 0, "")
+	pdf.CellFormat(18, 7, "Valor", "1", 0, "C", false, 0, "")
+	pdf.CellFormat(16, 7, "Peso", "1", 0, "C", false, 0, "")
+	pdf.CellFormat(18, 7, "Pontos", "1", 0, "C", false, 0, "")
+	pdf.CellFormat(94, 7, "Detalhe", "1", 1, "C", false, 0, "")
+	pdf.SetFont("Arial", "", 9)
+	total := 0.0
+	for _, componente := range saude.Componentes {
+		valor, pontos := "-", "-"
+		if componente.Aplicavel {
+			valor = fmt.Sprintf("%.0f", componente.Valor)
+			pontos = fmt.Sprintf("%.1f", componente.Pontos)
+			total += componente.Pontos
+		} else {
+			pdf.SetTextColor(128, 128, 128)
+		}
+		pdf.CellFormat(44, 7, tr(componente.Nome), "1", 0, "", false, 0, "")
+		pdf.CellFormat(18, 7, valor, "1", 0, "C", false, 0, "")
+		pdf.CellFormat(16, 7, fmt.Sprintf("%.0f", componente.Peso), "1", 0, "C", false, 0, "")
+		pdf.CellFormat(18, 7, pontos, "1", 0, "C", false, 0, "")
+		pdf.CellFormat(94, 7, ajustarTexto(pdf, tr, componente.Detalhe, 92), "1", 1, "", false, 0, "")
+		pdf.SetTextColor(0, 0, 0)
+	}
+	pdf.SetFont("Arial", "B", 9)
+	pdf.CellFormat(78, 7, "Nota", "1", 0, "R", false, 0, "")
+	pdf.SetFillColor(corSaude(saude))
+	pdf.CellFormat(18, 7, fmt.Sprintf("%.0f", math.Round(total)), "1", 0, "C", true, 0, "")
+	pdf.SetFillColor(255, 255, 255)
+	pdf.CellFormat(94, 7, "", "1", 1, "", false, 0, "")
+	pdf.SetFont("Arial", "", 10)
+}
+
+// corSaude é o fundo verde (boa), amarelo (atenção) ou vermelho (crítica) da nota
+func corSaude(saude *usecase.SaudeLocalidade) (int, int, int) {
+	switch saude.Faixa() {
+	case "boa":
+		return 198, 239, 206
+	case "atenção":
+		return 255, 235, 156
+	default:
+		return 255, 199, 206
+	}
+}
+
+// addCalendario desenha o mapa de calor do período: uma linha por semana, de
+// segunda a domingo, com o verde mais escuro nos dias de maior atividade. Os
+// fins de semana têm borda azul e os dias de culto, um marcador roxo.
+func (s *GofpdfService) addCalendario(pdf *gofpdf.Fpdf, tr func(string) string, calendario *usecase.Calendario) {
+	const larguraDia, alturaDia, alturaCabecalho = 190.0 / 7, 13.0, 6.0
+
+	unidade := "pelos lançamentos"
+	maximo := fmt.Sprintf("%.0f", calendario.Maximo)
+	if calendario.Metrica == domain.MetricaHoras {
+		unidade = "pelas horas"
+		maximo = formatarHoras(calendario.Maximo)
+	}
+	pdf.SetFont("Arial", "B", 14)
+	pdf.CellFormat(0, 10, tr("Calendário de Atividade"), "", 1, "", false, 0, "")
+	pdf.SetFont("Arial", "", 10)
+	pdf.MultiCell(0, 6, tr(fmt.Sprintf("Intensidade %s de cada dia; o dia de maior atividade teve %s. "+
+		"Fins de semana com borda azul e dias de culto com marcador roxo.", unidade, maximo)), "", "", false)
+	pdf.Ln(3)
+
+	cultos := make(map[time.Weekday]bool)
+	for _, dia := range calendario.Dias {
+		if dia.Culto {
+			cultos[dia.Data.Weekday()] = true
+		}
+	}
+	pdf.SetFont("Arial", "B", 9)
+	for i, nome := range []string{"Seg", "Ter", "Qua", "Qui", "Sex", "Sáb", "Dom"} {
+		fimDeSemana := i >= 5
+		if fimDeSemana {
+			pdf.SetFillColor(221, 231, 247)
+		}
+		if cultos[time.Weekday((i+1)%7)] {
+			nome += " (culto)"
+		}
+		ln := 0
+		if i == 6 {
+			ln = 1
+		}
+		pdf.CellFormat(larguraDia, alturaCabecalho, tr(nome), "1", ln, "C", fimDeSemana, 0, "")
+		pdf.SetFillColor(255, 255, 255)
+	}
+
+	x0, y0 := pdf.GetXY()
+	semana := 0
+	for i, dia := range calendario.Dias {
+		coluna := (int(dia.Data.Weekday()) + 6) % 7
+		if i > 0 && coluna == 0 {
+			semana++
+		}
+		x, y := x0+float64(coluna)*larguraDia, y0+float64(semana)*alturaDia
+
+		r, g, b := corIntensidade(dia.Intensidade)
+		pdf.SetFillColor(r, g, b)
+		pdf.SetDrawColor(0, 0, 0)
+		pdf.Rect(x, y, larguraDia, alturaDia, "FD")
+		if dia.FimDeSemana {
+			pdf.SetDrawColor(50, 90, 200)
+			pdf.SetLineWidth(0.6)
+			pdf.Rect(x+0.4, y+0.4, larguraDia-0.8, alturaDia-0.8, "D")
+			pdf.SetLineWidth(0.2)
+			pdf.SetDrawColor(0, 0, 0)
+		}
+		if dia.Culto {
+			pdf.SetFillColor(128, 60, 160)
+			pdf.Circle(x+larguraDia-2.5, y+2.5, 1.2, "F")
+		}
+
+		if dia.Intensidade > 0.6 {
+			pdf.SetTextColor(255, 255, 255)
+		}
+		pdf.SetFont("Arial", "", 7)
+		pdf.SetXY(x+0.8, y+0.8)
+		pdf.CellFormat(8, 3, fmt.Sprintf("%d", dia.Data.Day()), "", 0, "L", false, 0, "")
+		if valor := calendario.Valor(dia); valor > 0 {
+			texto := fmt.Sprintf("%.0f", valor)
+			if calendario.Metrica == domain.MetricaHoras {
+				texto = formatarHoras(valor)
+			}
+			pdf.SetFont("Arial", "B", 9)
+			pdf.SetXY(x, y+5)
+			pdf.CellFormat(larguraDia, 6, texto, "", 0, "C", false, 0, "")
+		}
+		pdf.SetTextColor(0, 0, 0)
+	}
+	pdf.SetFillColor(255, 255, 255)
+	pdf.SetXY(x0, y0+float64(semana+1)*alturaDia+2)
+
+	// Legenda da escala de cores
+	pdf.SetFont("Arial", "", 8)
+	pdf.CellFormat(14, 4, "Menos", "", 0, "R", false, 0, "")
+	for _, intensidade := range []float64{0, 0.25, 0.5, 0.75, 1} {
+		r, g, b := corIntensidade(intensidade)
+		pdf.SetFillColor(r, g, b)
+		pdf.CellFormat(6, 4, "", "1", 0, "C", true, 0, "")
+	}
+	pdf.SetFillColor(255, 255, 255)
+	pdf.CellFormat(14, 4, "Mais", "", 1, "L", false, 0, "")
+	pdf.SetFont("Arial", "", 10)
+}
+
+// corIntensidade vai do branco, sem atividade, ao verde escuro do dia de
+// maior atividade
+func corIntensidade(intensidade float64) (int, int, int) {
+	if intensidade <= 0 {
+		return 255, 255, 255
+	}
+	misturar := func(de, ate int) int {
+		return de + int(math.Round(float64(ate-de)*intensidade))
+	}
+	return misturar(214, 20), misturar(240, 110), misturar(220, 50)
+}
+
+// addManutencao desenha a grade do plano de manutenção: uma linha por tarefa
+// e uma coluna por semana do período. Tarefas mensais e trimestrais ocupam a
+// linha inteira.
+func (s *GofpdfService) addManutencao(pdf *gofpdf.Fpdf, tr func(string) string, plano *usecase.PlanoLocalidade) {
+	const larguraTarefa, larguraGrade = 50.0, 140.0
+
+	pdf.SetFont("Arial", "B", 14)
+	pdf.CellFormat(0, 10, tr("Plano de Manutenção Preventiva"), "", 1, "", false, 0, "")
+	pdf.SetFont("Arial", "", 10)
+	pdf.MultiCell(0, 6, tr(fmt.Sprintf("%d períodos com manutenção e %d sem manutenção. "+
+		"Cada célula traz os apontamentos do livro da tarefa na semana, no mês ou no trimestre.",
+		plano.Cumpridas, plano.SemManutencao)), "", "", false)
+	pdf.Ln(3)
+
+	larguraSemana := larguraGrade / float64(len(plano.Semanas))
+	pdf.SetFont("Arial", "B", 8)
+	pdf.CellFormat(larguraTarefa, 7, "Tarefa", "1", 0, "C", false, 0, "")
+	for i, semana := range plano.Semanas {
+		ln := 0
+		if i == len(plano.Semanas)-1 {
+			ln = 1
+		}
+		pdf.CellFormat(larguraSemana, 7, tr(fmt.Sprintf("%s a %s", semana.Inicio.Format("02/01"), semana.Fim.Format("02/01"))), "1", ln, "C", false, 0, "")
+	}
+
+	for _, tarefa := range plano.Tarefas {
+		pdf.SetFont("Arial", "", 9)
+		pdf.CellFormat(larguraTarefa, 7, ajustarTexto(pdf, tr, fmt.Sprintf("%s (%s)", tarefa.Tarefa, tarefa.Frequencia), larguraTarefa-2), "1", 0, "", false, 0, "")
+		largura := larguraSemana
+		if tarefa.Frequencia != domain.FrequenciaSemanal {
+			largura = larguraGrade
+		}
+		for i, janela := range tarefa.Janelas {
+			ln := 0
+			if i == len(tarefa.Janelas)-1 {
+				ln = 1
+			}
+			texto := fmt.Sprintf("%d", janela.Lancamentos)
+			switch tarefa.Frequencia {
+			case domain.FrequenciaMensal:
+				texto = fmt.Sprintf("Mês: %d apontamentos", janela.Lancamentos)
+			case domain.FrequenciaTrimestral:
+				texto = fmt.Sprintf("Trimestre %s a %s: %d apontamentos",
+					janela.Inicio.Format("02/01"), janela.Fim.Format("02/01"), janela.Lancamentos)
+			}
+			if janela.Situacao == usecase.JanelaFutura {
+				texto = "-"
+			}
+			s.celulaJanela(pdf, tr, largura, 7, texto, janela.Situacao, ln)
+		}
+	}
+
+	pdf.Ln(2)
+	pdf.SetFont("Arial", "", 8)
+	for _, situacao := range []usecase.SituacaoJanela{usecase.JanelaCumprida, usecase.JanelaSemManutencao, usecase.JanelaEmAndamento, usecase.JanelaFutura} {
+		s.celulaJanela(pdf, tr, 4, 4, "", situacao, 0)
+		pdf.CellFormat(30, 4, tr(string(situacao)), "", 0, "", false, 0, "")
+	}
+	pdf.Ln(4)
+	pdf.SetFont("Arial", "", 10)
+}
+
+// celulaJanela desenha uma célula da grade de manutenção com fundo verde
+// (cumprida), vermelho (sem manutenção), amarelo (em andamento) ou cinza (futura)
+func (s *GofpdfService) celulaJanela(pdf *gofpdf.Fpdf, tr func(string) string, largura, altura float64, texto string, situacao usecase.SituacaoJanela, ln int) {
+	switch situacao {
+	case usecase.JanelaCumprida:
+		pdf.SetFillColor(198, 239, 206)
+	case usecase.JanelaSemManutencao:
+		pdf.SetFillColor(255, 199, 206)
+	case usecase.JanelaEmAndamento:
+		pdf.SetFillColor(255, 235, 156)
+	default:
+		pdf.SetFillColor(230, 230, 230)
+	}
+	pdf.CellFormat(largura, altura, tr(texto), "1", ln, "C", true, 0, "")
+	pdf.SetFillColor(255, 255, 255)
+}
+
+// addBrigada desenha a situação dos treinamentos da Brigada de Incêndio, com
+// fundo verde (válido), amarelo (a vencer) ou vermelho (vencido)
+func (s *GofpdfService) addBrigada(pdf *gofpdf.Fpdf, tr func(string) string, brigada *usecase.BrigadaLocalidade) {
+	pdf.SetFont("Arial", "B", 14)
+	pdf.CellFormat(0, 10, tr("Brigada de Incêndio"), "", 1, "", false, 0, "")
+	pdf.SetFont("Arial", "", 10)
+	pdf.MultiCell(0, 6, tr(fmt.Sprintf("Treinamentos válidos por %d meses a partir do último apontamento no livro da brigada. "+
+		"Válidos: %d · a vencer em até 30 dias: %d · vencidos: %d.",
+		brigada.ValidadeMeses, brigada.Validos, brigada.AVencer, brigada.Vencidos)), "", "", false)
+	pdf.Ln(3)
+
+	pdf.SetFont("Arial", "B", 10)
+	pdf.CellFormat(70, 7, tr("Voluntário"), "1", 0, "C", false, 0, "")
+	pdf.CellFormat(45, 7, tr("Último treinamento"), "1", 0, "C", false, 0, "")
+	pdf.CellFormat(45, 7, tr("Válido até"), "1", 0, "C", false, 0, "")
+	pdf.CellFormat(30, 7, tr("Situação"), "1", 1, "C", false, 0, "")
+	pdf.SetFont("Arial", "", 10)
+	for _, voluntario := range brigada.Voluntarios {
+		pdf.CellFormat(70, 7, ajustarTexto(pdf, tr, voluntario.Nome, 68), "1", 0, "", false, 0, "")
+		pdf.CellFormat(45, 7, voluntario.Ultimo.Format("02/01/2006"), "1", 0, "C", false, 0, "")
+		pdf.CellFormat(45, 7, voluntario.Validade.Format("02/01/2006"), "1", 0, "C", false, 0, "")
+		switch voluntario.Situacao {
+		case usecase.TreinamentoValido:
+			pdf.SetFillColor(198, 239, 206)
+		case usecase.TreinamentoAVencer:
+			pdf.SetFillColor(255, 235, 156)
+		default:
+			pdf.SetFillColor(255, 199, 206)
+		}
+		pdf.CellFormat(30, 7, tr(string(voluntario.Situacao)), "1", 1, "C", true, 0, "")
+		pdf.SetFillColor(255, 255, 255)
+	}
+}
+
+// addCobertura desenha a concentração do trabalho em cada livro e a escala
+// de voluntários da localidade
+func (s *GofpdfService) addCobertura(pdf *gofpdf.Fpdf, tr func(string) string, cobertura *usecase.CoberturaLocalidade) {
+	pdf.SetFont("Arial", "B", 14)
+	pdf.CellFormat(0, 10, tr("Cobertura de Voluntários"), "", 1, "", false, 0, "")
+	pdf.SetFont("Arial", "", 10)
+	pdf.MultiCell(0, 6, tr(fmt.Sprintf("%d voluntários distintos, %d com apontamentos também em outras localidades. "+
+		"Em destaque, os livros em que um único voluntário responde por 60%% ou mais das horas.",
+		cobertura.Voluntarios, cobertura.MultiLocalidade)), "", "", false)
+	pdf.Ln(3)
+
+	pdf.SetFont("Arial", "B", 10)
+	pdf.CellFormat(62, 7, "Livro", "1", 0, "C", false, 0, "")
+	pdf.CellFormat(24, 7, tr("Voluntários"), "1", 0, "C", false, 0, "")
+	pdf.CellFormat(80, 7, tr("Principais voluntários"), "1", 0, "C", false, 0, "")
+	pdf.CellFormat(24, 7, tr("Principal"), "1", 1, "C", false, 0, "")
+	for _, livro := range cobertura.Livros {
+		principais := make([]string, 0, len(livro.Principais))
+		for _, principal := range livro.Principais {
+			principais = append(principais, fmt.Sprintf("%s %.0f%%", principal.Nome, principal.Participacao))
+		}
+
+		pdf.SetFont("Arial", "", 10)
+		pdf.CellFormat(62, 7, tr(livro.Livro), "1", 0, "", false, 0, "")
+		pdf.CellFormat(24, 7, fmt.Sprintf("%d", livro.Voluntarios), "1", 0, "C", false, 0, "")
+		pdf.SetFont("Arial", "", 8)
+		pdf.CellFormat(80, 7, ajustarTexto(pdf, tr, strings.Join(principais, " · "), 78), "1", 0, "", false, 0, "")
+		pdf.SetFont("Arial", "", 10)
+		if livro.Concentrado {
+			pdf.SetFillColor(255, 199, 206)
+		}
+		pdf.CellFormat(24, 7, fmt.Sprintf("%.0f%%", livro.Principais[0].Participacao), "1", 1, "C", livro.Concentrado, 0, "")
+		pdf.SetFillColor(255, 255, 255)
+	}
+
+	pdf.Ln(6)
+	pdf.SetFont("Arial", "B", 12)
+	pdf.CellFormat(0, 8, tr("Escala de Voluntários"), "", 1, "", false, 0, "")
+	pdf.SetFont("Arial", "B", 9)
+	pdf.CellFormat(40, 7, tr("Voluntário"), "1", 0, "C", false, 0, "")
+	pdf.CellFormat(70, 7, "Livros", "1", 0, "C", false, 0, "")
+	pdf.CellFormat(18, 7, tr("Lanç."), "1", 0, "C", false, 0, "")
+	pdf.CellFormat(18, 7, "Horas", "1", 0, "C", false, 0, "")
+	pdf.CellFormat(44, 7, "Outras localidades", "1", 1, "C", false, 0, "")
+	pdf.SetFont("Arial", "", 8)
+	for _, voluntario := range cobertura.Escala {
+		pdf.CellFormat(40, 6, ajustarTexto(pdf, tr, voluntario.Nome, 38), "1", 0, "", false, 0, "")
+		pdf.CellFormat(70, 6, ajustarTexto(pdf, tr, strings.Join(voluntario.Livros, ", "), 68), "1", 0, "", false, 0, "")
+		pdf.CellFormat(18, 6, fmt.Sprintf("%d", voluntario.Lancamentos), "1", 0, "C", false, 0, "")
+		pdf.CellFormat(18, 6, formatarHoras(voluntario.Horas), "1", 0, "C", false, 0, "")
+		pdf.CellFormat(44, 6, ajustarTexto(pdf, tr, strings.Join(voluntario.OutrasLocalidades, ", "), 42), "1", 1, "", false, 0, "")
+	}
+}
+
+// addRanking desenha a classificação das localidades pelo cumprimento geral
+// das metas. Com links, o nome de cada localidade aponta para o seu relatório.
+func (s *GofpdfService) addRanking(pdf *gofpdf.Fpdf, tr func(string) string, ranking []*usecase.CumprimentoLocalidade, links map[string]int) {
+	pdf.SetFont("Arial", "B", 14)
+	pdf.CellFormat(0, 10, tr("Ranking por Cumprimento das Metas"), "", 1, "", false, 0, "")
+
+	pdf.SetFont("Arial", "B", 10)
+	pdf.CellFormat(20, 7, tr("Posição"), "1", 0, "C", false, 0, "")
+	pdf.CellFormat(120, 7, "Localidade", "1", 0, "C", false, 0, "")
+	pdf.CellFormat(30, 7, "Cumprimento", "1", 1, "C", false, 0, "")
+
+	pdf.SetFont("Arial", "", 10)
+	for i, cumprimento := range ranking {
+		pdf.CellFormat(20, 7, fmt.Sprintf("%d", i+1), "1", 0, "C", false, 0, "")
+		if link, exists := links[cumprimento.Localidade]; exists {
+			pdf.SetTextColor(0, 0, 160)
+			pdf.CellFormat(120, 7, tr(cumprimento.Localidade), "1", 0, "", false, link, "")
+			pdf.SetTextColor(0, 0, 0)
+		} else {
+			pdf.CellFormat(120, 7, tr(cumprimento.Localidade), "1", 0, "", false, 0, "")
+		}
+		s.celulaCumprimento(pdf, 30, 7, cumprimento.Percentual, 1)
+	}
+}
+
+// celulaCumprimento desenha o percentual de cumprimento com fundo verde
+// (meta atingida), amarelo (a partir de 70%) ou vermelho
+func (s *GofpdfService) celulaCumprimento(pdf *gofpdf.Fpdf, largura, altura, percentual float64, ln int) {
+	s.celulaPercentual(pdf, largura, altura, percentual, fmt.Sprintf("%.0f%%", math.Floor(percentual)), ln)
+}
+
+// celulaPercentual desenha o texto com o fundo do percentual de cumprimento
+func (s *GofpdfService) celulaPercentual(pdf *gofpdf.Fpdf, largura, altura, percentual float64, texto string, ln int) {
+	switch {
+	case percentual >= 100:
+		pdf.SetFillColor(198, 239, 206)
+	case percentual >= 70:
+		pdf.SetFillColor(255, 235, 156)
+	default:
+		pdf.SetFillColor(255, 199, 206)
+	}
+	pdf.CellFormat(largura, altura, texto, "1", ln, "C", true, 0, "")
+	pdf.SetFillColor(255, 255, 255)
+}
+
+func (s *GofpdfService) addAlerts(pdf *gofpdf.Fpdf, tr func(string) string, alertas []domain.Alerta) {
+	if len(alertas) == 0 {
+		return
+	}
+
+	pdf.SetFont("Arial", "B", 12)
+	pdf.SetTextColor(255, 0, 0)
+	pdf.MultiCell(0, 8, tr("PONTOS DE ATENÇÃO:"), "", "", false)
+
+	for _, alerta := range alertas {
+		pdf.SetTextColor(237, 81, 14)
+		pdf.MultiCell(0, 8, tr("> "+alerta.Mensagem), "", "", false)
 	}
 }
 
-func (s *GofpdfService) addObservacoes(pdf *gofpdf.Fpdf, tr func(string) string) {
+// addObservacoes desenha o quadro de observações com as anotações do
+// período, completando com linhas em branco para anotações à mão
+func (s *GofpdfService) addObservacoes(pdf *gofpdf.Fpdf, tr func(string) string, observacoes []string) {
+	const linhas = 10
+
 	pdf.Ln(20)
 	pdf.SetFont("Arial", "B", 12)
 	pdf.CellFormat(190, 7, tr("OBSERVAÇÕES"), "1", 1, "C", false, 0, "")
-	for i := 0; i < 10; i++ {
+	pdf.SetFont("Arial", "", 10)
+	for _, observacao := range observacoes {
+		pdf.MultiCell(190, 7, tr(observacao), "1", "L", false)
+	}
+	for i := len(observacoes); i < linhas; i++ {
 		pdf.CellFormat(190, 7, "", "1", 1, "C", false, 0, "")
 	}
 }
+
+// celulaApontamento escreve a data do último lançamento, os dias desde então
+// e os voluntários do livro, com a seta da tendência ao lado
+func (s *GofpdfService) celulaApontamento(pdf *gofpdf.Fpdf, tr func(string) string, largura, altura float64, apontamento *usecase.ApontamentoLivro, ln int) {
+	const larguraSeta = 5.0
+
+	texto := "-"
+	if apontamento != nil {
+		switch {
+		case !apontamento.UltimoLancamento.IsZero():
+			texto = fmt.Sprintf("%s (%dd) · %d vol.",
+				apontamento.UltimoLancamento.Format("02/01"), apontamento.DiasSemLancamento, apontamento.Voluntarios)
+		case apontamento.Voluntarios > 0:
+			texto = fmt.Sprintf("%d vol.", apontamento.Voluntarios)
+		}
+	}
+
+	x, y := pdf.GetXY()
+	pdf.SetFont("Arial", "", 8)
+	pdf.CellFormat(largura-larguraSeta, altura, tr(texto), "LTB", 0, "C", false, 0, "")
+	pdf.CellFormat(larguraSeta, altura, "", "RTB", ln, "C", false, 0, "")
+	pdf.SetFont("Arial", "", 10)
+	if apontamento != nil {
+		s.desenharTendencia(pdf, x+largura-larguraSeta/2, y+altura/2, apontamento.Tendencia)
+	}
+}
+
+// desenharTendencia desenha um triângulo verde para cima (alta), vermelho
+// para baixo (queda) ou um traço cinza (estável), centrado em (x, y)
+func (s *GofpdfService) desenharTendencia(pdf *gofpdf.Fpdf, x, y float64, tendencia usecase.Tendencia) {
+	switch tendencia {
+	case usecase.TendenciaAlta:
+		pdf.SetFillColor(0, 150, 0)
+		pdf.Polygon([]gofpdf.PointType{{X: x - 1.5, Y: y + 1.2}, {X: x + 1.5, Y: y + 1.2}, {X: x, Y: y - 1.3}}, "F")
+	case usecase.TendenciaBaixa:
+		pdf.SetFillColor(220, 0, 0)
+		pdf.Polygon([]gofpdf.PointType{{X: x - 1.5, Y: y - 1.2}, {X: x + 1.5, Y: y - 1.2}, {X: x, Y: y + 1.3}}, "F")
+	case usecase.TendenciaEstavel:
+		pdf.SetFillColor(128, 128, 128)
+		pdf.Rect(x-1.5, y-0.4, 3, 0.8, "F")
+	}
+	pdf.SetFillColor(255, 255, 255)
+}
+
+// output grava o documento e registra o tempo de renderização
+func (s *GofpdfService) output(pdf *gofpdf.Fpdf, outputPath string, inicio time.Time) error {
+	paginas := pdf.PageNo()
+	if err := pdf.OutputFileAndClose(outputPath); err != nil {
+		return err
+	}
+	s.logger.Debug("documento gerado", "arquivo", outputPath, "paginas", paginas, "duracao", time.Since(inicio))
+	return nil
+}
+
+// ajustarTexto traduz o texto e o corta com reticências para caber na
+// largura, na fonte atual
+func ajustarTexto(pdf *gofpdf.Fpdf, tr func(string) string, texto string, largura float64) string {
+	if pdf.GetStringWidth(tr(texto)) <= largura {
+		return tr(texto)
+	}
+	runas := []rune(texto)
+	for len(runas) > 0 && pdf.GetStringWidth(tr(string(runas)+"...")) > largura {
+		runas = runas[:len(runas)-1]
+	}
+	return tr(strings.TrimSpace(string(runas)) + "...")
+}
+
+// formatarHoras exibe as horas no formato HH:MM
+func formatarHoras(horas float64) string {
+	minutos := int(math.Round(horas * 60))
+	return fmt.Sprintf("%d:%02d", minutos/60, minutos%60)
+}
+
+// formatarMeta exibe o valor da meta com a unidade da sua métrica
+func formatarMeta(meta domain.Meta) string {
+	switch meta.Metrica {
+	case domain.MetricaHoras:
+		return formatarHoras(meta.Valor)
+	case domain.MetricaVoluntarios:
+		return fmt.Sprintf("%g vol.", meta.Valor)
+	default:
+		return fmt.Sprintf("%g", meta.Valor)
+	}
+}
+
+func ensureDir(outputPath string) error {
+	dir := strings.TrimSuffix(outputPath, filepath.Base(outputPath))
+	if err := os.MkdirAll(dir, os.ModePerm); err != nil {
+		return fmt.Errorf("erro ao criar diretório: %v", err)
+	}
+	return nil
+}
@@ -0,0 +1,249 @@
+package infrastructure
+
+import (
+	"archive/zip"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/shakinm/xlsReader/xls"
+)
+
+// Formatos de planilha aceitos para a listagem de horas
+const (
+	FormatoCSV  = ".csv"
+	FormatoXLS  = ".xls"
+	FormatoXLSX = ".xlsx"
+)
+
+// FormatoSuportado indica se a extensão do arquivo corresponde a um formato de planilha aceito
+func FormatoSuportado(path string) bool {
+	switch strings.ToLower(filepath.Ext(path)) {
+	case FormatoCSV, FormatoXLS, FormatoXLSX:
+		return true
+	}
+	return false
+}
+
+// percorrerRegistros chama registro para cada linha da primeira planilha do
+// arquivo, na ordem, escolhendo o formato pela extensão. As linhas são
+// entregues uma a uma, sem manter o arquivo inteiro em memória; o slice
+// recebido é reaproveitado entre as chamadas e não deve ser guardado. A
+// codificação só se aplica a arquivos CSV, cujo formato lido é retornado.
+func percorrerRegistros(path, codificacao string, registro func(record []string) error) (leituraCSV, error) {
+	switch strings.ToLower(filepath.Ext(path)) {
+	case FormatoXLS:
+		return leituraCSV{}, percorrerXLS(path, registro)
+	case FormatoXLSX:
+		return leituraCSV{}, percorrerXLSX(path, registro)
+	default:
+		return percorrerCSV(path, codificacao, registro)
+	}
+}
+
+func percorrerCSV(path, codificacao string, registro func(record []string) error) (leituraCSV, error) {
+	arquivo, err := abrirCSV(path, codificacao)
+	if err != nil {
+		return leituraCSV{}, err
+	}
+	defer arquivo.Close()
+
+	arquivo.ReuseRecord = true
+	for {
+		record, err := arquivo.Read()
+		if err == io.EOF {
+			return arquivo.formato, nil
+		}
+		if err != nil {
+			return arquivo.formato, err
+		}
+		if err := registro(record); err != nil {
+			return arquivo.formato, err
+		}
+	}
+}
+
+// percorrerXLS entrega as linhas da planilha XLS; o formato binário é
+// carregado pela biblioteca, mas as linhas não são copiadas para uma matriz
+func percorrerXLS(path string, registro func(record []string) error) error {
+	workbook, err := xls.OpenFile(path)
+	if err != nil {
+		return fmt.Errorf("erro ao abrir planilha XLS: %v", err)
+	}
+	sheet, err := workbook.GetSheet(0)
+	if err != nil {
+		return fmt.Errorf("erro ao ler planilha XLS: %v", err)
+	}
+
+	// GetNumberRows percorre todas as linhas da aba a cada chamada
+	linhas := sheet.GetNumberRows()
+	var record []string
+	largura := 0
+	for i := 0; i < linhas; i++ {
+		record = record[:0]
+		if row, err := sheet.GetRow(i); err == nil {
+			for _, cell := range row.GetCols() {
+				record = append(record, strings.TrimSpace(cell.GetString()))
+			}
+		}
+		record = completarLinha(record, &largura)
+		if err := registro(record); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// xlsxRow e xlsxSharedStrings mapeiam o mínimo do formato SpreadsheetML
+// necessário para ler os valores das células
+type xlsxRow struct {
+	Cells []struct {
+		Ref    string `xml:"r,attr"`
+		Type   string `xml:"t,attr"`
+		Value  string `xml:"v"`
+		Inline string `xml:"is>t"`
+	} `xml:"c"`
+}
+
+type xlsxSharedStrings struct {
+	Items []struct {
+		Text string `xml:"t"`
+		Runs []struct {
+			Text string `xml:"t"`
+		} `xml:"r"`
+	} `xml:"si"`
+}
+
+// percorrerXLSX lê a primeira aba elemento a elemento, decodificando uma
+// linha por vez
+func percorrerXLSX(path string, registro func(record []string) error) error {
+	archive, err := zip.OpenReader(path)
+	if err != nil {
+		return fmt.Errorf("erro ao abrir planilha XLSX: %v", err)
+	}
+	defer archive.Close()
+
+	var sharedStrings []string
+	var sheet *zip.File
+	for _, file := range archive.File {
+		switch file.Name {
+		case "xl/sharedStrings.xml":
+			var sst xlsxSharedStrings
+			if err := decodeZipXML(file, &sst); err != nil {
+				return err
+			}
+			for _, item := range sst.Items {
+				text := item.Text
+				for _, run := range item.Runs {
+					text += run.Text
+				}
+				sharedStrings = append(sharedStrings, text)
+			}
+		case "xl/worksheets/sheet1.xml":
+			sheet = file
+		}
+	}
+	if sheet == nil {
+		return fmt.Errorf("planilha XLSX sem a primeira aba")
+	}
+
+	rc, err := sheet.Open()
+	if err != nil {
+		return err
+	}
+	defer rc.Close()
+
+	decoder := xml.NewDecoder(rc)
+	var record []string
+	largura := 0
+	for {
+		token, err := decoder.Token()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return fmt.Errorf("erro ao ler %s: %v", sheet.Name, err)
+		}
+		inicio, ok := token.(xml.StartElement)
+		if !ok || inicio.Name.Local != "row" {
+			continue
+		}
+
+		var row xlsxRow
+		if err := decoder.DecodeElement(&row, &inicio); err != nil {
+			return fmt.Errorf("erro ao ler %s: %v", sheet.Name, err)
+		}
+
+		record = record[:0]
+		for _, cell := range row.Cells {
+			col := columnIndex(cell.Ref)
+			if col < 0 {
+				col = len(record)
+			}
+			for len(record) <= col {
+				record = append(record, "")
+			}
+
+			value := cell.Value
+			switch cell.Type {
+			case "s":
+				if i, err := strconv.Atoi(value); err == nil && i < len(sharedStrings) {
+					value = sharedStrings[i]
+				}
+			case "inlineStr":
+				value = cell.Inline
+			}
+			record[col] = strings.TrimSpace(value)
+		}
+		record = completarLinha(record, &largura)
+		if err := registro(record); err != nil {
+			return err
+		}
+	}
+}
+
+// completarLinha completa a linha da planilha com células vazias até a
+// largura da linha mais larga já lida. As planilhas omitem as células vazias
+// do fim da linha; completadas, as linhas de dados têm ao menos a largura do
+// cabeçalho, e uma linha sem livro é contada como "livro vazio", não como
+// linha incompleta.
+func completarLinha(record []string, largura *int) []string {
+	for len(record) < *largura {
+		record = append(record, "")
+	}
+	*largura = len(record)
+	return record
+}
+
+func decodeZipXML(file *zip.File, v interface{}) error {
+	rc, err := file.Open()
+	if err != nil {
+		return err
+	}
+	defer rc.Close()
+
+	if err := xml.NewDecoder(io.Reader(rc)).Decode(v); err != nil {
+		return fmt.Errorf("erro ao ler %s: %v", file.Name, err)
+	}
+	return nil
+}
+
+// columnIndex converte a referência de uma célula (ex.: "C12") no índice da coluna
+func columnIndex(ref string) int {
+	col := 0
+	n := 0
+	for _, r := range ref {
+		if r < 'A' || r > 'Z' {
+			break
+		}
+		col = col*26 + int(r-'A'+1)
+		n++
+	}
+	if n == 0 {
+		return -1
+	}
+	return col - 1
+}
@@ -0,0 +1,186 @@
+package infrastructure
+
+import (
+	"archive/zip"
+	"bufio"
+	"encoding/csv"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// cabecalhoListagem é a linha de títulos da listagem exportada pelo sistema
+var cabecalhoListagem = []string{"Localidade", "", "Livro", "", "Nome", "CPF", "Nascimento", "Codigo", "Data", "Entrada", "Saida", "Horas", "Intervalo"}
+
+// gerarListagemCSV grava uma listagem com as linhas de cabeçalho e o número
+// de lançamentos informado, distribuídos de 2021 a 2025 entre 40 localidades
+func gerarListagemCSV(tb testing.TB, lancamentos int) string {
+	tb.Helper()
+	path := filepath.Join(tb.TempDir(), "listagem.csv")
+	file, err := os.Create(path)
+	if err != nil {
+		tb.Fatal(err)
+	}
+	defer file.Close()
+
+	buffer := bufio.NewWriter(file)
+	writer := csv.NewWriter(buffer)
+	for i := 1; i < linhasCabecalho; i++ {
+		writer.Write([]string{fmt.Sprintf("Relatório de horas - linha %d", i)})
+	}
+	writer.Write(cabecalhoListagem)
+	livros := []string{"Manutenção", "Limpeza", "Jardinagem", "Portaria", "Brigada"}
+	for i := 0; i < lancamentos; i++ {
+		entrada := 7 + i%10
+		writer.Write([]string{
+			fmt.Sprintf("BR 09-%04d - LOCALIDADE %d (SANTO AMARO)", i%40, i%40), "",
+			livros[i%len(livros)], "",
+			fmt.Sprintf("VOLUNTARIO %d", i%900), "000.000.000-00", "01/01/70", fmt.Sprint(i),
+			fmt.Sprintf("%02d/%02d/%02d", 1+i%28, 1+(i/28)%12, 21+(i/336)%5),
+			fmt.Sprintf("%02d:00", entrada), fmt.Sprintf("%02d:30", entrada+2), "02:30", "",
+		})
+	}
+	writer.Flush()
+	if err := writer.Error(); err != nil {
+		tb.Fatal(err)
+	}
+	if err := buffer.Flush(); err != nil {
+		tb.Fatal(err)
+	}
+	return path
+}
+
+// contarLivros agrega as linhas de dados por livro, o mínimo comum aos dois
+// modos de leitura comparados nos benchmarks
+func contarLivros(contagem map[string]int) func(record []string) error {
+	linha := 0
+	return func(record []string) error {
+		linha++
+		if linha > linhasCabecalho && len(record) > colunasPadrao.livro {
+			contagem[record[colunasPadrao.livro]]++
+		}
+		return nil
+	}
+}
+
+// BenchmarkPercorrerRegistrosCSV lê a listagem linha a linha, como o
+// repositório faz hoje
+func BenchmarkPercorrerRegistrosCSV(b *testing.B) {
+	path := gerarListagemCSV(b, 200000)
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		contagem := make(map[string]int)
+		if _, err := percorrerRegistros(path, CodificacaoAuto, contarLivros(contagem)); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+// BenchmarkReadAllCSV lê a listagem inteira para a memória antes de
+// percorrê-la, como o repositório fazia antes da leitura em fluxo
+func BenchmarkReadAllCSV(b *testing.B) {
+	path := gerarListagemCSV(b, 200000)
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		arquivo, err := abrirCSV(path, CodificacaoAuto)
+		if err != nil {
+			b.Fatal(err)
+		}
+		records, err := arquivo.ReadAll()
+		arquivo.Close()
+		if err != nil {
+			b.Fatal(err)
+		}
+		contagem := make(map[string]int)
+		registro := contarLivros(contagem)
+		for _, record := range records {
+			registro(record)
+		}
+	}
+}
+
+// BenchmarkLerListagemCSV mede a leitura completa do repositório, com a
+// agregação por localidade e livro
+func BenchmarkLerListagemCSV(b *testing.B) {
+	path := gerarListagemCSV(b, 200000)
+	repo, _, _ := NewCSVRepositories(path, "", slog.New(slog.NewTextHandler(io.Discard, nil)))
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if _, err := repo.ler(); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+// gravarXLSX grava uma planilha mínima com as linhas informadas em células
+// de texto, omitindo as células vazias como fazem as planilhas exportadas
+func gravarXLSX(t *testing.T, linhas [][]string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "listagem.xlsx")
+	file, err := os.Create(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer file.Close()
+
+	var sheet strings.Builder
+	sheet.WriteString(`<?xml version="1.0" encoding="UTF-8"?><worksheet xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main"><sheetData>`)
+	for i, linha := range linhas {
+		fmt.Fprintf(&sheet, `<row r="%d">`, i+1)
+		for j, valor := range linha {
+			if valor == "" {
+				continue
+			}
+			fmt.Fprintf(&sheet, `<c r="%s%d" t="inlineStr"><is><t>%s</t></is></c>`, columnName(j), i+1, escapeXML(valor))
+		}
+		sheet.WriteString(`</row>`)
+	}
+	sheet.WriteString(`</sheetData></worksheet>`)
+
+	archive := zip.NewWriter(file)
+	writer, err := archive.Create("xl/worksheets/sheet1.xml")
+	if err != nil {
+		t.Fatal(err)
+	}
+	io.WriteString(writer, sheet.String())
+	if err := archive.Close(); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestLerListagemXLSXLinhaSemLivro(t *testing.T) {
+	linhas := make([][]string, 0, linhasCabecalho+2)
+	for i := 1; i < linhasCabecalho; i++ {
+		linhas = append(linhas, []string{fmt.Sprintf("Relatório de horas - linha %d", i)})
+	}
+	linhas = append(linhas,
+		cabecalhoListagem,
+		[]string{"BR 09-0001 - VILA NOVA (SANTO AMARO)", "", "Manutenção", "", "JOSE DA SILVA", "", "", "1", "03/02/25", "08:00", "10:00", "02:00"},
+		// Sem livro, a planilha grava só a primeira célula da linha
+		[]string{"BR 09-0001 - VILA NOVA (SANTO AMARO)"},
+	)
+	path := gravarXLSX(t, linhas)
+
+	repo, _, _ := NewCSVRepositories(path, "", slog.New(slog.NewTextHandler(io.Discard, nil)))
+	if _, err := repo.GetAll(); err != nil {
+		t.Fatalf("GetAll: %v", err)
+	}
+	estatisticas := repo.Estatisticas()
+	if estatisticas.LinhasLidas != 2 {
+		t.Errorf("LinhasLidas = %d, esperado 2", estatisticas.LinhasLidas)
+	}
+	if n := estatisticas.Ignoradas[motivoSemLivro]; n != 1 {
+		t.Errorf("Ignoradas[%q] = %d, esperado 1", motivoSemLivro, n)
+	}
+	if n := estatisticas.Ignoradas[motivoIncompleta]; n != 0 {
+		t.Errorf("Ignoradas[%q] = %d, esperado 0", motivoIncompleta, n)
+	}
+}
@@ -0,0 +1,160 @@
+package infrastructure
+
+import (
+	"archive/zip"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+
+	"report/internal/usecase"
+)
+
+// XLSXPlanilhaService implementa PlanilhaService gravando o formato
+// SpreadsheetML mínimo: textos inline e números, sem estilos
+type XLSXPlanilhaService struct{}
+
+// NewXLSXPlanilhaService cria uma nova instância de XLSXPlanilhaService
+func NewXLSXPlanilhaService() *XLSXPlanilhaService {
+	return &XLSXPlanilhaService{}
+}
+
+const xlsxContentTypes = `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<Types xmlns="http://schemas.openxmlformats.org/package/2006/content-types">
+<Default Extension="rels" ContentType="application/vnd.openxmlformats-package.relationships+xml"/>
+<Default Extension="xml" ContentType="application/xml"/>
+<Override PartName="/xl/workbook.xml" ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.sheet.main+xml"/>
+%s</Types>`
+
+const xlsxRootRels = `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships">
+<Relationship Id="rId1" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/officeDocument" Target="xl/workbook.xml"/>
+</Relationships>`
+
+// GenerateXLSX grava as abas informadas em uma planilha XLSX
+func (s *XLSXPlanilhaService) GenerateXLSX(abas []usecase.Aba, outputPath string) error {
+	if err := ensureDir(outputPath); err != nil {
+		return err
+	}
+
+	file, err := os.Create(outputPath)
+	if err != nil {
+		return fmt.Errorf("erro ao criar planilha: %v", err)
+	}
+	defer file.Close()
+
+	archive := zip.NewWriter(file)
+
+	var overrides, sheets, rels strings.Builder
+	for i, aba := range abas {
+		n := i + 1
+		fmt.Fprintf(&overrides, `<Override PartName="/xl/worksheets/sheet%d.xml" ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.worksheet+xml"/>`+"\n", n)
+		fmt.Fprintf(&sheets, `<sheet name="%s" sheetId="%d" r:id="rId%d"/>`, escapeXML(nomeAba(aba.Nome, n)), n, n)
+		fmt.Fprintf(&rels, `<Relationship Id="rId%d" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/worksheet" Target="worksheets/sheet%d.xml"/>`+"\n", n, n)
+	}
+
+	partes := []struct {
+		nome     string
+		conteudo string
+	}{
+		{"[Content_Types].xml", fmt.Sprintf(xlsxContentTypes, overrides.String())},
+		{"_rels/.rels", xlsxRootRels},
+		{"xl/workbook.xml", `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<workbook xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main" xmlns:r="http://schemas.openxmlformats.org/officeDocument/2006/relationships"><sheets>` + sheets.String() + `</sheets></workbook>`},
+		{"xl/_rels/workbook.xml.rels", `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships">
+` + rels.String() + `</Relationships>`},
+	}
+	for _, parte := range partes {
+		if err := writeZipEntry(archive, parte.nome, parte.conteudo); err != nil {
+			return err
+		}
+	}
+
+	for i, aba := range abas {
+		entry, err := archive.Create(fmt.Sprintf("xl/worksheets/sheet%d.xml", i+1))
+		if err != nil {
+			return err
+		}
+		if err := writeSheet(entry, aba); err != nil {
+			return fmt.Errorf("erro ao gravar aba %s: %v", aba.Nome, err)
+		}
+	}
+
+	if err := archive.Close(); err != nil {
+		return fmt.Errorf("erro ao finalizar planilha: %v", err)
+	}
+	return file.Close()
+}
+
+func writeSheet(w io.Writer, aba usecase.Aba) error {
+	var b strings.Builder
+	b.WriteString(`<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<worksheet xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main"><sheetData>`)
+
+	linhas := make([][]interface{}, 0, len(aba.Linhas)+1)
+	cabecalho := make([]interface{}, len(aba.Cabecalho))
+	for i, coluna := range aba.Cabecalho {
+		cabecalho[i] = coluna
+	}
+	linhas = append(linhas, cabecalho)
+	linhas = append(linhas, aba.Linhas...)
+
+	for i, linha := range linhas {
+		fmt.Fprintf(&b, `<row r="%d">`, i+1)
+		for j, valor := range linha {
+			ref := columnName(j) + strconv.Itoa(i+1)
+			switch v := valor.(type) {
+			case int:
+				fmt.Fprintf(&b, `<c r="%s"><v>%d</v></c>`, ref, v)
+			case float64:
+				fmt.Fprintf(&b, `<c r="%s"><v>%s</v></c>`, ref, strconv.FormatFloat(v, 'f', -1, 64))
+			default:
+				fmt.Fprintf(&b, `<c r="%s" t="inlineStr"><is><t>%s</t></is></c>`, ref, escapeXML(fmt.Sprint(v)))
+			}
+		}
+		b.WriteString(`</row>`)
+	}
+	b.WriteString(`</sheetData></worksheet>`)
+
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+func writeZipEntry(archive *zip.Writer, nome, conteudo string) error {
+	entry, err := archive.Create(nome)
+	if err != nil {
+		return err
+	}
+	_, err = io.WriteString(entry, conteudo)
+	return err
+}
+
+// columnName converte o índice da coluna na letra usada nas referências (0 -> A)
+func columnName(col int) string {
+	nome := ""
+	for col >= 0 {
+		nome = string(rune('A'+col%26)) + nome
+		col = col/26 - 1
+	}
+	return nome
+}
+
+// nomeAba respeita o limite de 31 caracteres dos nomes de aba
+func nomeAba(nome string, n int) string {
+	if nome == "" {
+		return fmt.Sprintf("Planilha%d", n)
+	}
+	if runes := []rune(nome); len(runes) > 31 {
+		return string(runes[:31])
+	}
+	return nome
+}
+
+func escapeXML(s string) string {
+	var b strings.Builder
+	xml.EscapeText(&b, []byte(s))
+	return b.String()
+}
@@ -0,0 +1,78 @@
+package web
+
+import (
+	_ "embed"
+	"errors"
+	"net/http"
+	"strings"
+
+	"report/internal/usecase"
+)
+
+//go:embed openapi.json
+var openAPI []byte
+
+// handleAPI atende a API JSON de consulta aos dados dos relatórios
+func (s *Server) handleAPI(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		methodNotAllowed(w, http.MethodGet)
+		return
+	}
+
+	recurso := strings.Trim(strings.TrimPrefix(r.URL.Path, "/api"), "/")
+	if recurso == "openapi.json" {
+		w.Header().Set("Content-Type", "application/json; charset=utf-8")
+		w.Write(openAPI)
+		return
+	}
+
+	filtro := usecase.Filtro{
+		Periodo:    strings.TrimSpace(r.URL.Query().Get("periodo")),
+		Setor:      strings.TrimSpace(r.URL.Query().Get("setor")),
+		Localidade: strings.TrimSpace(r.URL.Query().Get("localidade")),
+		Livro:      strings.TrimSpace(r.URL.Query().Get("livro")),
+	}
+
+	var (
+		resposta interface{}
+		err      error
+	)
+	switch recurso {
+	case "periodos":
+		resposta, err = s.cfg.Consulta.Periodos()
+	case "setores":
+		resposta, err = s.cfg.Consulta.Setores()
+	case "localidades":
+		resposta, err = s.cfg.Consulta.Localidades(filtro)
+	case "livros":
+		resposta, err = s.cfg.Consulta.Livros(filtro)
+	case "resumos":
+		resposta, err = s.cfg.Consulta.Resumos(filtro)
+	case "alertas":
+		resposta, err = s.cfg.Consulta.Alertas(filtro)
+	case "voluntarios":
+		resposta, err = s.cfg.Consulta.Voluntarios(filtro)
+	default:
+		writeJSONError(w, http.StatusNotFound, "recurso não encontrado")
+		return
+	}
+
+	if errors.Is(err, usecase.ErrPeriodoInvalido) {
+		writeJSONError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	if errors.Is(err, usecase.ErrPeriodoNaoEncontrado) {
+		writeJSONError(w, http.StatusNotFound, err.Error())
+		return
+	}
+	if err != nil {
+		s.logger.Error("erro na consulta", "recurso", recurso, "erro", err)
+		writeJSONError(w, http.StatusInternalServerError, "erro ao consultar os dados")
+		return
+	}
+	writeJSON(w, http.StatusOK, resposta)
+}
+
+func writeJSONError(w http.ResponseWriter, status int, mensagem string) {
+	writeJSON(w, status, map[string]string{"erro": mensagem})
+}
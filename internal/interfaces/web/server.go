@@ -0,0 +1,552 @@
+// Package web implementa o modo servidor: envio da listagem de horas por
+// formulário, geração dos relatórios em segundo plano, download dos PDFs e
+// a API JSON de consulta aos dados.
+package web
+
+import (
+	"archive/zip"
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"os"
+	"path/filepath"
+	"slices"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"report/internal/domain"
+	"report/internal/usecase"
+)
+
+// Situações de uma execução
+const (
+	StatusPendente   = "pendente"
+	StatusExecutando = "executando"
+	StatusConcluido  = "concluido"
+	StatusFalhou     = "falhou"
+)
+
+const jobFileName = "job.json"
+
+// RunRequest contém os caminhos de uma execução disparada pelo servidor
+type RunRequest struct {
+	InputPath string
+	BooksPath string
+	OutputDir string
+	Periodo   string
+	// EstadoDir guarda o histórico, o registro da brigada e os apelidos
+	// próprios da execução, para que um envio não altere os do operador
+	EstadoDir string
+}
+
+// Runner executa a geração dos relatórios de uma execução
+type Runner func(ctx context.Context, req RunRequest) (*usecase.Resultado, error)
+
+// Config define os parâmetros do servidor
+type Config struct {
+	// DataDir é o diretório onde cada execução guarda as entradas e os relatórios
+	DataDir string
+	// BooksPath é o catálogo de livros usado quando nenhum é enviado
+	BooksPath string
+	// MaxRuns limita o número de execuções guardadas; zero mantém todas
+	MaxRuns int
+	// MaxUploadBytes limita o tamanho total dos arquivos enviados
+	MaxUploadBytes int64
+	// Consulta atende a API JSON em /api; nulo desativa a API
+	Consulta *usecase.Consulta
+}
+
+// Job representa uma execução e seu estado
+type Job struct {
+	ID          string     `json:"id"`
+	Status      string     `json:"status"`
+	Periodo     string     `json:"periodo"`
+	Listagem    string     `json:"listagem"`
+	Catalogo    string     `json:"catalogo,omitempty"`
+	CriadoEm    time.Time  `json:"criado_em"`
+	ConcluidoEm *time.Time `json:"concluido_em,omitempty"`
+	Documentos  []string   `json:"documentos,omitempty"`
+	Falhas      []string   `json:"falhas,omitempty"`
+	Erro        string     `json:"erro,omitempty"`
+}
+
+// Server atende a interface web e executa as gerações em segundo plano
+type Server struct {
+	cfg    Config
+	runner Runner
+	logger *slog.Logger
+
+	mu   sync.RWMutex
+	jobs map[string]*Job
+
+	// fila serializa as gerações para não competir pelos mesmos recursos
+	fila   chan struct{}
+	ctx    context.Context
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+}
+
+// NewServer cria o servidor e carrega as execuções guardadas em cfg.DataDir
+func NewServer(cfg Config, runner Runner, logger *slog.Logger) (*Server, error) {
+	if cfg.MaxUploadBytes <= 0 {
+		cfg.MaxUploadBytes = 64 << 20
+	}
+	if err := os.MkdirAll(cfg.DataDir, os.ModePerm); err != nil {
+		return nil, fmt.Errorf("erro ao criar diretório: %v", err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	s := &Server{
+		cfg:    cfg,
+		runner: runner,
+		logger: logger.With("servico", "web"),
+		jobs:   make(map[string]*Job),
+		fila:   make(chan struct{}, 1),
+		ctx:    ctx,
+		cancel: cancel,
+	}
+	if err := s.loadJobs(); err != nil {
+		cancel()
+		return nil, err
+	}
+	return s, nil
+}
+
+// Close cancela as execuções em andamento e aguarda seu término
+func (s *Server) Close() {
+	s.cancel()
+	s.wg.Wait()
+}
+
+// Handler retorna o roteador HTTP do servidor
+func (s *Server) Handler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/", s.handleIndex)
+	mux.HandleFunc("/runs", s.handleRuns)
+	mux.HandleFunc("/runs/", s.handleRun)
+	if s.cfg.Consulta != nil {
+		mux.HandleFunc("/api/", s.handleAPI)
+	}
+	return mux
+}
+
+func (s *Server) handleIndex(w http.ResponseWriter, r *http.Request) {
+	if r.URL.Path != "/" {
+		http.NotFound(w, r)
+		return
+	}
+	if r.Method != http.MethodGet {
+		methodNotAllowed(w, http.MethodGet)
+		return
+	}
+
+	render(w, indexTemplate, map[string]interface{}{
+		"Jobs":    s.listJobs(),
+		"Periodo": time.Now().Format(domain.LayoutPeriodo),
+	})
+}
+
+// handleRuns recebe o formulário de envio e inicia uma nova execução
+func (s *Server) handleRuns(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodGet:
+		writeJSON(w, http.StatusOK, s.listJobs())
+		return
+	case http.MethodPost:
+	default:
+		methodNotAllowed(w, http.MethodGet, http.MethodPost)
+		return
+	}
+
+	r.Body = http.MaxBytesReader(w, r.Body, s.cfg.MaxUploadBytes)
+	if err := r.ParseMultipartForm(s.cfg.MaxUploadBytes); err != nil {
+		http.Error(w, "envio inválido: "+err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	periodo := strings.TrimSpace(r.FormValue("periodo"))
+	if err := domain.ValidarPeriodo(periodo); err != nil {
+		http.Error(w, "período inválido, use AAAA-MM", http.StatusBadRequest)
+		return
+	}
+
+	job := &Job{
+		ID:       newJobID(),
+		Status:   StatusPendente,
+		Periodo:  periodo,
+		CriadoEm: time.Now(),
+	}
+	dir := s.jobDir(job.ID)
+	req := RunRequest{
+		BooksPath: s.cfg.BooksPath,
+		OutputDir: filepath.Join(dir, "output"),
+		Periodo:   periodo,
+		EstadoDir: filepath.Join(dir, "estado"),
+	}
+
+	inputPath, nome, err := saveUpload(r, "listagem", filepath.Join(dir, "input"), "listagem", ".csv", ".xls", ".xlsx")
+	if err != nil || inputPath == "" {
+		os.RemoveAll(dir)
+		http.Error(w, "listagem de horas inválida: "+errorText(err, "arquivo não enviado"), http.StatusBadRequest)
+		return
+	}
+	req.InputPath, job.Listagem = inputPath, nome
+
+	booksPath, nome, err := saveUpload(r, "catalogo", filepath.Join(dir, "input"), "catalogo", ".csv")
+	if err != nil {
+		os.RemoveAll(dir)
+		http.Error(w, "catálogo de livros inválido: "+err.Error(), http.StatusBadRequest)
+		return
+	}
+	if booksPath != "" {
+		req.BooksPath, job.Catalogo = booksPath, nome
+	}
+
+	if err := s.saveJob(job); err != nil {
+		os.RemoveAll(dir)
+		http.Error(w, "erro ao registrar execução", http.StatusInternalServerError)
+		s.logger.Error("erro ao registrar execução", "erro", err)
+		return
+	}
+
+	s.wg.Add(1)
+	go s.run(job.ID, req)
+
+	if strings.Contains(r.Header.Get("Accept"), "application/json") {
+		writeJSON(w, http.StatusAccepted, s.getJob(job.ID))
+		return
+	}
+	http.Redirect(w, r, "/runs/"+job.ID, http.StatusSeeOther)
+}
+
+// handleRun atende a página, o status, os downloads e o ZIP de uma execução
+func (s *Server) handleRun(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		methodNotAllowed(w, http.MethodGet)
+		return
+	}
+
+	resto := strings.TrimPrefix(r.URL.Path, "/runs/")
+	id, acao, _ := strings.Cut(resto, "/")
+	job := s.getJob(id)
+	if job == nil {
+		http.NotFound(w, r)
+		return
+	}
+
+	switch {
+	case acao == "":
+		render(w, runTemplate, job)
+	case acao == "status":
+		writeJSON(w, http.StatusOK, job)
+	case acao == "zip":
+		s.serveZip(w, job)
+	case strings.HasPrefix(acao, "files/"):
+		s.serveFile(w, r, job, strings.TrimPrefix(acao, "files/"))
+	default:
+		http.NotFound(w, r)
+	}
+}
+
+func (s *Server) serveFile(w http.ResponseWriter, r *http.Request, job *Job, documento string) {
+	if !contains(job.Documentos, documento) {
+		http.NotFound(w, r)
+		return
+	}
+
+	path := filepath.Join(s.jobDir(job.ID), "output", filepath.FromSlash(documento))
+	w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=%q", filepath.Base(path)))
+	http.ServeFile(w, r, path)
+}
+
+func (s *Server) serveZip(w http.ResponseWriter, job *Job) {
+	if job.Status != StatusConcluido {
+		http.Error(w, "execução ainda não concluída", http.StatusConflict)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/zip")
+	w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=%q", "relatorios_"+job.Periodo+".zip"))
+
+	archive := zip.NewWriter(w)
+	outputDir := filepath.Join(s.jobDir(job.ID), "output")
+	for _, documento := range job.Documentos {
+		if err := addToZip(archive, filepath.Join(outputDir, filepath.FromSlash(documento)), documento); err != nil {
+			s.logger.Error("erro ao compactar documento", "execucao", job.ID, "documento", documento, "erro", err)
+			return
+		}
+	}
+	if err := archive.Close(); err != nil {
+		s.logger.Error("erro ao finalizar ZIP", "execucao", job.ID, "erro", err)
+	}
+}
+
+// run executa a geração de uma execução, uma de cada vez
+func (s *Server) run(id string, req RunRequest) {
+	defer s.wg.Done()
+
+	select {
+	case s.fila <- struct{}{}:
+		defer func() { <-s.fila }()
+	case <-s.ctx.Done():
+		s.finish(id, nil, s.ctx.Err())
+		return
+	}
+
+	s.update(id, func(job *Job) { job.Status = StatusExecutando })
+	s.logger.Info("execução iniciada", "execucao", id, "periodo", req.Periodo)
+
+	resultado, err := s.runner(s.ctx, req)
+	s.finish(id, resultado, err)
+	s.prune()
+}
+
+func (s *Server) finish(id string, resultado *usecase.Resultado, err error) {
+	s.update(id, func(job *Job) {
+		agora := time.Now()
+		job.ConcluidoEm = &agora
+		if err != nil {
+			job.Status = StatusFalhou
+			job.Erro = err.Error()
+			return
+		}
+
+		outputDir := filepath.Join(s.jobDir(id), "output")
+		for _, caminho := range append(resultado.Documentos(), resultado.Pacotes...) {
+			if rel, err := filepath.Rel(outputDir, caminho); err == nil {
+				job.Documentos = append(job.Documentos, filepath.ToSlash(rel))
+			}
+		}
+		for _, falha := range resultado.Falhas {
+			job.Falhas = append(job.Falhas, fmt.Sprintf("%s: %v", falha.Nome, falha.Err))
+		}
+		job.Status = StatusConcluido
+		if len(job.Falhas) > 0 && len(job.Documentos) == 0 {
+			job.Status = StatusFalhou
+		}
+	})
+
+	job := s.getJob(id)
+	s.logger.Info("execução finalizada", "execucao", id, "status", job.Status, "documentos", len(job.Documentos), "falhas", len(job.Falhas))
+}
+
+// prune remove as execuções mais antigas além do limite configurado
+func (s *Server) prune() {
+	if s.cfg.MaxRuns <= 0 {
+		return
+	}
+
+	jobs := s.listJobs()
+	for _, job := range jobs[min(len(jobs), s.cfg.MaxRuns):] {
+		if job.Status == StatusPendente || job.Status == StatusExecutando {
+			continue
+		}
+		s.mu.Lock()
+		delete(s.jobs, job.ID)
+		s.mu.Unlock()
+		if err := os.RemoveAll(s.jobDir(job.ID)); err != nil {
+			s.logger.Error("erro ao remover execução antiga", "execucao", job.ID, "erro", err)
+		}
+	}
+}
+
+func (s *Server) update(id string, fn func(job *Job)) {
+	s.mu.Lock()
+	job, exists := s.jobs[id]
+	if !exists {
+		s.mu.Unlock()
+		return
+	}
+	fn(job)
+	copia := *job
+	s.mu.Unlock()
+
+	if err := s.saveJob(&copia); err != nil {
+		s.logger.Error("erro ao registrar execução", "execucao", id, "erro", err)
+	}
+}
+
+func (s *Server) getJob(id string) *Job {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	job, exists := s.jobs[id]
+	if !exists {
+		return nil
+	}
+	copia := *job
+	return &copia
+}
+
+// listJobs retorna as execuções da mais recente para a mais antiga
+func (s *Server) listJobs() []*Job {
+	s.mu.RLock()
+	jobs := make([]*Job, 0, len(s.jobs))
+	for _, job := range s.jobs {
+		copia := *job
+		jobs = append(jobs, &copia)
+	}
+	s.mu.RUnlock()
+
+	sort.Slice(jobs, func(i, j int) bool {
+		return jobs[i].CriadoEm.After(jobs[j].CriadoEm)
+	})
+	return jobs
+}
+
+func (s *Server) saveJob(job *Job) error {
+	dir := s.jobDir(job.ID)
+	if err := os.MkdirAll(dir, os.ModePerm); err != nil {
+		return err
+	}
+	content, err := json.MarshalIndent(job, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.WriteFile(filepath.Join(dir, jobFileName), content, 0644); err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	if _, exists := s.jobs[job.ID]; !exists {
+		copia := *job
+		s.jobs[job.ID] = &copia
+	}
+	s.mu.Unlock()
+	return nil
+}
+
+// loadJobs recupera as execuções guardadas; as interrompidas por uma parada
+// do servidor são marcadas como falha
+func (s *Server) loadJobs() error {
+	entries, err := os.ReadDir(s.cfg.DataDir)
+	if err != nil {
+		return err
+	}
+
+	for _, entry := range entries {
+		if !entry.IsDir() {
+			continue
+		}
+		content, err := os.ReadFile(filepath.Join(s.cfg.DataDir, entry.Name(), jobFileName))
+		if err != nil {
+			continue
+		}
+		var job Job
+		if err := json.Unmarshal(content, &job); err != nil || job.ID != entry.Name() {
+			s.logger.Warn("execução ignorada", "diretorio", entry.Name())
+			continue
+		}
+		if job.Status == StatusPendente || job.Status == StatusExecutando {
+			job.Status = StatusFalhou
+			job.Erro = "execução interrompida pela parada do servidor"
+		}
+		s.jobs[job.ID] = &job
+	}
+	return nil
+}
+
+func (s *Server) jobDir(id string) string {
+	return filepath.Join(s.cfg.DataDir, id)
+}
+
+// saveUpload grava o arquivo do campo informado, mantendo a extensão original,
+// que deve ser uma das extensões aceitas. Retorna caminho vazio quando o campo
+// não foi enviado.
+func saveUpload(r *http.Request, campo, dir, nome string, extensoes ...string) (string, string, error) {
+	file, header, err := r.FormFile(campo)
+	if err == http.ErrMissingFile {
+		return "", "", nil
+	}
+	if err != nil {
+		return "", "", err
+	}
+	defer file.Close()
+
+	ext := strings.ToLower(filepath.Ext(header.Filename))
+	if !slices.Contains(extensoes, ext) {
+		return "", "", fmt.Errorf("formato não suportado: %s (use %s)", header.Filename, strings.Join(extensoes, ", "))
+	}
+
+	if err := os.MkdirAll(dir, os.ModePerm); err != nil {
+		return "", "", err
+	}
+	path := filepath.Join(dir, nome+ext)
+	out, err := os.Create(path)
+	if err != nil {
+		return "", "", err
+	}
+	defer out.Close()
+
+	if _, err := io.Copy(out, file); err != nil {
+		return "", "", err
+	}
+	return path, filepath.Base(header.Filename), nil
+}
+
+func addToZip(archive *zip.Writer, path, nome string) error {
+	file, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	info, err := file.Stat()
+	if err != nil {
+		return err
+	}
+	header, err := zip.FileInfoHeader(info)
+	if err != nil {
+		return err
+	}
+	header.Name = nome
+	header.Method = zip.Deflate
+
+	entry, err := archive.CreateHeader(header)
+	if err != nil {
+		return err
+	}
+	_, err = io.Copy(entry, file)
+	return err
+}
+
+func newJobID() string {
+	sufixo := make([]byte, 3)
+	rand.Read(sufixo)
+	return time.Now().Format("20060102-150405") + "-" + hex.EncodeToString(sufixo)
+}
+
+func writeJSON(w http.ResponseWriter, status int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	w.WriteHeader(status)
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	encoder.Encode(v)
+}
+
+func methodNotAllowed(w http.ResponseWriter, methods ...string) {
+	w.Header().Set("Allow", strings.Join(methods, ", "))
+	http.Error(w, "método não permitido", http.StatusMethodNotAllowed)
+}
+
+func errorText(err error, padrao string) string {
+	if err != nil {
+		return err.Error()
+	}
+	return padrao
+}
+
+func contains(lista []string, item string) bool {
+	for _, v := range lista {
+		if v == item {
+			return true
+		}
+	}
+	return false
+}
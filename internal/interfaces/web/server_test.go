@@ -0,0 +1,133 @@
+package web
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"log/slog"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"sync"
+	"testing"
+
+	"report/internal/usecase"
+)
+
+// envioTeste monta o formulário de envio de uma listagem
+func envioTeste(t *testing.T, periodo string) *http.Request {
+	t.Helper()
+	var corpo bytes.Buffer
+	form := multipart.NewWriter(&corpo)
+	form.WriteField("periodo", periodo)
+	arquivo, err := form.CreateFormFile("listagem", "Listagem de Horas.csv")
+	if err != nil {
+		t.Fatal(err)
+	}
+	io.WriteString(arquivo, "dados")
+	form.Close()
+
+	req := httptest.NewRequest(http.MethodPost, "/runs", &corpo)
+	req.Header.Set("Content-Type", form.FormDataContentType())
+	req.Header.Set("Accept", "application/json")
+	return req
+}
+
+func TestServerExecucao(t *testing.T) {
+	var mu sync.Mutex
+	var estados []string
+	runner := func(ctx context.Context, req RunRequest) (*usecase.Resultado, error) {
+		mu.Lock()
+		estados = append(estados, req.EstadoDir)
+		mu.Unlock()
+		resultado := &usecase.Resultado{
+			Gerados: []string{filepath.Join(req.OutputDir, "SETOR 1", "VILA NOVA.pdf")},
+			Pacotes: []string{filepath.Join(req.OutputDir, "pacotes", "SETOR_1_"+req.Periodo+".zip")},
+		}
+		for _, caminho := range append(resultado.Gerados, resultado.Pacotes...) {
+			os.MkdirAll(filepath.Dir(caminho), 0o755)
+			os.WriteFile(caminho, []byte(filepath.Base(caminho)), 0o644)
+		}
+		return resultado, nil
+	}
+	dataDir := t.TempDir()
+	server, err := NewServer(Config{DataDir: dataDir}, runner, slog.New(slog.NewTextHandler(io.Discard, nil)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	handler := server.Handler()
+
+	var jobs []Job
+	for i := 0; i < 2; i++ {
+		resposta := httptest.NewRecorder()
+		handler.ServeHTTP(resposta, envioTeste(t, "2025-02"))
+		if resposta.Code != http.StatusAccepted {
+			t.Fatalf("POST /runs = %d: %s", resposta.Code, resposta.Body)
+		}
+		var job Job
+		if err := json.Unmarshal(resposta.Body.Bytes(), &job); err != nil {
+			t.Fatal(err)
+		}
+		jobs = append(jobs, job)
+	}
+	server.wg.Wait()
+
+	// Cada execução tem o próprio diretório de estado
+	if len(estados) != 2 || estados[0] == estados[1] {
+		t.Fatalf("diretórios de estado = %v, esperado um por execução", estados)
+	}
+	for _, estado := range estados {
+		if !strings.HasPrefix(estado, dataDir) {
+			t.Errorf("estado %s fora do diretório das execuções", estado)
+		}
+	}
+
+	job := server.getJob(jobs[0].ID)
+	esperados := []string{"SETOR 1/VILA NOVA.pdf", "pacotes/SETOR_1_2025-02.zip"}
+	if !reflect.DeepEqual(job.Documentos, esperados) {
+		t.Errorf("Documentos = %v, esperado %v", job.Documentos, esperados)
+	}
+	for _, documento := range esperados {
+		resposta := httptest.NewRecorder()
+		handler.ServeHTTP(resposta, httptest.NewRequest(http.MethodGet, "/runs/"+job.ID+"/files/"+strings.ReplaceAll(documento, " ", "%20"), nil))
+		if resposta.Code != http.StatusOK || resposta.Body.String() != filepath.Base(documento) {
+			t.Errorf("download de %s = %d %q", documento, resposta.Code, resposta.Body)
+		}
+	}
+}
+
+func TestServerEnvioInvalido(t *testing.T) {
+	tests := []struct {
+		nome    string
+		periodo string
+		status  int
+	}{
+		{"período vazio", "", http.StatusBadRequest},
+		{"período com dia", "2025-02-01", http.StatusBadRequest},
+		{"mês inexistente", "2025-13", http.StatusBadRequest},
+	}
+
+	runner := func(ctx context.Context, req RunRequest) (*usecase.Resultado, error) {
+		t.Error("execução iniciada com envio inválido")
+		return &usecase.Resultado{}, nil
+	}
+	server, err := NewServer(Config{DataDir: t.TempDir()}, runner, slog.New(slog.NewTextHandler(io.Discard, nil)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, tt := range tests {
+		t.Run(tt.nome, func(t *testing.T) {
+			resposta := httptest.NewRecorder()
+			server.Handler().ServeHTTP(resposta, envioTeste(t, tt.periodo))
+			if resposta.Code != tt.status {
+				t.Errorf("POST /runs = %d, esperado %d", resposta.Code, tt.status)
+			}
+		})
+	}
+	server.Close()
+}
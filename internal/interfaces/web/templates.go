@@ -0,0 +1,105 @@
+package web
+
+import (
+	"html/template"
+	"net/http"
+)
+
+const layout = `<!DOCTYPE html>
+<html lang="pt-BR">
+<head>
+<meta charset="utf-8">
+<title>Relatórios CCB</title>
+<style>
+body { font-family: Arial, sans-serif; margin: 2em auto; max-width: 860px; color: #222; }
+h1 { font-size: 1.5em; }
+table { border-collapse: collapse; width: 100%; margin-top: 1em; }
+th, td { border: 1px solid #ccc; padding: 6px 8px; text-align: left; }
+fieldset { border: 1px solid #ccc; padding: 1em; }
+label { display: block; margin: .6em 0 .2em; }
+button { margin-top: 1em; padding: .5em 1.5em; }
+.pendente, .executando { color: #a60; }
+.concluido { color: #080; }
+.falhou { color: #c00; }
+</style>
+</head>
+<body>
+{{template "conteudo" .}}
+</body>
+</html>`
+
+var indexTemplate = template.Must(template.Must(template.New("index").Parse(layout)).Parse(`
+{{define "conteudo"}}
+<h1>Gerador de Relatórios</h1>
+<form method="post" action="/runs" enctype="multipart/form-data">
+<fieldset>
+<label for="listagem">Listagem de horas (CSV, XLS ou XLSX)</label>
+<input type="file" id="listagem" name="listagem" accept=".csv,.xls,.xlsx" required>
+<label for="catalogo">Livros por localidade (opcional)</label>
+<input type="file" id="catalogo" name="catalogo" accept=".csv">
+<label for="periodo">Período</label>
+<input type="month" id="periodo" name="periodo" value="{{.Periodo}}" required>
+<br><button type="submit">Gerar relatórios</button>
+</fieldset>
+</form>
+
+<h2>Execuções anteriores</h2>
+{{if .Jobs}}
+<table>
+<tr><th>Data</th><th>Período</th><th>Listagem</th><th>Situação</th><th>Documentos</th></tr>
+{{range .Jobs}}
+<tr>
+<td><a href="/runs/{{.ID}}">{{.CriadoEm.Format "02/01/2006 15:04"}}</a></td>
+<td>{{.Periodo}}</td>
+<td>{{.Listagem}}</td>
+<td class="{{.Status}}">{{.Status}}</td>
+<td>{{len .Documentos}}</td>
+</tr>
+{{end}}
+</table>
+{{else}}
+<p>Nenhuma execução registrada.</p>
+{{end}}
+{{end}}`))
+
+var runTemplate = template.Must(template.Must(template.New("run").Parse(layout)).Parse(`
+{{define "conteudo"}}
+<p><a href="/">&larr; Voltar</a></p>
+<h1>Execução {{.ID}}</h1>
+<p>Período: {{.Periodo}}<br>Listagem: {{.Listagem}}{{if .Catalogo}}<br>Catálogo: {{.Catalogo}}{{end}}</p>
+<p>Situação: <strong id="status" class="{{.Status}}">{{.Status}}</strong></p>
+{{if .Erro}}<p class="falhou">{{.Erro}}</p>{{end}}
+{{if .Falhas}}
+<h2>Falhas</h2>
+<ul>{{range .Falhas}}<li class="falhou">{{.}}</li>{{end}}</ul>
+{{end}}
+{{if .Documentos}}
+<p><a href="/runs/{{.ID}}/zip">Baixar todos (ZIP)</a></p>
+<table>
+<tr><th>Documento</th></tr>
+{{$id := .ID}}
+{{range .Documentos}}<tr><td><a href="/runs/{{$id}}/files/{{.}}">{{.}}</a></td></tr>{{end}}
+</table>
+{{end}}
+{{if or (eq .Status "pendente") (eq .Status "executando")}}
+<script>
+(function poll() {
+  fetch("/runs/{{.ID}}/status").then(function (r) { return r.json(); }).then(function (job) {
+    if (job.status === "pendente" || job.status === "executando") {
+      document.getElementById("status").textContent = job.status;
+      setTimeout(poll, 2000);
+    } else {
+      location.reload();
+    }
+  }).catch(function () { setTimeout(poll, 5000); });
+})();
+</script>
+{{end}}
+{{end}}`))
+
+func render(w http.ResponseWriter, tmpl *template.Template, data interface{}) {
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	if err := tmpl.Execute(w, data); err != nil {
+		http.Error(w, "erro ao montar a página", http.StatusInternalServerError)
+	}
+}
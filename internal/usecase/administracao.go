@@ -0,0 +1,178 @@
+package usecase
+
+import (
+	"fmt"
+	"path/filepath"
+	"sort"
+	"time"
+
+	"report/internal/domain"
+)
+
+// nomeSemSetor identifica o grupo das localidades que não pertencem a nenhum setor
+const nomeSemSetor = "Localidades sem setor"
+
+// AdministracaoReportData contém os totais consolidados de uma administração
+// e de cada um dos seus setores
+type AdministracaoReportData struct {
+	Titulo        string
+	Data          time.Time `json:"-"`
+	Periodo       string
+	Administracao string
+	Consolidado   ResumoConsolidado
+	Setores       []ResumoConsolidado
+}
+
+// ResumoConsolidado reúne os dados das localidades de um nível da hierarquia
+// (administração ou setor)
+type ResumoConsolidado struct {
+	Nome        string
+	Localidades int
+	Alertas     int
+	Livros      map[string]*domain.Summary
+	// Cumprimento é a média do cumprimento geral das localidades com metas
+	Cumprimento float64
+	ComMetas    int
+}
+
+// consolidarLivros soma os livros das localidades informadas; os voluntários
+// são contados uma única vez mesmo que atuem em mais de uma localidade
+func consolidarLivros(localidades map[string]map[string]*domain.Summary, nomes []string) map[string]*domain.Summary {
+	consolidado := make(map[string]map[string]*domain.Summary, 1)
+	consolidado[""] = make(map[string]*domain.Summary)
+	for _, nome := range nomes {
+		mergeLivros(consolidado, "", localidades[nome])
+	}
+	return consolidado[""]
+}
+
+// resumoConsolidado consolida os livros, alertas e cumprimento das localidades
+func resumoConsolidado(
+	nome string,
+	snapshot *domain.Snapshot,
+	localidades []string,
+	cumprimentos map[string]*CumprimentoLocalidade,
+) ResumoConsolidado {
+	resumo := ResumoConsolidado{
+		Nome:        nome,
+		Localidades: len(localidades),
+		Livros:      consolidarLivros(snapshot.Localidades, localidades),
+	}
+
+	total := 0.0
+	for _, localidade := range localidades {
+		resumo.Alertas += len(snapshot.Alertas[localidade])
+		if cumprimento := cumprimentos[localidade]; cumprimento != nil {
+			total += cumprimento.Percentual
+			resumo.ComMetas++
+		}
+	}
+	if resumo.ComMetas > 0 {
+		resumo.Cumprimento = total / float64(resumo.ComMetas)
+	}
+	return resumo
+}
+
+// administracaoSetor retorna a administração cadastrada para o setor ou, sem
+// cadastro, a indicada na listagem para a maioria das suas localidades
+func administracaoSetor(setor *domain.Setor, indicadas map[string]string) string {
+	if setor.Administracao != "" {
+		return setor.Administracao
+	}
+	contagem := make(map[string]int)
+	for _, localidade := range setor.Localidades {
+		if administracao := indicadas[localidade]; administracao != "" {
+			contagem[administracao]++
+		}
+	}
+	escolhida := ""
+	for _, administracao := range OrdenarChaves(contagem) {
+		if contagem[administracao] > contagem[escolhida] {
+			escolhida = administracao
+		}
+	}
+	return escolhida
+}
+
+// documentosAdministracoes monta o relatório consolidado de cada
+// administração: os setores vêm do cadastro ou, sem administração
+// cadastrada, seguem a indicada na listagem para as suas localidades, assim
+// como as localidades sem setor
+func (g *ReportGenerator) documentosAdministracoes(
+	snapshot *domain.Snapshot,
+	grupos map[string]*grupoSetor,
+	cumprimentos map[string]*CumprimentoLocalidade,
+) ([]documento, error) {
+	cadastro, err := g.setorRepo.GetAdministracoes()
+	if err != nil {
+		return nil, fmt.Errorf("erro ao obter administrações: %v", err)
+	}
+
+	// administração -> setor -> localidades
+	hierarquia := make(map[string]map[string][]string)
+	incluir := func(administracao, setor string, localidades ...string) {
+		if administracao == "" {
+			return
+		}
+		if hierarquia[administracao] == nil {
+			hierarquia[administracao] = make(map[string][]string)
+		}
+		hierarquia[administracao][setor] = append(hierarquia[administracao][setor], localidades...)
+	}
+
+	for _, administracao := range cadastro {
+		for _, setor := range administracao.Setores {
+			incluir(administracao.Nome, setor.Nome)
+		}
+	}
+	for _, diretorio := range OrdenarChaves(grupos) {
+		grupo := grupos[diretorio]
+		for _, localidade := range grupo.Localidades {
+			if diretorio == diretorioOutros {
+				incluir(snapshot.Administracoes[localidade], nomeSemSetor, localidade)
+			} else {
+				incluir(grupo.Administracao, grupo.Nome, localidade)
+			}
+		}
+	}
+
+	var documentos []documento
+	for _, administracao := range OrdenarChaves(hierarquia) {
+		setores := hierarquia[administracao]
+		data := &AdministracaoReportData{
+			Titulo:        fmt.Sprintf("Relatório da Administração %s", administracao),
+			Data:          time.Now(),
+			Periodo:       g.periodo,
+			Administracao: administracao,
+		}
+
+		nomes := OrdenarChaves(setores)
+		sort.SliceStable(nomes, func(i, j int) bool {
+			return nomes[i] != nomeSemSetor && nomes[j] == nomeSemSetor
+		})
+		var todas []string
+		for _, setor := range nomes {
+			localidades := setores[setor]
+			sort.Strings(localidades)
+			todas = append(todas, localidades...)
+			data.Setores = append(data.Setores, resumoConsolidado(setor, snapshot, localidades, cumprimentos))
+		}
+		data.Consolidado = resumoConsolidado(administracao, snapshot, todas, cumprimentos)
+
+		caminho := g.administracaoOutputPath(administracao)
+		documentos = append(documentos, documento{
+			Nome:    fmt.Sprintf("relatório da administração %s", administracao),
+			Caminho: caminho,
+			Chave:   g.chaveDocumento("administracao", data),
+			gerar: func() error {
+				return g.pdfService.GenerateAdministracaoReport(data, caminho)
+			},
+		})
+	}
+
+	return documentos, nil
+}
+
+func (g *ReportGenerator) administracaoOutputPath(administracao string) string {
+	return filepath.Join(g.outputDir, fmt.Sprintf("administracao-%s.pdf", administracao))
+}
@@ -0,0 +1,129 @@
+package usecase
+
+import (
+	"reflect"
+	"testing"
+
+	"report/internal/domain"
+)
+
+// setorRepoAdministracoes acrescenta ao setorRepoMemoria o cadastro de administrações
+type setorRepoAdministracoes struct {
+	setorRepoMemoria
+	administracoes map[string]*domain.Administracao
+}
+
+func (r setorRepoAdministracoes) GetAdministracoes() (map[string]*domain.Administracao, error) {
+	return r.administracoes, nil
+}
+
+func TestAdministracaoSetor(t *testing.T) {
+	indicadas := map[string]string{"VILA NOVA": "ADM B", "CENTRO": "ADM A", "JARDIM": "ADM B", "AVULSA": ""}
+	tests := []struct {
+		nome     string
+		setor    *domain.Setor
+		esperada string
+	}{
+		{"administração cadastrada", &domain.Setor{Administracao: "ADM C", Localidades: []string{"VILA NOVA"}}, "ADM C"},
+		{"maioria das localidades", &domain.Setor{Localidades: []string{"VILA NOVA", "CENTRO", "JARDIM"}}, "ADM B"},
+		{"empate pela ordem alfabética", &domain.Setor{Localidades: []string{"VILA NOVA", "CENTRO"}}, "ADM A"},
+		{"sem indicação", &domain.Setor{Localidades: []string{"AVULSA"}}, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.nome, func(t *testing.T) {
+			if administracao := administracaoSetor(tt.setor, indicadas); administracao != tt.esperada {
+				t.Errorf("administracaoSetor = %q, esperado %q", administracao, tt.esperada)
+			}
+		})
+	}
+}
+
+func TestConsolidarLivros(t *testing.T) {
+	localidades := map[string]map[string]*domain.Summary{
+		"VILA NOVA": {"LIMPEZA": {TotalTrabalhos: 3, Horas: 6, Voluntarios: map[string]int{"A": 2, "B": 1}}},
+		"CENTRO":    {"LIMPEZA": {TotalTrabalhos: 2, Horas: 4, Voluntarios: map[string]int{"A": 2}}},
+		"JARDIM":    {"LIMPEZA": {TotalTrabalhos: 9, Horas: 9, Voluntarios: map[string]int{"C": 9}}},
+	}
+
+	totais := consolidarLivros(localidades, []string{"VILA NOVA", "CENTRO"})
+
+	limpeza := totais["LIMPEZA"]
+	if limpeza.TotalTrabalhos != 5 || limpeza.Horas != 10 {
+		t.Errorf("LIMPEZA = %d apontamentos e %g horas, esperado 5 e 10", limpeza.TotalTrabalhos, limpeza.Horas)
+	}
+	if limpeza.TotalVoluntarios() != 2 {
+		t.Errorf("%d voluntários, esperado 2 contados uma única vez", limpeza.TotalVoluntarios())
+	}
+	if localidades["VILA NOVA"]["LIMPEZA"].TotalTrabalhos != 3 {
+		t.Error("consolidarLivros alterou os dados das localidades")
+	}
+}
+
+func TestDocumentosAdministracoes(t *testing.T) {
+	setores := setorRepoAdministracoes{
+		setorRepoMemoria: setorRepoMemoria{},
+		administracoes: map[string]*domain.Administracao{
+			"ADM A": {Nome: "ADM A", Setores: []*domain.Setor{{Nome: "Setor 1"}, {Nome: "Setor 3"}}},
+		},
+	}
+	snapshot := &domain.Snapshot{
+		Localidades: map[string]map[string]*domain.Summary{
+			"VILA NOVA": {"LIMPEZA": summaryTeste(map[string]int{"A": 2})},
+			"CENTRO":    {"LIMPEZA": summaryTeste(map[string]int{"B": 1})},
+			"JARDIM":    {"LIMPEZA": summaryTeste(map[string]int{"C": 4})},
+			"AVULSA":    {"LIMPEZA": summaryTeste(map[string]int{"D": 1})},
+		},
+		Alertas:        map[string][]domain.Alerta{"CENTRO": {{Mensagem: "x"}}, "AVULSA": {{Mensagem: "y"}}},
+		Administracoes: map[string]string{"AVULSA": "ADM A", "JARDIM": "ADM B"},
+	}
+	grupos := map[string]*grupoSetor{
+		"Setor 1":       {Nome: "Setor 1", Administracao: "ADM A", Localidades: []string{"VILA NOVA", "CENTRO"}},
+		"Setor 2":       {Nome: "Setor 2", Administracao: "ADM B", Localidades: []string{"JARDIM"}},
+		diretorioOutros: {Nome: diretorioOutros, Localidades: []string{"AVULSA"}},
+	}
+	cumprimentos := map[string]*CumprimentoLocalidade{
+		"VILA NOVA": {Percentual: 100},
+		"CENTRO":    {Percentual: 50},
+	}
+	pdf := &pdfMemoria{}
+	g := NewReportGenerator(nil, setores, nil, pdf, WithOutputDir("saida"), WithPeriodo("2025-02"))
+
+	documentos, err := g.documentosAdministracoes(snapshot, grupos, cumprimentos)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, doc := range documentos {
+		doc.gerar()
+	}
+
+	type setorEsperado struct {
+		Nome        string
+		Localidades int
+		Alertas     int
+		Cumprimento float64
+	}
+	esperados := map[string][]setorEsperado{
+		"ADM A": {{"Setor 1", 2, 1, 75}, {"Setor 3", 0, 0, 0}, {nomeSemSetor, 1, 1, 0}},
+		"ADM B": {{"Setor 2", 1, 0, 0}},
+	}
+	if len(pdf.documentos) != len(esperados) {
+		t.Fatalf("%d relatórios de administração, esperado %d", len(pdf.documentos), len(esperados))
+	}
+	for administracao, setores := range esperados {
+		data, ok := pdf.documentos[g.administracaoOutputPath(administracao)].(*AdministracaoReportData)
+		if !ok {
+			t.Fatalf("relatório da %s não gerado", administracao)
+		}
+		var obtidos []setorEsperado
+		for _, setor := range data.Setores {
+			obtidos = append(obtidos, setorEsperado{setor.Nome, setor.Localidades, setor.Alertas, setor.Cumprimento})
+		}
+		if !reflect.DeepEqual(obtidos, setores) {
+			t.Errorf("setores da %s = %v, esperado %v", administracao, obtidos, setores)
+		}
+	}
+	consolidado := pdf.documentos[g.administracaoOutputPath("ADM A")].(*AdministracaoReportData).Consolidado
+	if consolidado.Localidades != 3 || consolidado.Alertas != 2 || consolidado.Cumprimento != 75 || consolidado.ComMetas != 2 {
+		t.Errorf("consolidado da ADM A = %+v", consolidado)
+	}
+}
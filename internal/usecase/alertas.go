@@ -0,0 +1,68 @@
+package usecase
+
+import (
+	"fmt"
+
+	"report/internal/domain"
+)
+
+// versaoRegras identifica as regras de alerta nas chaves do cache de
+// documentos; altere ao mudar as regras para que os relatórios sejam refeitos
+const versaoRegras = "5"
+
+// minimosManutencao é o mínimo mensal de manutenção preventiva em cada
+// métrica das regras
+var minimosManutencao = map[domain.Metrica]float64{
+	domain.MetricaLancamentos: 8,
+	domain.MetricaHoras:       24,
+	domain.MetricaVoluntarios: 3,
+}
+
+// WithMinimoManutencao substitui o mínimo de manutenção preventiva da métrica
+// das regras; zero mantém o mínimo padrão da métrica
+func WithMinimoManutencao(minimo float64) Option {
+	return func(g *ReportGenerator) {
+		if minimo > 0 {
+			g.minimoManutencao = minimo
+		}
+	}
+}
+
+// minimoManutencaoMetrica retorna o mínimo de manutenção na métrica das regras
+func (g *ReportGenerator) minimoManutencaoMetrica() float64 {
+	if g.minimoManutencao > 0 {
+		return g.minimoManutencao
+	}
+	return minimosManutencao[g.metrica]
+}
+
+// avaliarAlertas identifica os pontos de atenção de uma localidade, medindo
+// os livros pela métrica informada contra o mínimo de manutenção. Com plano
+// de manutenção, o mínimo dá lugar aos alertas do plano.
+func avaliarAlertas(livros map[string]*domain.Summary, metrica domain.Metrica, minimo float64, comPlano bool) []domain.Alerta {
+	var alertas []domain.Alerta
+
+	if _, exists := livros[domain.LivroAdministracao]; !exists {
+		alertas = append(alertas, domain.Alerta{
+			Livro:      domain.LivroAdministracao,
+			Mensagem:   "Não há apontamentos de ADMINISTRAÇÃO.",
+			Severidade: domain.SeveridadeMedia,
+		})
+	}
+	if mp, exists := livros[domain.LivroManutencao]; !comPlano && (!exists || mp.Valor(metrica) < minimo) {
+		alertas = append(alertas, domain.Alerta{
+			Livro:      domain.LivroManutencao,
+			Mensagem:   fmt.Sprintf("Menos de %g %s de MANUTENÇÃO.", minimo, metrica.Unidade()),
+			Severidade: domain.SeveridadeMedia,
+		})
+	}
+	if _, exists := livros[domain.LivroBrigada]; !exists {
+		alertas = append(alertas, domain.Alerta{
+			Livro:      domain.LivroBrigada,
+			Mensagem:   "Não há apontamentos de BRIGADA DE INCÊNDIO.",
+			Severidade: domain.SeveridadeAlta,
+		})
+	}
+
+	return alertas
+}
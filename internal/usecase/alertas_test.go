@@ -0,0 +1,105 @@
+package usecase
+
+import (
+	"reflect"
+	"testing"
+
+	"report/internal/domain"
+)
+
+func TestAvaliarAlertas(t *testing.T) {
+	// manutencao cria o livro de manutenção com 6 apontamentos, 20 horas e 2 voluntários
+	manutencao := &domain.Summary{TotalTrabalhos: 6, Horas: 20, Voluntarios: map[string]int{"A": 4, "B": 2}}
+	completos := map[string]*domain.Summary{
+		domain.LivroAdministracao: summaryTeste(map[string]int{"A": 1}),
+		domain.LivroManutencao:    manutencao,
+		domain.LivroBrigada:       summaryTeste(map[string]int{"A": 1}),
+	}
+
+	tests := []struct {
+		nome     string
+		livros   map[string]*domain.Summary
+		metrica  domain.Metrica
+		minimo   float64
+		comPlano bool
+		esperado []domain.Alerta
+	}{
+		{
+			nome:    "abaixo do mínimo de apontamentos",
+			livros:  completos,
+			metrica: domain.MetricaLancamentos,
+			minimo:  8,
+			esperado: []domain.Alerta{
+				{Livro: domain.LivroManutencao, Mensagem: "Menos de 8 apontamentos de MANUTENÇÃO.", Severidade: domain.SeveridadeMedia},
+			},
+		},
+		{
+			nome:    "abaixo do mínimo de horas",
+			livros:  completos,
+			metrica: domain.MetricaHoras,
+			minimo:  24,
+			esperado: []domain.Alerta{
+				{Livro: domain.LivroManutencao, Mensagem: "Menos de 24 horas de MANUTENÇÃO.", Severidade: domain.SeveridadeMedia},
+			},
+		},
+		{
+			nome:    "mínimo de voluntários atingido",
+			livros:  completos,
+			metrica: domain.MetricaVoluntarios,
+			minimo:  2,
+		},
+		{
+			nome:    "mínimo de horas configurado",
+			livros:  completos,
+			metrica: domain.MetricaHoras,
+			minimo:  12.5,
+		},
+		{
+			nome:     "plano de manutenção substitui o mínimo",
+			livros:   completos,
+			metrica:  domain.MetricaLancamentos,
+			minimo:   8,
+			comPlano: true,
+		},
+		{
+			nome:    "livros ausentes",
+			livros:  map[string]*domain.Summary{},
+			metrica: domain.MetricaLancamentos,
+			minimo:  8,
+			esperado: []domain.Alerta{
+				{Livro: domain.LivroAdministracao, Mensagem: "Não há apontamentos de ADMINISTRAÇÃO.", Severidade: domain.SeveridadeMedia},
+				{Livro: domain.LivroManutencao, Mensagem: "Menos de 8 apontamentos de MANUTENÇÃO.", Severidade: domain.SeveridadeMedia},
+				{Livro: domain.LivroBrigada, Mensagem: "Não há apontamentos de BRIGADA DE INCÊNDIO.", Severidade: domain.SeveridadeAlta},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.nome, func(t *testing.T) {
+			alertas := avaliarAlertas(tt.livros, tt.metrica, tt.minimo, tt.comPlano)
+			if !reflect.DeepEqual(alertas, tt.esperado) {
+				t.Errorf("avaliarAlertas =\n%v\nesperado\n%v", alertas, tt.esperado)
+			}
+		})
+	}
+}
+
+func TestMinimoManutencaoMetrica(t *testing.T) {
+	tests := []struct {
+		metrica  domain.Metrica
+		minimo   float64
+		esperado float64
+	}{
+		{domain.MetricaLancamentos, 0, 8},
+		{domain.MetricaHoras, 0, 24},
+		{domain.MetricaVoluntarios, 0, 3},
+		{domain.MetricaHoras, 30, 30},
+		{domain.MetricaVoluntarios, -1, 3},
+	}
+	for _, tt := range tests {
+		g := NewReportGenerator(nil, nil, nil, nil, WithMetrica(tt.metrica), WithMinimoManutencao(tt.minimo))
+		if minimo := g.minimoManutencaoMetrica(); minimo != tt.esperado {
+			t.Errorf("%s com mínimo %g: %g, esperado %g", tt.metrica, tt.minimo, minimo, tt.esperado)
+		}
+	}
+}
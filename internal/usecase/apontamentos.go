@@ -0,0 +1,135 @@
+package usecase
+
+import (
+	"fmt"
+	"time"
+
+	"report/internal/domain"
+)
+
+// Tendencia compara um livro com o período anterior do histórico
+type Tendencia string
+
+// Tendências possíveis; TendenciaSemHistorico indica que não há período
+// anterior com dados da localidade
+const (
+	TendenciaSemHistorico Tendencia = ""
+	TendenciaAlta         Tendencia = "alta"
+	TendenciaEstavel      Tendencia = "estavel"
+	TendenciaBaixa        Tendencia = "baixa"
+)
+
+// ApontamentoLivro resume os apontamentos de um livro na coluna Apontamentos
+// do relatório da localidade
+type ApontamentoLivro struct {
+	// UltimoLancamento é zero quando a data não consta da listagem
+	UltimoLancamento  time.Time
+	DiasSemLancamento int
+	Voluntarios       int
+	Tendencia         Tendencia
+}
+
+// WithObservacaoRepository habilita as observações livres impressas no
+// quadro OBSERVAÇÕES do relatório de cada localidade
+func WithObservacaoRepository(observacaoRepo domain.ObservacaoRepository) Option {
+	return func(g *ReportGenerator) {
+		g.observacaoRepo = observacaoRepo
+	}
+}
+
+// carregarObservacoes retorna as observações do período agrupadas por
+// localidade, na ordem do arquivo
+func (g *ReportGenerator) carregarObservacoes() (map[string][]string, error) {
+	observacoes := make(map[string][]string)
+	if g.observacaoRepo == nil {
+		return observacoes, nil
+	}
+	todas, err := g.observacaoRepo.GetAll()
+	if err != nil {
+		return nil, fmt.Errorf("erro ao obter observações: %v", err)
+	}
+	for _, observacao := range todas {
+		if observacao.Periodo == "" || observacao.Periodo == g.periodo {
+			observacoes[observacao.Localidade] = append(observacoes[observacao.Localidade], observacao.Texto)
+		}
+	}
+	return observacoes, nil
+}
+
+// carregarAnterior retorna os dados do período anterior do histórico. Sem
+// histórico, ou se ele não puder ser lido, as tendências não são calculadas.
+func (g *ReportGenerator) carregarAnterior() *domain.Snapshot {
+	if g.historicoRepo == nil {
+		return nil
+	}
+	anterior, err := g.historicoRepo.GetAnterior(g.periodo)
+	if err != nil {
+		g.logger.Warn("histórico do período anterior ignorado", "periodo", g.periodo, "erro", err)
+		return nil
+	}
+	return anterior
+}
+
+// dataReferencia é o último dia do período ou, para o período corrente, o
+// dia atual; os dias sem lançamento são contados até ela
+func (g *ReportGenerator) dataReferencia() time.Time {
+	hoje := time.Now().UTC().Truncate(24 * time.Hour)
+	inicio, err := time.Parse(domain.LayoutPeriodo, g.periodo)
+	if err != nil {
+		return hoje
+	}
+	fim := inicio.AddDate(0, 1, -1)
+	if fim.After(hoje) {
+		return hoje
+	}
+	return fim
+}
+
+// avaliarApontamentos calcula as notas de cada livro da localidade. A
+// tendência compara a métrica do livro com a do período anterior; livros
+// sem apontamentos no período anterior contam como zero.
+func avaliarApontamentos(
+	dados map[string]*domain.Summary,
+	anteriores map[string]*domain.Summary,
+	comAnterior bool,
+	metrica domain.Metrica,
+	referencia time.Time,
+) map[string]*ApontamentoLivro {
+	apontamentos := make(map[string]*ApontamentoLivro, len(dados))
+	for livro, summary := range dados {
+		apontamento := &ApontamentoLivro{
+			UltimoLancamento: summary.UltimoLancamento,
+			Voluntarios:      summary.TotalVoluntarios(),
+		}
+		if !summary.UltimoLancamento.IsZero() {
+			dias := int(referencia.Sub(summary.UltimoLancamento).Hours() / 24)
+			apontamento.DiasSemLancamento = max(dias, 0)
+		}
+
+		if comAnterior {
+			anterior := 0.0
+			if summary, exists := anteriores[livro]; exists {
+				anterior = summary.Valor(metrica)
+			}
+			switch atual := summary.Valor(metrica); {
+			case atual > anterior:
+				apontamento.Tendencia = TendenciaAlta
+			case atual < anterior:
+				apontamento.Tendencia = TendenciaBaixa
+			default:
+				apontamento.Tendencia = TendenciaEstavel
+			}
+		}
+		apontamentos[livro] = apontamento
+	}
+
+	// Livros que deixaram de ter apontamentos aparecem em queda
+	if comAnterior {
+		for livro, summary := range anteriores {
+			if _, exists := apontamentos[livro]; !exists && summary.Valor(metrica) > 0 {
+				apontamentos[livro] = &ApontamentoLivro{Tendencia: TendenciaBaixa}
+			}
+		}
+	}
+	return apontamentos
+}
@@ -0,0 +1,143 @@
+package usecase
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"report/internal/domain"
+)
+
+// observacaoRepoMemoria implementa ObservacaoRepository em memória
+type observacaoRepoMemoria []domain.Observacao
+
+func (r observacaoRepoMemoria) GetAll() ([]domain.Observacao, error) {
+	return r, nil
+}
+
+func TestAvaliarApontamentos(t *testing.T) {
+	referencia := time.Date(2025, 2, 28, 0, 0, 0, 0, time.UTC)
+	dados := map[string]*domain.Summary{
+		"MANUTENCAO": {
+			TotalTrabalhos:   5,
+			UltimoLancamento: time.Date(2025, 2, 18, 0, 0, 0, 0, time.UTC),
+			Voluntarios:      map[string]int{"ANA": 3, "JOSE": 2},
+		},
+		"LIMPEZA": {TotalTrabalhos: 2, Voluntarios: map[string]int{"ANA": 2}},
+	}
+
+	tests := []struct {
+		nome        string
+		anteriores  map[string]*domain.Summary
+		comAnterior bool
+		esperado    map[string]*ApontamentoLivro
+	}{
+		{
+			nome: "sem histórico",
+			esperado: map[string]*ApontamentoLivro{
+				"MANUTENCAO": {UltimoLancamento: dados["MANUTENCAO"].UltimoLancamento, DiasSemLancamento: 10, Voluntarios: 2},
+				"LIMPEZA":    {Voluntarios: 1},
+			},
+		},
+		{
+			nome: "alta, estável e livro que deixou de ter apontamentos",
+			anteriores: map[string]*domain.Summary{
+				"MANUTENCAO": {TotalTrabalhos: 3},
+				"LIMPEZA":    {TotalTrabalhos: 2},
+				"JARDINAGEM": {TotalTrabalhos: 4},
+				"PORTARIA":   {},
+			},
+			comAnterior: true,
+			esperado: map[string]*ApontamentoLivro{
+				"MANUTENCAO": {UltimoLancamento: dados["MANUTENCAO"].UltimoLancamento, DiasSemLancamento: 10, Voluntarios: 2, Tendencia: TendenciaAlta},
+				"LIMPEZA":    {Voluntarios: 1, Tendencia: TendenciaEstavel},
+				"JARDINAGEM": {Tendencia: TendenciaBaixa},
+			},
+		},
+		{
+			nome:        "baixa e livro sem apontamentos no período anterior",
+			anteriores:  map[string]*domain.Summary{"MANUTENCAO": {TotalTrabalhos: 8}},
+			comAnterior: true,
+			esperado: map[string]*ApontamentoLivro{
+				"MANUTENCAO": {UltimoLancamento: dados["MANUTENCAO"].UltimoLancamento, DiasSemLancamento: 10, Voluntarios: 2, Tendencia: TendenciaBaixa},
+				"LIMPEZA":    {Voluntarios: 1, Tendencia: TendenciaAlta},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.nome, func(t *testing.T) {
+			apontamentos := avaliarApontamentos(dados, tt.anteriores, tt.comAnterior, domain.MetricaLancamentos, referencia)
+			if !reflect.DeepEqual(apontamentos, tt.esperado) {
+				for livro, apontamento := range apontamentos {
+					t.Logf("%s: %+v", livro, *apontamento)
+				}
+				t.Errorf("apontamentos diferentes do esperado")
+			}
+		})
+	}
+}
+
+func TestDataReferencia(t *testing.T) {
+	hoje := time.Now().UTC().Truncate(24 * time.Hour)
+
+	tests := []struct {
+		periodo  string
+		esperado time.Time
+	}{
+		{"2025-02", time.Date(2025, 2, 28, 0, 0, 0, 0, time.UTC)},
+		{"2024-02", time.Date(2024, 2, 29, 0, 0, 0, 0, time.UTC)},
+		{hoje.Format(domain.LayoutPeriodo), hoje},
+		{hoje.AddDate(1, 0, 0).Format(domain.LayoutPeriodo), hoje},
+	}
+	for _, tt := range tests {
+		g := NewReportGenerator(nil, nil, nil, nil, WithPeriodo(tt.periodo))
+		if referencia := g.dataReferencia(); !referencia.Equal(tt.esperado) {
+			t.Errorf("dataReferencia(%s) = %s, esperado %s", tt.periodo, referencia, tt.esperado)
+		}
+	}
+}
+
+func TestCarregarObservacoes(t *testing.T) {
+	repo := observacaoRepoMemoria{
+		{Periodo: "2025-02", Localidade: "VILA NOVA", Texto: "Telhado revisado"},
+		{Periodo: "2025-01", Localidade: "VILA NOVA", Texto: "Do mês anterior"},
+		{Localidade: "VILA NOVA", Texto: "Sem período"},
+		{Periodo: "2025-02", Localidade: "CENTRO", Texto: "Pintura"},
+	}
+
+	tests := []struct {
+		nome     string
+		opts     []Option
+		esperado map[string][]string
+	}{
+		{
+			nome: "observações do período e sem período",
+			opts: []Option{WithPeriodo("2025-02"), WithObservacaoRepository(repo)},
+			esperado: map[string][]string{
+				"VILA NOVA": {"Telhado revisado", "Sem período"},
+				"CENTRO":    {"Pintura"},
+			},
+		},
+		{
+			nome:     "outro período",
+			opts:     []Option{WithPeriodo("2024-12"), WithObservacaoRepository(repo)},
+			esperado: map[string][]string{"VILA NOVA": {"Sem período"}},
+		},
+		{
+			nome:     "sem repositório",
+			opts:     []Option{WithPeriodo("2025-02")},
+			esperado: map[string][]string{},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.nome, func(t *testing.T) {
+			observacoes, err := NewReportGenerator(nil, nil, nil, nil, tt.opts...).carregarObservacoes()
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(observacoes, tt.esperado) {
+				t.Errorf("observações = %v, esperado %v", observacoes, tt.esperado)
+			}
+		})
+	}
+}
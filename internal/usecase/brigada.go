@@ -0,0 +1,169 @@
+package usecase
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	"report/internal/domain"
+)
+
+// ValidadeBrigadaPadrao é a validade, em meses, do treinamento da brigada
+const ValidadeBrigadaPadrao = 12
+
+// avisoBrigada é a antecedência com que um treinamento é considerado a vencer
+const avisoBrigada = 30 * 24 * time.Hour
+
+// SituacaoTreinamento classifica o treinamento de um voluntário da brigada
+type SituacaoTreinamento string
+
+// Situações do treinamento na data de referência do período
+const (
+	TreinamentoValido  SituacaoTreinamento = "válido"
+	TreinamentoAVencer SituacaoTreinamento = "a vencer"
+	TreinamentoVencido SituacaoTreinamento = "vencido"
+)
+
+// TreinamentoVoluntario é o treinamento de um voluntário da brigada
+type TreinamentoVoluntario struct {
+	Nome     string
+	Ultimo   time.Time
+	Validade time.Time
+	Situacao SituacaoTreinamento
+}
+
+// BrigadaLocalidade resume os treinamentos da brigada de uma localidade
+type BrigadaLocalidade struct {
+	ValidadeMeses int
+	Validos       int
+	AVencer       int
+	Vencidos      int
+	// Voluntarios vêm ordenados pela validade, dos vencidos aos válidos
+	Voluntarios []TreinamentoVoluntario
+}
+
+// WithBrigada habilita o acompanhamento dos treinamentos da Brigada de
+// Incêndio: cada apontamento no livro da brigada conta como treinamento,
+// válido pelo número de meses informado
+func WithBrigada(brigadaRepo domain.BrigadaRepository, validadeMeses int) Option {
+	return func(g *ReportGenerator) {
+		g.brigadaRepo = brigadaRepo
+		g.validadeBrigada = ValidadeBrigadaPadrao
+		if validadeMeses > 0 {
+			g.validadeBrigada = validadeMeses
+		}
+	}
+}
+
+// avaliarBrigadas incorpora ao registro gravado os treinamentos da listagem e
+// avalia a brigada de cada localidade na data de referência. O registro
+// atualizado é retornado para ser gravado ao final da geração dos relatórios.
+func (g *ReportGenerator) avaliarBrigadas(localidades map[string]map[string]*domain.Summary) (map[string]*BrigadaLocalidade, domain.RegistroBrigada, error) {
+	anterior, err := g.brigadaRepo.Get()
+	if err != nil {
+		return nil, nil, fmt.Errorf("erro ao obter registro da brigada: %v", err)
+	}
+	registro := make(domain.RegistroBrigada)
+	for localidade, livros := range localidades {
+		if summary, exists := livros[domain.LivroBrigada]; exists {
+			for voluntario, data := range summary.UltimoLancamentoVoluntarios {
+				registro.Registrar(localidade, g.privacidade.Chave(voluntario), data)
+			}
+		}
+	}
+	incorporarRegistro(registro, anterior, g.privacidade)
+
+	referencia := g.dataReferencia()
+	brigadas := make(map[string]*BrigadaLocalidade, len(localidades))
+	for localidade := range localidades {
+		brigadas[localidade] = avaliarBrigada(registro.Treinamentos(localidade, referencia), g.validadeBrigada, referencia, g.privacidade)
+	}
+	return brigadas, registro, nil
+}
+
+// incorporarRegistro acrescenta ao registro os treinamentos do registro
+// gravado. As chaves gravadas, que podem ser nomes completos de versões
+// anteriores ou chaves de outra política, são reunidas à chave atual do
+// mesmo voluntário.
+func incorporarRegistro(registro, anterior domain.RegistroBrigada, privacidade domain.Privacidade) {
+	for localidade, voluntarios := range anterior {
+		porResumo := make(map[string]string)
+		for chave := range registro[localidade] {
+			porResumo[domain.ResumoNome(chave)] = chave
+		}
+		for _, voluntario := range OrdenarChaves(voluntarios) {
+			chave := privacidade.Chave(voluntario)
+			resumo := domain.ResumoNome(chave)
+			if atual, exists := porResumo[resumo]; exists {
+				chave = atual
+			} else {
+				porResumo[resumo] = chave
+			}
+			for _, data := range voluntarios[voluntario] {
+				registro.Registrar(localidade, chave, data)
+			}
+		}
+	}
+}
+
+// avaliarBrigada classifica o treinamento de cada voluntário na data de referência
+func avaliarBrigada(
+	treinamentos map[string]time.Time,
+	validadeMeses int,
+	referencia time.Time,
+	privacidade domain.Privacidade,
+) *BrigadaLocalidade {
+	brigada := &BrigadaLocalidade{ValidadeMeses: validadeMeses}
+	for _, voluntario := range OrdenarChaves(treinamentos) {
+		ultimo := treinamentos[voluntario]
+		treinamento := TreinamentoVoluntario{
+			Nome:     privacidade.Nome(voluntario),
+			Ultimo:   ultimo,
+			Validade: ultimo.AddDate(0, validadeMeses, 0),
+		}
+		switch {
+		case referencia.After(treinamento.Validade):
+			treinamento.Situacao = TreinamentoVencido
+			brigada.Vencidos++
+		case treinamento.Validade.Sub(referencia) <= avisoBrigada:
+			treinamento.Situacao = TreinamentoAVencer
+			brigada.AVencer++
+		default:
+			treinamento.Situacao = TreinamentoValido
+			brigada.Validos++
+		}
+		brigada.Voluntarios = append(brigada.Voluntarios, treinamento)
+	}
+	sort.SliceStable(brigada.Voluntarios, func(i, j int) bool {
+		return brigada.Voluntarios[i].Validade.Before(brigada.Voluntarios[j].Validade)
+	})
+	return brigada
+}
+
+// alertasBrigada aponta os treinamentos vencidos e os que vencem em breve
+func alertasBrigada(brigada *BrigadaLocalidade) []domain.Alerta {
+	var alertas []domain.Alerta
+	if brigada.Vencidos > 0 {
+		alertas = append(alertas, domain.Alerta{
+			Livro:      domain.LivroBrigada,
+			Mensagem:   fmt.Sprintf("%s da BRIGADA DE INCÊNDIO com treinamento vencido.", contarVoluntarios(brigada.Vencidos)),
+			Severidade: domain.SeveridadeAlta,
+		})
+	}
+	if brigada.AVencer > 0 {
+		alertas = append(alertas, domain.Alerta{
+			Livro: domain.LivroBrigada,
+			Mensagem: fmt.Sprintf("%s da BRIGADA DE INCÊNDIO com treinamento vencendo em até %d dias.",
+				contarVoluntarios(brigada.AVencer), int(avisoBrigada.Hours()/24)),
+			Severidade: domain.SeveridadeBaixa,
+		})
+	}
+	return alertas
+}
+
+func contarVoluntarios(n int) string {
+	if n == 1 {
+		return "1 voluntário"
+	}
+	return fmt.Sprintf("%d voluntários", n)
+}
@@ -0,0 +1,176 @@
+package usecase
+
+import (
+	"context"
+	"reflect"
+	"testing"
+	"time"
+
+	"report/internal/domain"
+)
+
+func dataUTC(ano int, mes time.Month, dia int) time.Time {
+	return time.Date(ano, mes, dia, 0, 0, 0, 0, time.UTC)
+}
+
+func TestAvaliarBrigada(t *testing.T) {
+	referencia := dataUTC(2025, 2, 28)
+
+	tests := []struct {
+		nome         string
+		treinamentos map[string]time.Time
+		validade     int
+		esperado     *BrigadaLocalidade
+	}{
+		{
+			nome: "válido, a vencer e vencido, ordenados pela validade",
+			treinamentos: map[string]time.Time{
+				"ANA LIMA":      dataUTC(2024, 6, 1),
+				"JOSE DA SILVA": dataUTC(2024, 3, 15),
+				"MARIA SOUZA":   dataUTC(2024, 1, 10),
+			},
+			validade: 12,
+			esperado: &BrigadaLocalidade{
+				ValidadeMeses: 12, Validos: 1, AVencer: 1, Vencidos: 1,
+				Voluntarios: []TreinamentoVoluntario{
+					{Nome: "M. S.", Ultimo: dataUTC(2024, 1, 10), Validade: dataUTC(2025, 1, 10), Situacao: TreinamentoVencido},
+					{Nome: "J. S.", Ultimo: dataUTC(2024, 3, 15), Validade: dataUTC(2025, 3, 15), Situacao: TreinamentoAVencer},
+					{Nome: "A. L.", Ultimo: dataUTC(2024, 6, 1), Validade: dataUTC(2025, 6, 1), Situacao: TreinamentoValido},
+				},
+			},
+		},
+		{
+			nome:         "validade mais curta",
+			treinamentos: map[string]time.Time{"ANA LIMA": dataUTC(2024, 6, 1)},
+			validade:     6,
+			esperado: &BrigadaLocalidade{
+				ValidadeMeses: 6, Vencidos: 1,
+				Voluntarios: []TreinamentoVoluntario{
+					{Nome: "A. L.", Ultimo: dataUTC(2024, 6, 1), Validade: dataUTC(2024, 12, 1), Situacao: TreinamentoVencido},
+				},
+			},
+		},
+		{
+			nome:     "sem treinamentos",
+			validade: 12,
+			esperado: &BrigadaLocalidade{ValidadeMeses: 12},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.nome, func(t *testing.T) {
+			brigada := avaliarBrigada(tt.treinamentos, tt.validade, referencia, domain.PrivacidadeIniciais)
+			if !reflect.DeepEqual(brigada, tt.esperado) {
+				t.Errorf("brigada = %+v, esperado %+v", brigada, tt.esperado)
+			}
+		})
+	}
+}
+
+func TestAlertasBrigada(t *testing.T) {
+	tests := []struct {
+		brigada     *BrigadaLocalidade
+		severidades []domain.Severidade
+	}{
+		{&BrigadaLocalidade{Validos: 3}, nil},
+		{&BrigadaLocalidade{Vencidos: 2}, []domain.Severidade{domain.SeveridadeAlta}},
+		{&BrigadaLocalidade{Vencidos: 1, AVencer: 1}, []domain.Severidade{domain.SeveridadeAlta, domain.SeveridadeBaixa}},
+	}
+	for _, tt := range tests {
+		var severidades []domain.Severidade
+		for _, alerta := range alertasBrigada(tt.brigada) {
+			if alerta.Livro != domain.LivroBrigada {
+				t.Errorf("alerta no livro %s", alerta.Livro)
+			}
+			severidades = append(severidades, alerta.Severidade)
+		}
+		if !reflect.DeepEqual(severidades, tt.severidades) {
+			t.Errorf("%+v: severidades = %v, esperado %v", *tt.brigada, severidades, tt.severidades)
+		}
+	}
+}
+
+func TestIncorporarRegistro(t *testing.T) {
+	privacidade := domain.PrivacidadeIniciais
+	jose := privacidade.Chave("JOSE DA SILVA")
+
+	registro := domain.RegistroBrigada{"VILA NOVA": {jose: {dataUTC(2025, 2, 10)}}}
+	anterior := domain.RegistroBrigada{
+		"VILA NOVA": {
+			// Nome completo gravado por uma versão anterior
+			"JOSE DA SILVA": {dataUTC(2024, 2, 10), dataUTC(2025, 2, 10)},
+			// Chave gravada com outra política
+			domain.PrivacidadePrimeiroNome.Chave("JOSE DA SILVA"): {dataUTC(2023, 2, 1)},
+			"ANA LIMA": {dataUTC(2024, 5, 1)},
+		},
+		"CENTRO": {"MARIA SOUZA": {dataUTC(2024, 8, 1)}},
+	}
+
+	incorporarRegistro(registro, anterior, privacidade)
+
+	esperado := domain.RegistroBrigada{
+		"VILA NOVA": {
+			jose:                          {dataUTC(2023, 2, 1), dataUTC(2024, 2, 10), dataUTC(2025, 2, 10)},
+			privacidade.Chave("ANA LIMA"): {dataUTC(2024, 5, 1)},
+		},
+		"CENTRO": {privacidade.Chave("MARIA SOUZA"): {dataUTC(2024, 8, 1)}},
+	}
+	if !reflect.DeepEqual(registro, esperado) {
+		t.Errorf("registro = %v, esperado %v", registro, esperado)
+	}
+}
+
+// TestSnapshotAlertasBrigada confere que Snapshot calcula os mesmos alertas
+// que GenerateReports grava no histórico, sem gravar o registro da brigada
+func TestSnapshotAlertasBrigada(t *testing.T) {
+	localidades := localidadeRepoMemoria{
+		"VILA NOVA": {
+			domain.LivroBrigada: {
+				TotalTrabalhos:              1,
+				Voluntarios:                 map[string]int{"JOSE DA SILVA": 1},
+				UltimoLancamentoVoluntarios: map[string]time.Time{"JOSE DA SILVA": dataUTC(2025, 2, 10)},
+			},
+		},
+	}
+	setores := setorRepoMemoria{"Setor 1": {Nome: "Setor 1", Responsavel: "setor1", Localidades: []string{"VILA NOVA"}}}
+	brigadaRepo := &brigadaRepoMemoria{registro: domain.RegistroBrigada{
+		"VILA NOVA": {domain.PrivacidadeIniciais.Chave("ANA LIMA"): {dataUTC(2023, 12, 1)}},
+	}}
+	historico := historicoRepoMemoria{}
+	g := NewReportGenerator(localidades, setores, livroRepoMemoria{}, &pdfMemoria{},
+		WithLogger(loggerTeste),
+		WithOutputDir(t.TempDir()),
+		WithPeriodo("2025-02"),
+		WithBrigada(brigadaRepo, 12),
+		WithHistorico(historico),
+	)
+
+	snapshot, err := g.Snapshot()
+	if err != nil {
+		t.Fatalf("Snapshot: %v", err)
+	}
+	var severidades []domain.Severidade
+	for _, alerta := range snapshot.Alertas["VILA NOVA"] {
+		if alerta.Livro == domain.LivroBrigada {
+			severidades = append(severidades, alerta.Severidade)
+		}
+	}
+	if !reflect.DeepEqual(severidades, []domain.Severidade{domain.SeveridadeAlta}) {
+		t.Errorf("alertas da brigada = %v, esperado um treinamento vencido", snapshot.Alertas["VILA NOVA"])
+	}
+	if brigadaRepo.gravacoes != 0 {
+		t.Error("Snapshot gravou o registro da brigada")
+	}
+	if g.Validacao() != nil {
+		t.Error("Snapshot alterou a validação do gerador")
+	}
+
+	if _, err := g.GenerateReports(context.Background()); err != nil {
+		t.Fatalf("GenerateReports: %v", err)
+	}
+	if !reflect.DeepEqual(historico["2025-02"].Alertas, snapshot.Alertas) {
+		t.Errorf("alertas gravados = %v, esperado %v", historico["2025-02"].Alertas, snapshot.Alertas)
+	}
+	if brigadaRepo.gravacoes != 1 || len(brigadaRepo.registro["VILA NOVA"]) != 2 {
+		t.Errorf("registro gravado %d vezes: %v", brigadaRepo.gravacoes, brigadaRepo.registro)
+	}
+}
@@ -0,0 +1,73 @@
+package usecase
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"os"
+	"path/filepath"
+)
+
+// WithGeracaoCompleta desativa o cache e gera novamente todos os documentos,
+// mesmo os que não mudaram desde a execução anterior
+func WithGeracaoCompleta() Option {
+	return func(g *ReportGenerator) {
+		g.semCache = true
+	}
+}
+
+// chaveDocumento identifica as entradas de um documento: os dados
+// normalizados, o modelo dos documentos e as regras de avaliação. Documentos
+// com a mesma chave têm o mesmo conteúdo e não precisam ser gerados de novo.
+func (g *ReportGenerator) chaveDocumento(tipo string, dados interface{}) string {
+	content, err := json.Marshal(struct {
+		Tipo    string
+		Modelo  string
+		Regras  string
+		Metrica string
+		Dados   interface{}
+	}{tipo, g.pdfService.Modelo(), versaoRegras, string(g.metrica), dados})
+	if err != nil {
+		return ""
+	}
+	hash := sha256.Sum256(content)
+	return hex.EncodeToString(hash[:])
+}
+
+// reaproveitar separa os documentos que podem ser mantidos: a chave é a
+// mesma registrada no manifesto anterior e o arquivo continua no diretório
+// de saída com o mesmo tamanho. Os arquivos mantidos não são regravados e
+// preservam a data de modificação.
+func (g *ReportGenerator) reaproveitar(documentos []documento) ([]documento, []string) {
+	if g.semCache || g.manifestService == nil {
+		return documentos, nil
+	}
+
+	manifest, err := g.manifestService.Load(filepath.Join(g.outputDir, ManifestFileName))
+	if err != nil {
+		if !os.IsNotExist(err) {
+			g.logger.Warn("manifesto anterior ignorado", "erro", err)
+		}
+		return documentos, nil
+	}
+	anteriores := make(map[string]ArquivoManifest, len(manifest.Documentos))
+	for _, arquivo := range manifest.Documentos {
+		anteriores[filepath.ToSlash(arquivo.Caminho)] = arquivo
+	}
+
+	var gerar []documento
+	var mantidos []string
+	for _, doc := range documentos {
+		anterior, exists := anteriores[filepath.ToSlash(relativo(g.outputDir, doc.Caminho))]
+		if doc.Chave != "" && exists && anterior.Chave == doc.Chave {
+			if info, err := os.Stat(doc.Caminho); err == nil && info.Size() == anterior.Tamanho {
+				mantidos = append(mantidos, doc.Caminho)
+				continue
+			}
+		}
+		gerar = append(gerar, doc)
+	}
+
+	g.logger.Info("cache de documentos", "mantidos", len(mantidos), "gerar", len(gerar))
+	return gerar, mantidos
+}
@@ -0,0 +1,116 @@
+package usecase
+
+import (
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestReaproveitar(t *testing.T) {
+	tests := []struct {
+		nome      string
+		opts      []Option
+		manifesto bool
+		// alterar modifica o diretório de saída depois do manifesto
+		alterar  func(t *testing.T, dir string)
+		chaves   map[string]string
+		mantidos []string
+	}{
+		{
+			nome:      "mesma chave e mesmo tamanho",
+			manifesto: true,
+			chaves:    map[string]string{"a.pdf": "1", "b.pdf": "2"},
+			mantidos:  []string{"a.pdf", "b.pdf"},
+		},
+		{
+			nome:      "chave alterada",
+			manifesto: true,
+			chaves:    map[string]string{"a.pdf": "1", "b.pdf": "outra"},
+			mantidos:  []string{"a.pdf"},
+		},
+		{
+			nome:      "documento sem chave é sempre gerado",
+			manifesto: true,
+			chaves:    map[string]string{"a.pdf": "", "b.pdf": "2"},
+			mantidos:  []string{"b.pdf"},
+		},
+		{
+			nome:      "arquivo alterado depois da geração",
+			manifesto: true,
+			alterar: func(t *testing.T, dir string) {
+				gravarArquivo(t, filepath.Join(dir, "a.pdf"), "conteúdo maior")
+			},
+			chaves:   map[string]string{"a.pdf": "1", "b.pdf": "2"},
+			mantidos: []string{"b.pdf"},
+		},
+		{
+			nome:      "geração completa",
+			opts:      []Option{WithGeracaoCompleta()},
+			manifesto: true,
+			chaves:    map[string]string{"a.pdf": "1", "b.pdf": "2"},
+		},
+		{
+			nome:   "sem manifesto anterior",
+			chaves: map[string]string{"a.pdf": "1", "b.pdf": "2"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.nome, func(t *testing.T) {
+			dir := t.TempDir()
+			service := &manifestMemoria{}
+			gravarArquivo(t, filepath.Join(dir, "a.pdf"), "a")
+			gravarArquivo(t, filepath.Join(dir, "b.pdf"), "b")
+			opts := append([]Option{WithOutputDir(dir), WithLogger(loggerTeste), WithManifest(service, ManifestInfo{})}, tt.opts...)
+			g := NewReportGenerator(nil, nil, nil, nil, opts...)
+			if tt.manifesto {
+				caminhos := []string{filepath.Join(dir, "a.pdf"), filepath.Join(dir, "b.pdf")}
+				chaves := map[string]string{caminhos[0]: "1", caminhos[1]: "2"}
+				if err := g.writeManifest(&Resultado{Gerados: caminhos}, nil, chaves); err != nil {
+					t.Fatal(err)
+				}
+			}
+			if tt.alterar != nil {
+				tt.alterar(t, dir)
+			}
+
+			var documentos []documento
+			for _, nome := range OrdenarChaves(tt.chaves) {
+				documentos = append(documentos, documento{Caminho: filepath.Join(dir, nome), Chave: tt.chaves[nome]})
+			}
+			gerar, mantidos := g.reaproveitar(documentos)
+
+			var nomes []string
+			for _, caminho := range mantidos {
+				nomes = append(nomes, filepath.Base(caminho))
+			}
+			if !reflect.DeepEqual(nomes, tt.mantidos) {
+				t.Errorf("mantidos = %v, esperado %v", nomes, tt.mantidos)
+			}
+			if len(gerar)+len(mantidos) != len(documentos) {
+				t.Errorf("%d a gerar e %d mantidos de %d documentos", len(gerar), len(mantidos), len(documentos))
+			}
+		})
+	}
+}
+
+func TestChaveDocumento(t *testing.T) {
+	pdf := &pdfMemoria{}
+	base := NewReportGenerator(nil, nil, nil, pdf).chaveDocumento("resumo", map[string]int{"a": 1})
+
+	tests := []struct {
+		nome  string
+		chave string
+		igual bool
+	}{
+		{"mesmos dados", NewReportGenerator(nil, nil, nil, pdf).chaveDocumento("resumo", map[string]int{"a": 1}), true},
+		{"dados diferentes", NewReportGenerator(nil, nil, nil, pdf).chaveDocumento("resumo", map[string]int{"a": 2}), false},
+		{"outro tipo", NewReportGenerator(nil, nil, nil, pdf).chaveDocumento("localidade", map[string]int{"a": 1}), false},
+		{"outra métrica", NewReportGenerator(nil, nil, nil, pdf, WithMetrica("horas")).chaveDocumento("resumo", map[string]int{"a": 1}), false},
+	}
+	for _, tt := range tests {
+		if (tt.chave == base) != tt.igual {
+			t.Errorf("%s: chave %s, base %s", tt.nome, tt.chave, base)
+		}
+	}
+}
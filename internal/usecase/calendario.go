@@ -0,0 +1,117 @@
+package usecase
+
+import (
+	"fmt"
+	"time"
+
+	"report/internal/domain"
+)
+
+// DiaCalendario é um dia do calendário de atividade do período
+type DiaCalendario struct {
+	Data        time.Time
+	Lancamentos int
+	Horas       float64
+	// Intensidade é o valor do dia em relação ao dia de maior atividade, de 0 a 1
+	Intensidade float64
+	FimDeSemana bool
+	Culto       bool
+}
+
+// Calendario é o mapa de calor da atividade de uma localidade ou setor no
+// período, dia a dia
+type Calendario struct {
+	// Metrica é horas ou lancamentos, conforme a métrica dos relatórios
+	Metrica domain.Metrica
+	Dias    []DiaCalendario
+	// Maximo é o valor do dia de maior atividade
+	Maximo float64
+}
+
+// Valor retorna o valor do dia na métrica do calendário
+func (c *Calendario) Valor(dia DiaCalendario) float64 {
+	if c.Metrica == domain.MetricaHoras {
+		return dia.Horas
+	}
+	return float64(dia.Lancamentos)
+}
+
+// WithAgendaCultos define os dias de culto destacados nos calendários
+func WithAgendaCultos(cultosRepo domain.AgendaCultosRepository) Option {
+	return func(g *ReportGenerator) {
+		g.cultosRepo = cultosRepo
+	}
+}
+
+// carregarCultos retorna os dias de culto de cada localidade e os da agenda
+// geral, usados pelas localidades sem agenda própria e pelos setores
+func (g *ReportGenerator) carregarCultos() (map[string][]time.Weekday, []time.Weekday, error) {
+	porLocalidade := make(map[string][]time.Weekday)
+	if g.cultosRepo == nil {
+		return porLocalidade, nil, nil
+	}
+	agendas, err := g.cultosRepo.GetAll()
+	if err != nil {
+		return nil, nil, fmt.Errorf("erro ao obter agenda de cultos: %v", err)
+	}
+	var gerais []time.Weekday
+	for _, agenda := range agendas {
+		if agenda.Localidade == "" {
+			gerais = append(gerais, agenda.Dias...)
+		} else {
+			porLocalidade[agenda.Localidade] = append(porLocalidade[agenda.Localidade], agenda.Dias...)
+		}
+	}
+	return porLocalidade, gerais, nil
+}
+
+// cultosLocalidade retorna os dias de culto da localidade ou, sem agenda
+// própria, os dias da agenda geral
+func cultosLocalidade(cultos map[string][]time.Weekday, gerais []time.Weekday, localidade string) []time.Weekday {
+	if dias, exists := cultos[localidade]; exists {
+		return dias
+	}
+	return gerais
+}
+
+// montarCalendario soma, dia a dia, os apontamentos de todos os livros
+// informados no período. A intensidade usa as horas quando essa é a métrica
+// dos relatórios e, nas demais métricas, os lançamentos.
+func (g *ReportGenerator) montarCalendario(livros []map[string]*domain.Summary, cultos []time.Weekday) *Calendario {
+	inicio, err := time.Parse(domain.LayoutPeriodo, g.periodo)
+	if err != nil {
+		return nil
+	}
+	calendario := &Calendario{Metrica: domain.MetricaLancamentos}
+	if g.metrica == domain.MetricaHoras {
+		calendario.Metrica = domain.MetricaHoras
+	}
+	culto := make(map[time.Weekday]bool, len(cultos))
+	for _, dia := range cultos {
+		culto[dia] = true
+	}
+
+	for data := inicio; data.Month() == inicio.Month(); data = data.AddDate(0, 0, 1) {
+		dia := DiaCalendario{
+			Data:        data,
+			FimDeSemana: data.Weekday() == time.Saturday || data.Weekday() == time.Sunday,
+			Culto:       culto[data.Weekday()],
+		}
+		chave := data.Format(domain.LayoutDia)
+		for _, livrosLocalidade := range livros {
+			for _, summary := range livrosLocalidade {
+				dia.Lancamentos += summary.LancamentosDia[chave]
+				dia.Horas += summary.HorasDia[chave]
+			}
+		}
+		calendario.Maximo = max(calendario.Maximo, calendario.Valor(dia))
+		calendario.Dias = append(calendario.Dias, dia)
+	}
+
+	if calendario.Maximo > 0 {
+		for i := range calendario.Dias {
+			calendario.Dias[i].Intensidade = calendario.Valor(calendario.Dias[i]) / calendario.Maximo
+		}
+	}
+	return calendario
+}
@@ -0,0 +1,149 @@
+package usecase
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"report/internal/domain"
+)
+
+// agendaRepoMemoria implementa AgendaCultosRepository em memória
+type agendaRepoMemoria []domain.AgendaCultos
+
+func (r agendaRepoMemoria) GetAll() ([]domain.AgendaCultos, error) {
+	return r, nil
+}
+
+var agendaTeste = agendaRepoMemoria{
+	{Dias: []time.Weekday{time.Sunday}},
+	{Dias: []time.Weekday{time.Wednesday}},
+	{Localidade: "VILA NOVA", Dias: []time.Weekday{time.Saturday}},
+}
+
+func TestCultosLocalidade(t *testing.T) {
+	g := NewReportGenerator(nil, nil, nil, nil, WithAgendaCultos(agendaTeste))
+	cultos, gerais, err := g.carregarCultos()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		localidade string
+		esperado   []time.Weekday
+	}{
+		{"VILA NOVA", []time.Weekday{time.Saturday}},
+		{"CENTRO", []time.Weekday{time.Sunday, time.Wednesday}},
+	}
+	for _, tt := range tests {
+		if dias := cultosLocalidade(cultos, gerais, tt.localidade); !reflect.DeepEqual(dias, tt.esperado) {
+			t.Errorf("cultos de %s = %v, esperado %v", tt.localidade, dias, tt.esperado)
+		}
+	}
+
+	cultos, gerais, err = NewReportGenerator(nil, nil, nil, nil).carregarCultos()
+	if err != nil || len(cultos) != 0 || gerais != nil {
+		t.Errorf("sem agenda: %v, %v, %v", cultos, gerais, err)
+	}
+}
+
+func TestMontarCalendario(t *testing.T) {
+	vila := map[string]*domain.Summary{
+		"LIMPEZA": {
+			LancamentosDia: map[string]int{"2025-02-01": 2, "2025-02-10": 1},
+			HorasDia:       map[string]float64{"2025-02-01": 2, "2025-02-10": 6},
+		},
+	}
+	centro := map[string]*domain.Summary{
+		"LIMPEZA": {
+			LancamentosDia: map[string]int{"2025-02-01": 2},
+			HorasDia:       map[string]float64{"2025-02-01": 1},
+		},
+		// Lançamentos de outro mês não entram no calendário
+		"JARDINAGEM": {LancamentosDia: map[string]int{"2025-03-01": 9}},
+	}
+
+	tests := []struct {
+		nome        string
+		metrica     domain.Metrica
+		livros      []map[string]*domain.Summary
+		maximo      float64
+		intensidade map[int]float64
+	}{
+		{
+			nome:        "lançamentos de duas localidades",
+			livros:      []map[string]*domain.Summary{vila, centro},
+			maximo:      4,
+			intensidade: map[int]float64{1: 1, 10: 0.25},
+		},
+		{
+			nome:        "horas",
+			metrica:     domain.MetricaHoras,
+			livros:      []map[string]*domain.Summary{vila, centro},
+			maximo:      6,
+			intensidade: map[int]float64{1: 0.5, 10: 1},
+		},
+		{
+			nome:   "sem apontamentos",
+			livros: []map[string]*domain.Summary{{}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.nome, func(t *testing.T) {
+			g := NewReportGenerator(nil, nil, nil, nil, WithPeriodo("2025-02"), WithMetrica(tt.metrica))
+			calendario := g.montarCalendario(tt.livros, []time.Weekday{time.Wednesday})
+			if len(calendario.Dias) != 28 {
+				t.Fatalf("%d dias, esperado 28", len(calendario.Dias))
+			}
+			if calendario.Maximo != tt.maximo {
+				t.Errorf("Maximo = %g, esperado %g", calendario.Maximo, tt.maximo)
+			}
+			for _, dia := range calendario.Dias {
+				if dia.Intensidade != tt.intensidade[dia.Data.Day()] {
+					t.Errorf("intensidade de %s = %g, esperado %g", dia.Data.Format(domain.LayoutDia), dia.Intensidade, tt.intensidade[dia.Data.Day()])
+				}
+				fimDeSemana := dia.Data.Weekday() == time.Saturday || dia.Data.Weekday() == time.Sunday
+				if dia.FimDeSemana != fimDeSemana || dia.Culto != (dia.Data.Weekday() == time.Wednesday) {
+					t.Errorf("%s: fim de semana %v, culto %v", dia.Data.Format(domain.LayoutDia), dia.FimDeSemana, dia.Culto)
+				}
+			}
+		})
+	}
+}
+
+// TestCalendarioSetor confere que o calendário do setor marca só os dias da
+// agenda geral, mesmo quando as localidades têm agendas próprias
+func TestCalendarioSetor(t *testing.T) {
+	pdf := &pdfMemoria{}
+	g := NewReportGenerator(nil, nil, nil, pdf, WithPeriodo("2025-02"), WithAgendaCultos(agendaTeste))
+	cultos, gerais, err := g.carregarCultos()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(cultos) == 0 {
+		t.Fatal("agenda sem localidades próprias")
+	}
+
+	snapshot := &domain.Snapshot{Localidades: map[string]map[string]*domain.Summary{"VILA NOVA": {}, "CENTRO": {}}}
+	grupos := map[string]*grupoSetor{
+		"setor1": {Nome: "Setor 1", Diretorio: t.TempDir(), Localidades: []string{"CENTRO", "VILA NOVA"}},
+	}
+	documentos := g.documentosResumosSetores(snapshot, grupos, nil, nil, nil, gerais)
+	if len(documentos) != 1 {
+		t.Fatalf("%d documentos, esperado 1", len(documentos))
+	}
+	if err := documentos[0].gerar(); err != nil {
+		t.Fatal(err)
+	}
+
+	calendario := pdf.documentos[documentos[0].Caminho].(*ReportData).Calendario
+	var dias []time.Weekday
+	for _, dia := range calendario.Dias[:7] {
+		if dia.Culto {
+			dias = append(dias, dia.Data.Weekday())
+		}
+	}
+	if esperado := []time.Weekday{time.Sunday, time.Wednesday}; !reflect.DeepEqual(dias, esperado) {
+		t.Errorf("dias de culto do setor = %v, esperado %v", dias, esperado)
+	}
+}
@@ -0,0 +1,72 @@
+package usecase
+
+import (
+	"regexp"
+	"strings"
+	"unicode"
+
+	"report/internal/domain"
+
+	"golang.org/x/text/unicode/norm"
+)
+
+// prefixoLivro é a numeração que a listagem de horas coloca antes do nome do
+// livro ("4 - ADMINISTRAÇÃO") e que o cadastro de livros não usa
+var prefixoLivro = regexp.MustCompile(`^\d+\s*-\s*`)
+
+// chaveLivro normaliza o nome de um livro para comparar a listagem com o
+// cadastro: sem numeração, sem acentos e em maiúsculas
+func chaveLivro(livro string) string {
+	livro = prefixoLivro.ReplaceAllString(strings.TrimSpace(livro), "")
+	var b strings.Builder
+	for _, r := range norm.NFD.String(livro) {
+		if !unicode.Is(unicode.Mn, r) {
+			b.WriteRune(r)
+		}
+	}
+	return strings.ToUpper(strings.Join(strings.Fields(b.String()), " "))
+}
+
+// nomesLivros associa a chave de cada livro ao nome usado na listagem
+func nomesLivros(localidades map[string]map[string]*domain.Summary) map[string]string {
+	nomes := make(map[string]string)
+	for _, livros := range localidades {
+		for livro := range livros {
+			nomes[chaveLivro(livro)] = livro
+		}
+	}
+	return nomes
+}
+
+// alinharCatalogo troca os nomes dos livros do cadastro pelos nomes usados na
+// listagem, para que os livros esperados e os apontados coincidam. Livros
+// sem apontamentos em nenhuma localidade mantêm o nome do cadastro.
+func alinharCatalogo(catalogo map[string]map[string]bool, nomes map[string]string) map[string]map[string]bool {
+	alinhado := make(map[string]map[string]bool, len(catalogo))
+	for localidade, livros := range catalogo {
+		alinhado[localidade] = make(map[string]bool, len(livros))
+		for livro := range livros {
+			if nome, exists := nomes[chaveLivro(livro)]; exists {
+				livro = nome
+			}
+			alinhado[localidade][livro] = true
+		}
+	}
+	return alinhado
+}
+
+// livrosSemCadastro retorna os livros com apontamentos que não estão
+// cadastrados para a localidade. Localidades ausentes do cadastro não são
+// verificadas.
+func livrosSemCadastro(dados map[string]*domain.Summary, esperados map[string]bool) []string {
+	if len(esperados) == 0 {
+		return nil
+	}
+	var livros []string
+	for _, livro := range OrdenarChaves(dados) {
+		if !esperados[livro] {
+			livros = append(livros, livro)
+		}
+	}
+	return livros
+}
@@ -0,0 +1,67 @@
+package usecase
+
+import (
+	"reflect"
+	"testing"
+
+	"report/internal/domain"
+)
+
+func TestChaveLivro(t *testing.T) {
+	tests := []struct {
+		livro    string
+		esperado string
+	}{
+		{"4 - ADMINISTRAÇÃO", "ADMINISTRACAO"},
+		{"Administração", "ADMINISTRACAO"},
+		{" 2-MANUTENÇÃO   PREVENTIVA ", "MANUTENCAO PREVENTIVA"},
+		{"12 - Jardinagem", "JARDINAGEM"},
+		{"LIMPEZA 2", "LIMPEZA 2"},
+	}
+	for _, tt := range tests {
+		if chave := chaveLivro(tt.livro); chave != tt.esperado {
+			t.Errorf("chaveLivro(%q) = %q, esperado %q", tt.livro, chave, tt.esperado)
+		}
+	}
+}
+
+func TestAlinharCatalogo(t *testing.T) {
+	localidades := map[string]map[string]*domain.Summary{
+		"VILA NOVA": {domain.LivroAdministracao: {}, domain.LivroManutencao: {}},
+		"CENTRO":    {"6 - LIMPEZA": {}},
+	}
+	catalogo := map[string]map[string]bool{
+		"VILA NOVA": {"Administração": true, "Limpeza": true},
+		"CENTRO":    {"Manutenção Preventiva": true, "Portaria": true},
+	}
+
+	alinhado := alinharCatalogo(catalogo, nomesLivros(localidades))
+
+	esperado := map[string]map[string]bool{
+		"VILA NOVA": {domain.LivroAdministracao: true, "6 - LIMPEZA": true},
+		"CENTRO":    {domain.LivroManutencao: true, "Portaria": true},
+	}
+	if !reflect.DeepEqual(alinhado, esperado) {
+		t.Errorf("alinharCatalogo = %v, esperado %v", alinhado, esperado)
+	}
+}
+
+func TestLivrosSemCadastro(t *testing.T) {
+	dados := map[string]*domain.Summary{"LIMPEZA": {}, "PORTARIA": {}, "JARDINAGEM": {}}
+	tests := []struct {
+		nome      string
+		esperados map[string]bool
+		livros    []string
+	}{
+		{"localidade fora do cadastro", nil, nil},
+		{"todos cadastrados", map[string]bool{"LIMPEZA": true, "PORTARIA": true, "JARDINAGEM": true}, nil},
+		{"livros não cadastrados", map[string]bool{"LIMPEZA": true}, []string{"JARDINAGEM", "PORTARIA"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.nome, func(t *testing.T) {
+			if livros := livrosSemCadastro(dados, tt.esperados); !reflect.DeepEqual(livros, tt.livros) {
+				t.Errorf("livrosSemCadastro = %v, esperado %v", livros, tt.livros)
+			}
+		})
+	}
+}
@@ -0,0 +1,318 @@
+package usecase
+
+import (
+	"fmt"
+	"path/filepath"
+	"sort"
+	"time"
+
+	"report/internal/domain"
+)
+
+// Nomes dos arquivos da classificação das localidades
+const (
+	classificacaoPDFNome  = "classificacao.pdf"
+	classificacaoXLSXNome = "classificacao.xlsx"
+)
+
+// PosicaoLocalidade é uma linha da classificação das localidades
+type PosicaoLocalidade struct {
+	Localidade string
+	Setor      string
+	// Cumprimento é o cumprimento geral das metas ou, sem metas, o
+	// percentual dos livros cadastrados que tiveram apontamentos
+	Cumprimento float64
+	ComMetas    bool
+	Horas       float64
+	Voluntarios int
+	Alertas     int
+	Posicao     int
+	// PosicaoAnterior é a posição no período anterior; zero quando a
+	// localidade não consta dele
+	PosicaoAnterior int
+}
+
+// Variacao retorna quantas posições a localidade subiu (positivo) ou desceu
+// desde o período anterior
+func (p PosicaoLocalidade) Variacao() int {
+	if p.PosicaoAnterior == 0 {
+		return 0
+	}
+	return p.PosicaoAnterior - p.Posicao
+}
+
+// ClassificacaoSetor é a classificação das localidades de um setor
+type ClassificacaoSetor struct {
+	Nome     string
+	Posicoes []PosicaoLocalidade
+}
+
+// CriterioClassificacao é o critério que ordena uma classificação
+type CriterioClassificacao string
+
+// Critérios disponíveis; a classificação geral e a dos setores usam o cumprimento
+const (
+	CriterioCumprimento CriterioClassificacao = "cumprimento"
+	CriterioHoras       CriterioClassificacao = "horas"
+	CriterioVoluntarios CriterioClassificacao = "voluntarios"
+	CriterioAlertas     CriterioClassificacao = "alertas"
+)
+
+// Titulo retorna o nome do critério usado nos documentos
+func (c CriterioClassificacao) Titulo() string {
+	switch c {
+	case CriterioHoras:
+		return "Horas"
+	case CriterioVoluntarios:
+		return "Voluntários"
+	case CriterioAlertas:
+		return "Alertas abertos"
+	default:
+		return "Cumprimento"
+	}
+}
+
+// ClassificacaoCriterio é a classificação geral ordenada por um só critério
+type ClassificacaoCriterio struct {
+	Criterio CriterioClassificacao
+	Posicoes []PosicaoLocalidade
+}
+
+// ClassificacaoReportData contém a classificação geral e a de cada setor
+type ClassificacaoReportData struct {
+	Titulo  string
+	Data    time.Time `json:"-"`
+	Periodo string
+	// PeriodoAnterior é o período comparado; vazio quando não há histórico
+	PeriodoAnterior string
+	Geral           []PosicaoLocalidade
+	Setores         []ClassificacaoSetor
+	// Criterios são as classificações gerais por horas, voluntários e
+	// alertas abertos, cada uma com a variação no mesmo critério
+	Criterios []ClassificacaoCriterio
+}
+
+// WithClassificacao habilita a classificação das localidades em PDF e XLSX
+func WithClassificacao(planilhaService PlanilhaService) Option {
+	return func(g *ReportGenerator) {
+		g.classificacao = true
+		g.planilhaService = planilhaService
+	}
+}
+
+// classificacaoReportData classifica as localidades do período e, com
+// histórico, as do período anterior, medidas pelas metas atuais
+func (g *ReportGenerator) classificacaoReportData(
+	snapshot, anterior *domain.Snapshot,
+	livros map[string]map[string]bool,
+	metas []domain.Meta,
+) (*ClassificacaoReportData, error) {
+	data := &ClassificacaoReportData{
+		Titulo:  "Classificação das Localidades",
+		Data:    time.Now(),
+		Periodo: g.periodo,
+	}
+
+	atuais, err := g.posicoesLocalidades(snapshot, livros, metas)
+	if err != nil {
+		return nil, err
+	}
+	var anteriores []PosicaoLocalidade
+	if anterior != nil {
+		data.PeriodoAnterior = anterior.Periodo
+		if anteriores, err = g.posicoesLocalidades(anterior, livros, metas); err != nil {
+			return nil, err
+		}
+	}
+
+	data.Geral = classificar(atuais, anteriores, CriterioCumprimento)
+	for _, criterio := range []CriterioClassificacao{CriterioHoras, CriterioVoluntarios, CriterioAlertas} {
+		data.Criterios = append(data.Criterios, ClassificacaoCriterio{
+			Criterio: criterio,
+			Posicoes: classificar(atuais, anteriores, criterio),
+		})
+	}
+	porSetor := make(map[string][]PosicaoLocalidade)
+	anterioresPorSetor := make(map[string][]PosicaoLocalidade)
+	for _, posicao := range atuais {
+		porSetor[posicao.Setor] = append(porSetor[posicao.Setor], posicao)
+	}
+	for _, posicao := range anteriores {
+		anterioresPorSetor[posicao.Setor] = append(anterioresPorSetor[posicao.Setor], posicao)
+	}
+	nomes := OrdenarChaves(porSetor)
+	sort.SliceStable(nomes, func(i, j int) bool {
+		return nomes[i] != nomeSemSetor && nomes[j] == nomeSemSetor
+	})
+	for _, setor := range nomes {
+		data.Setores = append(data.Setores, ClassificacaoSetor{
+			Nome:     setor,
+			Posicoes: classificar(porSetor[setor], anterioresPorSetor[setor], CriterioCumprimento),
+		})
+	}
+	return data, nil
+}
+
+// posicoesLocalidades mede cada localidade do snapshot nos critérios da classificação
+func (g *ReportGenerator) posicoesLocalidades(
+	snapshot *domain.Snapshot,
+	livros map[string]map[string]bool,
+	metas []domain.Meta,
+) ([]PosicaoLocalidade, error) {
+	posicoes := make([]PosicaoLocalidade, 0, len(snapshot.Localidades))
+	for _, localidade := range OrdenarChaves(snapshot.Localidades) {
+		dados := snapshot.Localidades[localidade]
+		setor, err := g.setorRepo.GetByLocalidade(localidade)
+		if err != nil {
+			return nil, fmt.Errorf("erro ao obter setor para localidade %s: %v", localidade, err)
+		}
+
+		posicao := PosicaoLocalidade{
+			Localidade: localidade,
+			Setor:      nomeSemSetor,
+			Alertas:    len(snapshot.Alertas[localidade]),
+		}
+		if setor != nil {
+			posicao.Setor = setor.Nome
+		}
+		voluntarios := make(map[string]bool)
+		for _, summary := range dados {
+			posicao.Horas += summary.Horas
+			for voluntario := range summary.Voluntarios {
+				voluntarios[voluntario] = true
+			}
+		}
+		posicao.Voluntarios = len(voluntarios)
+
+		if cumprimento := avaliarCumprimento(localidade, dados, metasLocalidade(metas, setor, localidade)); cumprimento != nil {
+			posicao.Cumprimento = cumprimento.Percentual
+			posicao.ComMetas = true
+		} else if esperados := livros[localidade]; len(esperados) > 0 {
+			atendidos := 0
+			for livro := range esperados {
+				if _, exists := dados[livro]; exists {
+					atendidos++
+				}
+			}
+			posicao.Cumprimento = float64(atendidos) / float64(len(esperados)) * 100
+		}
+		posicoes = append(posicoes, posicao)
+	}
+	return posicoes, nil
+}
+
+// classificar ordena as localidades pelo critério e associa a posição que
+// cada uma ocupava na classificação anterior pelo mesmo critério
+func classificar(posicoes, anteriores []PosicaoLocalidade, criterio CriterioClassificacao) []PosicaoLocalidade {
+	ordenar := func(lista []PosicaoLocalidade) []PosicaoLocalidade {
+		ordenada := append([]PosicaoLocalidade(nil), lista...)
+		sort.Slice(ordenada, func(i, j int) bool {
+			return antesNaClassificacao(ordenada[i], ordenada[j], criterio)
+		})
+		for i := range ordenada {
+			ordenada[i].Posicao = i + 1
+		}
+		return ordenada
+	}
+
+	posicaoAnterior := make(map[string]int, len(anteriores))
+	for _, anterior := range ordenar(anteriores) {
+		posicaoAnterior[anterior.Localidade] = anterior.Posicao
+	}
+	classificacao := ordenar(posicoes)
+	for i := range classificacao {
+		classificacao[i].PosicaoAnterior = posicaoAnterior[classificacao[i].Localidade]
+	}
+	return classificacao
+}
+
+// antesNaClassificacao compara duas localidades pelo critério e, no empate,
+// pelo cumprimento, alertas abertos, horas e voluntários
+func antesNaClassificacao(a, b PosicaoLocalidade, criterio CriterioClassificacao) bool {
+	switch {
+	case criterio == CriterioHoras && a.Horas != b.Horas:
+		return a.Horas > b.Horas
+	case criterio == CriterioVoluntarios && a.Voluntarios != b.Voluntarios:
+		return a.Voluntarios > b.Voluntarios
+	case criterio == CriterioAlertas && a.Alertas != b.Alertas:
+		return a.Alertas < b.Alertas
+	case a.Cumprimento != b.Cumprimento:
+		return a.Cumprimento > b.Cumprimento
+	case a.Alertas != b.Alertas:
+		return a.Alertas < b.Alertas
+	case a.Horas != b.Horas:
+		return a.Horas > b.Horas
+	case a.Voluntarios != b.Voluntarios:
+		return a.Voluntarios > b.Voluntarios
+	}
+	return a.Localidade < b.Localidade
+}
+
+// documentosClassificacao monta a classificação em PDF e em planilha
+func (g *ReportGenerator) documentosClassificacao(data *ClassificacaoReportData) []documento {
+	pdfPath := filepath.Join(g.outputDir, classificacaoPDFNome)
+	xlsxPath := filepath.Join(g.outputDir, classificacaoXLSXNome)
+	abas := abasClassificacao(data)
+	return []documento{
+		{
+			Nome:    "classificação das localidades",
+			Caminho: pdfPath,
+			Chave:   g.chaveDocumento("classificacao", data),
+			gerar: func() error {
+				return g.pdfService.GenerateClassificacaoReport(data, pdfPath)
+			},
+		},
+		{
+			Nome:    "planilha da classificação",
+			Caminho: xlsxPath,
+			Chave:   g.chaveDocumento("planilha", abas),
+			gerar: func() error {
+				return g.planilhaService.GenerateXLSX(abas, xlsxPath)
+			},
+		},
+	}
+}
+
+// abasClassificacao monta as abas Geral e Setores da planilha da
+// classificação e uma aba para cada critério
+func abasClassificacao(data *ClassificacaoReportData) []Aba {
+	cabecalho := []string{"Posição", "Variação", "Localidade", "Setor", "Cumprimento (%)", "Com metas", "Horas", "Voluntários", "Alertas"}
+	linha := func(posicao PosicaoLocalidade) []interface{} {
+		var variacao interface{} = ""
+		switch {
+		case data.PeriodoAnterior == "":
+		case posicao.PosicaoAnterior == 0:
+			variacao = "nova"
+		default:
+			variacao = posicao.Variacao()
+		}
+		comMetas := "não"
+		if posicao.ComMetas {
+			comMetas = "sim"
+		}
+		return []interface{}{
+			posicao.Posicao, variacao, posicao.Localidade, posicao.Setor,
+			posicao.Cumprimento, comMetas, posicao.Horas, posicao.Voluntarios, posicao.Alertas,
+		}
+	}
+
+	geral := Aba{Nome: "Geral", Cabecalho: cabecalho}
+	for _, posicao := range data.Geral {
+		geral.Linhas = append(geral.Linhas, linha(posicao))
+	}
+	setores := Aba{Nome: "Setores", Cabecalho: cabecalho}
+	for _, setor := range data.Setores {
+		for _, posicao := range setor.Posicoes {
+			setores.Linhas = append(setores.Linhas, linha(posicao))
+		}
+	}
+	abas := []Aba{geral, setores}
+	for _, criterio := range data.Criterios {
+		aba := Aba{Nome: criterio.Criterio.Titulo(), Cabecalho: cabecalho}
+		for _, posicao := range criterio.Posicoes {
+			aba.Linhas = append(aba.Linhas, linha(posicao))
+		}
+		abas = append(abas, aba)
+	}
+	return abas
+}
@@ -0,0 +1,163 @@
+package usecase
+
+import (
+	"reflect"
+	"testing"
+
+	"report/internal/domain"
+)
+
+func TestAntesNaClassificacao(t *testing.T) {
+	tests := []struct {
+		nome     string
+		a, b     PosicaoLocalidade
+		criterio CriterioClassificacao
+		antes    bool
+	}{
+		{"maior cumprimento", PosicaoLocalidade{Localidade: "B", Cumprimento: 90}, PosicaoLocalidade{Localidade: "A", Cumprimento: 80}, CriterioCumprimento, true},
+		{"empate no cumprimento, menos alertas", PosicaoLocalidade{Localidade: "B", Cumprimento: 80, Alertas: 1}, PosicaoLocalidade{Localidade: "A", Cumprimento: 80, Alertas: 2}, CriterioCumprimento, true},
+		{"empate, mais horas", PosicaoLocalidade{Localidade: "B", Horas: 10}, PosicaoLocalidade{Localidade: "A", Horas: 5}, CriterioCumprimento, true},
+		{"empate, mais voluntários", PosicaoLocalidade{Localidade: "B", Voluntarios: 3}, PosicaoLocalidade{Localidade: "A", Voluntarios: 2}, CriterioCumprimento, true},
+		{"empate total, ordem alfabética", PosicaoLocalidade{Localidade: "B"}, PosicaoLocalidade{Localidade: "A"}, CriterioCumprimento, false},
+		{"horas antes do cumprimento", PosicaoLocalidade{Localidade: "B", Horas: 10, Cumprimento: 10}, PosicaoLocalidade{Localidade: "A", Horas: 5, Cumprimento: 90}, CriterioHoras, true},
+		{"voluntários", PosicaoLocalidade{Localidade: "B", Voluntarios: 1}, PosicaoLocalidade{Localidade: "A", Voluntarios: 4}, CriterioVoluntarios, false},
+		{"menos alertas abertos", PosicaoLocalidade{Localidade: "B", Alertas: 0, Cumprimento: 10}, PosicaoLocalidade{Localidade: "A", Alertas: 3, Cumprimento: 90}, CriterioAlertas, true},
+		{"empate no critério usa o cumprimento", PosicaoLocalidade{Localidade: "B", Horas: 5, Cumprimento: 90}, PosicaoLocalidade{Localidade: "A", Horas: 5, Cumprimento: 80}, CriterioHoras, true},
+	}
+	for _, tt := range tests {
+		if antes := antesNaClassificacao(tt.a, tt.b, tt.criterio); antes != tt.antes {
+			t.Errorf("%s: antesNaClassificacao = %v, esperado %v", tt.nome, antes, tt.antes)
+		}
+	}
+}
+
+func TestClassificar(t *testing.T) {
+	atuais := []PosicaoLocalidade{
+		{Localidade: "VILA NOVA", Cumprimento: 50, Horas: 30},
+		{Localidade: "CENTRO", Cumprimento: 90, Horas: 10},
+		{Localidade: "BARRAGEM", Cumprimento: 70, Horas: 20},
+		{Localidade: "NOVA", Cumprimento: 60, Horas: 40},
+	}
+	anteriores := []PosicaoLocalidade{
+		{Localidade: "VILA NOVA", Cumprimento: 95, Horas: 5},
+		{Localidade: "CENTRO", Cumprimento: 80, Horas: 50},
+		{Localidade: "BARRAGEM", Cumprimento: 70, Horas: 20},
+	}
+
+	type linha struct {
+		Localidade string
+		Posicao    int
+		Variacao   int
+		Anterior   int
+	}
+	tests := []struct {
+		nome       string
+		anteriores []PosicaoLocalidade
+		criterio   CriterioClassificacao
+		esperado   []linha
+	}{
+		{
+			nome:       "cumprimento com subidas, quedas e localidade nova",
+			anteriores: anteriores,
+			criterio:   CriterioCumprimento,
+			esperado: []linha{
+				{"CENTRO", 1, 1, 2},
+				{"BARRAGEM", 2, 1, 3},
+				{"NOVA", 3, 0, 0},
+				{"VILA NOVA", 4, -3, 1},
+			},
+		},
+		{
+			nome:       "horas comparadas às horas do período anterior",
+			anteriores: anteriores,
+			criterio:   CriterioHoras,
+			esperado: []linha{
+				{"NOVA", 1, 0, 0},
+				{"VILA NOVA", 2, 1, 3},
+				{"BARRAGEM", 3, -1, 2},
+				{"CENTRO", 4, -3, 1},
+			},
+		},
+		{
+			nome:     "sem período anterior",
+			criterio: CriterioCumprimento,
+			esperado: []linha{
+				{"CENTRO", 1, 0, 0},
+				{"BARRAGEM", 2, 0, 0},
+				{"NOVA", 3, 0, 0},
+				{"VILA NOVA", 4, 0, 0},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.nome, func(t *testing.T) {
+			var linhas []linha
+			for _, posicao := range classificar(atuais, tt.anteriores, tt.criterio) {
+				linhas = append(linhas, linha{posicao.Localidade, posicao.Posicao, posicao.Variacao(), posicao.PosicaoAnterior})
+			}
+			if !reflect.DeepEqual(linhas, tt.esperado) {
+				t.Errorf("classificação = %v, esperado %v", linhas, tt.esperado)
+			}
+		})
+	}
+	if atuais[0].Posicao != 0 {
+		t.Error("classificar alterou as posições recebidas")
+	}
+}
+
+func TestClassificacaoReportData(t *testing.T) {
+	setores := setorRepoMemoria{"Setor 1": {Nome: "Setor 1", Localidades: []string{"VILA NOVA", "CENTRO"}}}
+	livros := map[string]map[string]bool{
+		"VILA NOVA": {"LIMPEZA": true, "JARDINAGEM": true},
+		"CENTRO":    {"LIMPEZA": true},
+		"AVULSA":    {"LIMPEZA": true},
+	}
+	atual := &domain.Snapshot{
+		Periodo: "2025-02",
+		Localidades: map[string]map[string]*domain.Summary{
+			"VILA NOVA": {"LIMPEZA": {Horas: 4, Voluntarios: map[string]int{"ANA": 1, "JOSE": 1}}},
+			"CENTRO":    {"LIMPEZA": {Horas: 2, Voluntarios: map[string]int{"ANA": 1}}},
+			"AVULSA":    {},
+		},
+		Alertas: map[string][]domain.Alerta{"CENTRO": {{}}},
+	}
+	anterior := &domain.Snapshot{
+		Periodo: "2025-01",
+		Localidades: map[string]map[string]*domain.Summary{
+			"VILA NOVA": {},
+			"CENTRO":    {"LIMPEZA": {Horas: 1}},
+		},
+	}
+
+	g := NewReportGenerator(nil, setores, nil, nil, WithPeriodo("2025-02"))
+	data, err := g.classificacaoReportData(atual, anterior, livros, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if data.PeriodoAnterior != "2025-01" {
+		t.Errorf("PeriodoAnterior = %q", data.PeriodoAnterior)
+	}
+
+	var geral []string
+	for _, posicao := range data.Geral {
+		geral = append(geral, posicao.Localidade)
+	}
+	// CENTRO cumpre todo o catálogo; VILA NOVA, metade; AVULSA, nada
+	if esperado := []string{"CENTRO", "VILA NOVA", "AVULSA"}; !reflect.DeepEqual(geral, esperado) {
+		t.Errorf("classificação geral = %v, esperado %v", geral, esperado)
+	}
+	if v := data.Geral[1]; v.Cumprimento != 50 || v.Voluntarios != 2 || v.Horas != 4 || v.PosicaoAnterior != 2 {
+		t.Errorf("VILA NOVA = %+v", v)
+	}
+
+	var nomes []string
+	for _, setor := range data.Setores {
+		nomes = append(nomes, setor.Nome)
+	}
+	if esperado := []string{"Setor 1", nomeSemSetor}; !reflect.DeepEqual(nomes, esperado) {
+		t.Errorf("setores = %v, esperado %v", nomes, esperado)
+	}
+	if len(data.Criterios) != 3 || data.Criterios[2].Posicoes[2].Localidade != "CENTRO" {
+		t.Errorf("critérios = %+v", data.Criterios)
+	}
+}
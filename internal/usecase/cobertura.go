@@ -0,0 +1,160 @@
+package usecase
+
+import (
+	"sort"
+
+	"report/internal/domain"
+)
+
+// limiteConcentracao é a participação do principal voluntário de um livro a
+// partir da qual o livro depende de uma única pessoa
+const limiteConcentracao = 60.0
+
+// maxPrincipais é o número de principais voluntários listados por livro
+const maxPrincipais = 3
+
+// ParticipacaoVoluntario é a contribuição de um voluntário em um livro
+type ParticipacaoVoluntario struct {
+	Nome        string
+	Lancamentos int
+	Horas       float64
+	// Participacao é o percentual das horas do livro, ou dos lançamentos
+	// quando a listagem não tem horas
+	Participacao float64
+}
+
+// CoberturaLivro resume quantos voluntários atendem um livro e o quanto o
+// trabalho está concentrado nos principais
+type CoberturaLivro struct {
+	Livro       string
+	Voluntarios int
+	Principais  []ParticipacaoVoluntario
+	// Concentrado indica que o principal voluntário atinge o limite de concentração
+	Concentrado bool
+}
+
+// VoluntarioEscala é uma linha da escala de voluntários da localidade
+type VoluntarioEscala struct {
+	Nome        string
+	Livros      []string
+	Lancamentos int
+	Horas       float64
+	// OutrasLocalidades são as demais localidades em que o voluntário tem apontamentos
+	OutrasLocalidades []string
+}
+
+// CoberturaLocalidade reúne a análise de cobertura e a escala de voluntários
+// de uma localidade, com os nomes já apresentados conforme a privacidade
+type CoberturaLocalidade struct {
+	Voluntarios int
+	// MultiLocalidade é o número de voluntários com apontamentos em outras localidades
+	MultiLocalidade int
+	Livros          []CoberturaLivro
+	Escala          []VoluntarioEscala
+}
+
+// WithPrivacidade define como os nomes dos voluntários aparecem na escala
+func WithPrivacidade(privacidade domain.Privacidade) Option {
+	return func(g *ReportGenerator) {
+		if privacidade != "" {
+			g.privacidade = privacidade
+		}
+	}
+}
+
+// localidadesPorVoluntario associa cada voluntário às localidades em que tem
+// apontamentos. Os voluntários são identificados pelo nome, pois a listagem
+// não é lida com o CPF; homônimos são tratados como a mesma pessoa.
+func localidadesPorVoluntario(localidades map[string]map[string]*domain.Summary) map[string][]string {
+	porVoluntario := make(map[string][]string)
+	for _, localidade := range OrdenarChaves(localidades) {
+		vistos := make(map[string]bool)
+		for _, summary := range localidades[localidade] {
+			for voluntario := range summary.Voluntarios {
+				if !vistos[voluntario] {
+					vistos[voluntario] = true
+					porVoluntario[voluntario] = append(porVoluntario[voluntario], localidade)
+				}
+			}
+		}
+	}
+	return porVoluntario
+}
+
+// avaliarCobertura calcula os voluntários distintos de cada livro, a
+// participação dos principais e a escala da localidade
+func avaliarCobertura(
+	localidade string,
+	dados map[string]*domain.Summary,
+	porVoluntario map[string][]string,
+	privacidade domain.Privacidade,
+) *CoberturaLocalidade {
+	cobertura := &CoberturaLocalidade{}
+	escala := make(map[string]*VoluntarioEscala)
+
+	for _, livro := range OrdenarChaves(dados) {
+		summary := dados[livro]
+		if len(summary.Voluntarios) == 0 {
+			continue
+		}
+
+		participacoes := make([]ParticipacaoVoluntario, 0, len(summary.Voluntarios))
+		for voluntario, lancamentos := range summary.Voluntarios {
+			horas := summary.HorasVoluntarios[voluntario]
+			participacao := ParticipacaoVoluntario{Nome: voluntario, Lancamentos: lancamentos, Horas: horas}
+			if summary.Horas > 0 {
+				participacao.Participacao = horas / summary.Horas * 100
+			} else if summary.TotalTrabalhos > 0 {
+				participacao.Participacao = float64(lancamentos) / float64(summary.TotalTrabalhos) * 100
+			}
+			participacoes = append(participacoes, participacao)
+
+			item, exists := escala[voluntario]
+			if !exists {
+				item = &VoluntarioEscala{Nome: voluntario}
+				escala[voluntario] = item
+			}
+			item.Livros = append(item.Livros, livro)
+			item.Lancamentos += lancamentos
+			item.Horas += horas
+		}
+		sort.Slice(participacoes, func(i, j int) bool {
+			if participacoes[i].Participacao != participacoes[j].Participacao {
+				return participacoes[i].Participacao > participacoes[j].Participacao
+			}
+			return participacoes[i].Nome < participacoes[j].Nome
+		})
+
+		principais := participacoes[:min(len(participacoes), maxPrincipais)]
+		for i := range principais {
+			principais[i].Nome = privacidade.Nome(principais[i].Nome)
+		}
+		cobertura.Livros = append(cobertura.Livros, CoberturaLivro{
+			Livro:       livro,
+			Voluntarios: len(summary.Voluntarios),
+			Principais:  principais,
+			Concentrado: principais[0].Participacao >= limiteConcentracao,
+		})
+	}
+
+	nomes := OrdenarChaves(escala)
+	sort.SliceStable(nomes, func(i, j int) bool {
+		return escala[nomes[i]].Horas > escala[nomes[j]].Horas
+	})
+	for _, nome := range nomes {
+		item := escala[nome]
+		for _, outra := range porVoluntario[nome] {
+			if outra != localidade {
+				item.OutrasLocalidades = append(item.OutrasLocalidades, outra)
+			}
+		}
+		if len(item.OutrasLocalidades) > 0 {
+			cobertura.MultiLocalidade++
+		}
+		item.Nome = privacidade.Nome(nome)
+		cobertura.Escala = append(cobertura.Escala, *item)
+	}
+	cobertura.Voluntarios = len(cobertura.Escala)
+
+	return cobertura
+}
@@ -0,0 +1,370 @@
+package usecase
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"sort"
+
+	"report/internal/domain"
+)
+
+// ErrPeriodoNaoEncontrado indica que não há histórico para o período consultado
+var ErrPeriodoNaoEncontrado = errors.New("período não encontrado")
+
+// ErrPeriodoInvalido indica um período fora do formato AAAA-MM
+var ErrPeriodoInvalido = errors.New("período inválido, use AAAA-MM")
+
+// Filtro restringe as consultas por período, setor, localidade e livro.
+// Campos vazios não filtram; sem período, é usado o mais recente do histórico.
+type Filtro struct {
+	Periodo    string
+	Setor      string
+	Localidade string
+	Livro      string
+}
+
+// SetorInfo descreve um setor e suas localidades
+type SetorInfo struct {
+	Nome          string   `json:"nome"`
+	Administracao string   `json:"administracao"`
+	Responsavel   string   `json:"responsavel"`
+	Localidades   []string `json:"localidades"`
+}
+
+// LocalidadeInfo descreve uma localidade e os livros cadastrados para ela
+type LocalidadeInfo struct {
+	Nome   string   `json:"nome"`
+	Setor  string   `json:"setor,omitempty"`
+	Livros []string `json:"livros"`
+}
+
+// LivroInfo descreve um livro e as localidades em que está cadastrado
+type LivroInfo struct {
+	Nome        string   `json:"nome"`
+	Localidades []string `json:"localidades"`
+}
+
+// ResumoLivro contém os totais de um livro em uma localidade
+type ResumoLivro struct {
+	Livro          string  `json:"livro"`
+	TotalTrabalhos int     `json:"total_trabalhos"`
+	Horas          float64 `json:"horas"`
+	Voluntarios    int     `json:"voluntarios"`
+}
+
+// ResumoLocalidade contém os totais de uma localidade no período
+type ResumoLocalidade struct {
+	Localidade string        `json:"localidade"`
+	Setor      string        `json:"setor,omitempty"`
+	Livros     []ResumoLivro `json:"livros"`
+}
+
+// AlertasLocalidade contém os pontos de atenção de uma localidade no período
+type AlertasLocalidade struct {
+	Localidade string          `json:"localidade"`
+	Setor      string          `json:"setor,omitempty"`
+	Alertas    []domain.Alerta `json:"alertas"`
+}
+
+// VoluntariosLocalidade agrega a participação de voluntários em uma localidade
+type VoluntariosLocalidade struct {
+	Localidade  string        `json:"localidade"`
+	Setor       string        `json:"setor,omitempty"`
+	Voluntarios int           `json:"voluntarios"`
+	Livros      []ResumoLivro `json:"livros"`
+}
+
+// AgregadoVoluntarios resume a participação de voluntários no período
+type AgregadoVoluntarios struct {
+	Periodo                string                  `json:"periodo"`
+	Voluntarios            int                     `json:"voluntarios"`
+	EmMultiplasLocalidades int                     `json:"em_multiplas_localidades"`
+	Localidades            []VoluntariosLocalidade `json:"localidades"`
+}
+
+// Consulta define o caso de uso de consulta aos dados dos relatórios
+type Consulta struct {
+	setorRepo     domain.SetorRepository
+	livroRepo     domain.LivroRepository
+	historicoRepo domain.HistoricoRepository
+}
+
+// NewConsulta cria uma nova instância de Consulta
+func NewConsulta(
+	setorRepo domain.SetorRepository,
+	livroRepo domain.LivroRepository,
+	historicoRepo domain.HistoricoRepository,
+) *Consulta {
+	return &Consulta{
+		setorRepo:     setorRepo,
+		livroRepo:     livroRepo,
+		historicoRepo: historicoRepo,
+	}
+}
+
+// Periodos retorna os períodos disponíveis no histórico
+func (c *Consulta) Periodos() ([]string, error) {
+	return c.historicoRepo.List()
+}
+
+// Setores retorna os setores cadastrados
+func (c *Consulta) Setores() ([]SetorInfo, error) {
+	setores, err := c.setorRepo.GetAll()
+	if err != nil {
+		return nil, fmt.Errorf("erro ao obter setores: %v", err)
+	}
+
+	porNome := make(map[string]*domain.Setor)
+	for _, setor := range setores {
+		porNome[setor.Nome] = setor
+	}
+
+	lista := []SetorInfo{}
+	for _, nome := range OrdenarChaves(porNome) {
+		setor := porNome[nome]
+		localidades := append([]string(nil), setor.Localidades...)
+		sort.Strings(localidades)
+		lista = append(lista, SetorInfo{
+			Nome:          setor.Nome,
+			Administracao: setor.Administracao,
+			Responsavel:   setor.Responsavel,
+			Localidades:   localidades,
+		})
+	}
+	return lista, nil
+}
+
+// Localidades retorna as localidades cadastradas, filtradas por setor e livro
+func (c *Consulta) Localidades(filtro Filtro) ([]LocalidadeInfo, error) {
+	setores, err := c.setorRepo.GetAll()
+	if err != nil {
+		return nil, fmt.Errorf("erro ao obter setores: %v", err)
+	}
+	livros, err := c.livroRepo.GetAll()
+	if err != nil {
+		return nil, fmt.Errorf("erro ao obter livros: %v", err)
+	}
+
+	nomes := make(map[string]bool)
+	for localidade := range setores {
+		nomes[localidade] = true
+	}
+	for localidade := range livros {
+		nomes[localidade] = true
+	}
+
+	lista := []LocalidadeInfo{}
+	for _, localidade := range OrdenarChaves(nomes) {
+		info := LocalidadeInfo{Nome: localidade, Livros: OrdenarChaves(livros[localidade])}
+		if setor := setores[localidade]; setor != nil {
+			info.Setor = setor.Nome
+		}
+		if !filtro.aceitaSetor(info.Setor) || !filtro.aceitaLocalidade(localidade) {
+			continue
+		}
+		if filtro.Livro != "" && !livros[localidade][filtro.Livro] {
+			continue
+		}
+		lista = append(lista, info)
+	}
+	return lista, nil
+}
+
+// Livros retorna os livros cadastrados e suas localidades, filtrados por setor e localidade
+func (c *Consulta) Livros(filtro Filtro) ([]LivroInfo, error) {
+	localidades, err := c.Localidades(Filtro{Setor: filtro.Setor, Localidade: filtro.Localidade})
+	if err != nil {
+		return nil, err
+	}
+
+	porLivro := make(map[string][]string)
+	for _, localidade := range localidades {
+		for _, livro := range localidade.Livros {
+			porLivro[livro] = append(porLivro[livro], localidade.Nome)
+		}
+	}
+
+	lista := []LivroInfo{}
+	for _, livro := range OrdenarChaves(porLivro) {
+		if filtro.Livro != "" && livro != filtro.Livro {
+			continue
+		}
+		lista = append(lista, LivroInfo{Nome: livro, Localidades: porLivro[livro]})
+	}
+	return lista, nil
+}
+
+// Resumos retorna os totais por localidade e livro no período
+func (c *Consulta) Resumos(filtro Filtro) ([]ResumoLocalidade, error) {
+	snapshot, setores, err := c.carregar(filtro)
+	if err != nil {
+		return nil, err
+	}
+
+	lista := []ResumoLocalidade{}
+	for _, localidade := range OrdenarChaves(snapshot.Localidades) {
+		setor := nomeSetor(setores[localidade])
+		if !filtro.aceitaSetor(setor) || !filtro.aceitaLocalidade(localidade) {
+			continue
+		}
+		livros := resumirLivros(snapshot.Localidades[localidade], filtro)
+		if filtro.Livro != "" && len(livros) == 0 {
+			continue
+		}
+		lista = append(lista, ResumoLocalidade{Localidade: localidade, Setor: setor, Livros: livros})
+	}
+	return lista, nil
+}
+
+// Alertas retorna os pontos de atenção por localidade no período
+func (c *Consulta) Alertas(filtro Filtro) ([]AlertasLocalidade, error) {
+	snapshot, setores, err := c.carregar(filtro)
+	if err != nil {
+		return nil, err
+	}
+
+	lista := []AlertasLocalidade{}
+	for _, localidade := range OrdenarChaves(snapshot.Alertas) {
+		setor := nomeSetor(setores[localidade])
+		if !filtro.aceitaSetor(setor) || !filtro.aceitaLocalidade(localidade) {
+			continue
+		}
+
+		var alertas []domain.Alerta
+		for _, alerta := range snapshot.Alertas[localidade] {
+			if filtro.Livro == "" || alerta.Livro == filtro.Livro {
+				alertas = append(alertas, alerta)
+			}
+		}
+		if len(alertas) > 0 {
+			lista = append(lista, AlertasLocalidade{Localidade: localidade, Setor: setor, Alertas: alertas})
+		}
+	}
+	return lista, nil
+}
+
+// Voluntarios retorna a participação agregada de voluntários no período, sem identificá-los
+func (c *Consulta) Voluntarios(filtro Filtro) (*AgregadoVoluntarios, error) {
+	snapshot, setores, err := c.carregar(filtro)
+	if err != nil {
+		return nil, err
+	}
+
+	agregado := &AgregadoVoluntarios{Periodo: snapshot.Periodo}
+	localidadesPorVoluntario := make(map[string]map[string]bool)
+
+	for _, localidade := range OrdenarChaves(snapshot.Localidades) {
+		setor := nomeSetor(setores[localidade])
+		if !filtro.aceitaSetor(setor) || !filtro.aceitaLocalidade(localidade) {
+			continue
+		}
+
+		distintos := make(map[string]bool)
+		for livro, summary := range snapshot.Localidades[localidade] {
+			if filtro.Livro != "" && livro != filtro.Livro {
+				continue
+			}
+			for voluntario := range summary.Voluntarios {
+				distintos[voluntario] = true
+				if localidadesPorVoluntario[voluntario] == nil {
+					localidadesPorVoluntario[voluntario] = make(map[string]bool)
+				}
+				localidadesPorVoluntario[voluntario][localidade] = true
+			}
+		}
+
+		livros := resumirLivros(snapshot.Localidades[localidade], filtro)
+		if len(livros) == 0 {
+			continue
+		}
+		agregado.Localidades = append(agregado.Localidades, VoluntariosLocalidade{
+			Localidade:  localidade,
+			Setor:       setor,
+			Voluntarios: len(distintos),
+			Livros:      livros,
+		})
+	}
+
+	agregado.Voluntarios = len(localidadesPorVoluntario)
+	for _, localidades := range localidadesPorVoluntario {
+		if len(localidades) > 1 {
+			agregado.EmMultiplasLocalidades++
+		}
+	}
+	return agregado, nil
+}
+
+// carregar obtém os dados do período filtrado e o setor de cada localidade
+func (c *Consulta) carregar(filtro Filtro) (*domain.Snapshot, map[string]*domain.Setor, error) {
+	periodo := filtro.Periodo
+	if periodo == "" {
+		periodos, err := c.historicoRepo.List()
+		if err != nil {
+			return nil, nil, fmt.Errorf("erro ao obter períodos: %v", err)
+		}
+		if len(periodos) == 0 {
+			return nil, nil, ErrPeriodoNaoEncontrado
+		}
+		periodo = periodos[len(periodos)-1]
+	}
+
+	snapshot, err := obterHistorico(c.historicoRepo, periodo)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	setores, err := c.setorRepo.GetAll()
+	if err != nil {
+		return nil, nil, fmt.Errorf("erro ao obter setores: %v", err)
+	}
+	return snapshot, setores, nil
+}
+
+// obterHistorico lê o histórico do período, distinguindo o período inválido
+// e o período sem histórico das falhas de leitura
+func obterHistorico(historicoRepo domain.HistoricoRepository, periodo string) (*domain.Snapshot, error) {
+	if domain.ValidarPeriodo(periodo) != nil {
+		return nil, fmt.Errorf("%w: %s", ErrPeriodoInvalido, periodo)
+	}
+	snapshot, err := historicoRepo.Get(periodo)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, fmt.Errorf("%w: %s", ErrPeriodoNaoEncontrado, periodo)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("erro ao obter histórico de %s: %v", periodo, err)
+	}
+	return snapshot, nil
+}
+
+func resumirLivros(livros map[string]*domain.Summary, filtro Filtro) []ResumoLivro {
+	var resumos []ResumoLivro
+	for _, livro := range OrdenarChaves(livros) {
+		if filtro.Livro != "" && livro != filtro.Livro {
+			continue
+		}
+		summary := livros[livro]
+		resumos = append(resumos, ResumoLivro{
+			Livro:          livro,
+			TotalTrabalhos: summary.TotalTrabalhos,
+			Horas:          summary.Horas,
+			Voluntarios:    summary.TotalVoluntarios(),
+		})
+	}
+	return resumos
+}
+
+func (f Filtro) aceitaSetor(setor string) bool {
+	return f.Setor == "" || f.Setor == setor
+}
+
+func (f Filtro) aceitaLocalidade(localidade string) bool {
+	return f.Localidade == "" || f.Localidade == localidade
+}
+
+func nomeSetor(setor *domain.Setor) string {
+	if setor == nil {
+		return ""
+	}
+	return setor.Nome
+}
@@ -0,0 +1,177 @@
+package usecase
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	"report/internal/domain"
+)
+
+// consultaTeste monta uma consulta sobre dois setores e dois períodos do histórico
+func consultaTeste() *Consulta {
+	setores := setorRepoMemoria{
+		"SETOR 1": {Nome: "SETOR 1", Localidades: []string{"VILA NOVA", "CENTRO"}},
+		"SETOR 2": {Nome: "SETOR 2", Localidades: []string{"JARDIM"}},
+	}
+	livros := livroRepoMemoria{
+		"VILA NOVA": {"LIMPEZA": true, "JARDINAGEM": true},
+		"CENTRO":    {"LIMPEZA": true},
+		"JARDIM":    {"PORTARIA": true},
+	}
+	historico := historicoRepoMemoria{
+		"2025-01": {Periodo: "2025-01", Localidades: map[string]map[string]*domain.Summary{
+			"CENTRO": {"LIMPEZA": summaryTeste(map[string]int{"A": 1})},
+		}},
+		"2025-02": {
+			Periodo: "2025-02",
+			Localidades: map[string]map[string]*domain.Summary{
+				"VILA NOVA": {
+					"LIMPEZA":    summaryTeste(map[string]int{"A": 2, "B": 1}),
+					"JARDINAGEM": summaryTeste(map[string]int{"A": 1}),
+				},
+				"JARDIM": {"PORTARIA": summaryTeste(map[string]int{"A": 4, "C": 1})},
+			},
+			Alertas: map[string][]domain.Alerta{
+				"VILA NOVA": {{Livro: "LIMPEZA", Mensagem: "poucos voluntários"}, {Livro: "JARDINAGEM", Mensagem: "sem lançamentos"}},
+			},
+		},
+	}
+	return NewConsulta(setores, livros, historico)
+}
+
+func TestConsultaResumos(t *testing.T) {
+	tests := []struct {
+		nome   string
+		filtro Filtro
+		// localidades esperadas, com o total de trabalhos de cada livro
+		esperado map[string]map[string]int
+		err      error
+	}{
+		{
+			nome:   "período mais recente",
+			filtro: Filtro{},
+			esperado: map[string]map[string]int{
+				"JARDIM":    {"PORTARIA": 5},
+				"VILA NOVA": {"JARDINAGEM": 1, "LIMPEZA": 3},
+			},
+		},
+		{
+			nome:     "período informado",
+			filtro:   Filtro{Periodo: "2025-01"},
+			esperado: map[string]map[string]int{"CENTRO": {"LIMPEZA": 1}},
+		},
+		{
+			nome:     "filtro por setor e livro",
+			filtro:   Filtro{Setor: "SETOR 1", Livro: "LIMPEZA"},
+			esperado: map[string]map[string]int{"VILA NOVA": {"LIMPEZA": 3}},
+		},
+		{
+			nome:     "filtro por localidade",
+			filtro:   Filtro{Localidade: "JARDIM"},
+			esperado: map[string]map[string]int{"JARDIM": {"PORTARIA": 5}},
+		},
+		{nome: "período inválido", filtro: Filtro{Periodo: "02/2025"}, err: ErrPeriodoInvalido},
+		{nome: "período sem histórico", filtro: Filtro{Periodo: "2024-12"}, err: ErrPeriodoNaoEncontrado},
+	}
+
+	consulta := consultaTeste()
+	for _, tt := range tests {
+		t.Run(tt.nome, func(t *testing.T) {
+			resumos, err := consulta.Resumos(tt.filtro)
+			if !errors.Is(err, tt.err) {
+				t.Fatalf("err = %v, esperado %v", err, tt.err)
+			}
+			if tt.err != nil {
+				return
+			}
+			obtido := make(map[string]map[string]int)
+			for _, resumo := range resumos {
+				obtido[resumo.Localidade] = make(map[string]int)
+				for _, livro := range resumo.Livros {
+					obtido[resumo.Localidade][livro.Livro] = livro.TotalTrabalhos
+				}
+			}
+			if !reflect.DeepEqual(obtido, tt.esperado) {
+				t.Errorf("Resumos = %v, esperado %v", obtido, tt.esperado)
+			}
+		})
+	}
+}
+
+func TestConsultaAlertas(t *testing.T) {
+	tests := []struct {
+		nome     string
+		filtro   Filtro
+		esperado []string
+	}{
+		{"todos", Filtro{}, []string{"poucos voluntários", "sem lançamentos"}},
+		{"por livro", Filtro{Livro: "JARDINAGEM"}, []string{"sem lançamentos"}},
+		{"setor sem alertas", Filtro{Setor: "SETOR 2"}, nil},
+	}
+
+	consulta := consultaTeste()
+	for _, tt := range tests {
+		t.Run(tt.nome, func(t *testing.T) {
+			alertas, err := consulta.Alertas(tt.filtro)
+			if err != nil {
+				t.Fatal(err)
+			}
+			var mensagens []string
+			for _, localidade := range alertas {
+				for _, alerta := range localidade.Alertas {
+					mensagens = append(mensagens, alerta.Mensagem)
+				}
+			}
+			if !reflect.DeepEqual(mensagens, tt.esperado) {
+				t.Errorf("Alertas = %v, esperado %v", mensagens, tt.esperado)
+			}
+		})
+	}
+}
+
+func TestConsultaVoluntarios(t *testing.T) {
+	agregado, err := consultaTeste().Voluntarios(Filtro{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if agregado.Periodo != "2025-02" || agregado.Voluntarios != 3 || agregado.EmMultiplasLocalidades != 1 {
+		t.Errorf("agregado = %s, %d voluntários, %d em várias localidades; esperado 2025-02, 3 e 1",
+			agregado.Periodo, agregado.Voluntarios, agregado.EmMultiplasLocalidades)
+	}
+	porLocalidade := make(map[string]int)
+	for _, localidade := range agregado.Localidades {
+		porLocalidade[localidade.Localidade] = localidade.Voluntarios
+	}
+	if esperado := map[string]int{"VILA NOVA": 2, "JARDIM": 2}; !reflect.DeepEqual(porLocalidade, esperado) {
+		t.Errorf("voluntários por localidade = %v, esperado %v", porLocalidade, esperado)
+	}
+}
+
+func TestConsultaLocalidadesELivros(t *testing.T) {
+	consulta := consultaTeste()
+
+	localidades, err := consulta.Localidades(Filtro{Livro: "LIMPEZA"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var nomes []string
+	for _, localidade := range localidades {
+		nomes = append(nomes, localidade.Nome+"/"+localidade.Setor)
+	}
+	if esperado := []string{"CENTRO/SETOR 1", "VILA NOVA/SETOR 1"}; !reflect.DeepEqual(nomes, esperado) {
+		t.Errorf("Localidades = %v, esperado %v", nomes, esperado)
+	}
+
+	livros, err := consulta.Livros(Filtro{Setor: "SETOR 1"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	esperados := []LivroInfo{
+		{Nome: "JARDINAGEM", Localidades: []string{"VILA NOVA"}},
+		{Nome: "LIMPEZA", Localidades: []string{"CENTRO", "VILA NOVA"}},
+	}
+	if !reflect.DeepEqual(livros, esperados) {
+		t.Errorf("Livros = %+v, esperado %+v", livros, esperados)
+	}
+}
@@ -0,0 +1,246 @@
+package usecase
+
+import (
+	"fmt"
+	"strings"
+
+	"report/internal/domain"
+)
+
+// Situações de um livro na comparação entre duas entradas
+const (
+	SituacaoAdicionado = "adicionado"
+	SituacaoRemovido   = "removido"
+	SituacaoAlterado   = "alterado"
+)
+
+// DiferencaLivro descreve a mudança de contagem de um livro em uma localidade
+type DiferencaLivro struct {
+	Livro    string `json:"livro"`
+	Antes    int    `json:"antes"`
+	Depois   int    `json:"depois"`
+	Situacao string `json:"situacao"`
+}
+
+// Variacao retorna a diferença de apontamentos entre as duas entradas
+func (d DiferencaLivro) Variacao() int {
+	return d.Depois - d.Antes
+}
+
+// DiferencaVoluntario é um voluntário com apontamentos em um livro em apenas
+// uma das entradas
+type DiferencaVoluntario struct {
+	Livro      string `json:"livro"`
+	Voluntario string `json:"voluntario"`
+	// Lancamentos são os apontamentos na entrada em que o voluntário aparece
+	Lancamentos int    `json:"lancamentos"`
+	Situacao    string `json:"situacao"`
+}
+
+// DiferencaLocalidade reúne as mudanças de uma localidade
+type DiferencaLocalidade struct {
+	Localidade        string                `json:"localidade"`
+	Situacao          string                `json:"situacao,omitempty"`
+	Livros            []DiferencaLivro      `json:"livros,omitempty"`
+	Voluntarios       []DiferencaVoluntario `json:"voluntarios,omitempty"`
+	AlertasNovos      []string              `json:"alertas_novos,omitempty"`
+	AlertasResolvidos []string              `json:"alertas_resolvidos,omitempty"`
+}
+
+// Diferenca é o resultado da comparação entre duas entradas ou dois períodos
+type Diferenca struct {
+	Antes       string                `json:"antes"`
+	Depois      string                `json:"depois"`
+	Localidades []DiferencaLocalidade `json:"localidades"`
+}
+
+// Vazia indica se não houve nenhuma mudança entre as entradas
+func (d *Diferenca) Vazia() bool {
+	return len(d.Localidades) == 0
+}
+
+// String formata a comparação para exibição no console
+func (d *Diferenca) String() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "Comparação: %s -> %s\n", d.Antes, d.Depois)
+	if d.Vazia() {
+		b.WriteString("Nenhuma diferença encontrada\n")
+		return b.String()
+	}
+
+	for _, loc := range d.Localidades {
+		if loc.Situacao != "" {
+			fmt.Fprintf(&b, "\n%s (%s)\n", loc.Localidade, loc.Situacao)
+		} else {
+			fmt.Fprintf(&b, "\n%s\n", loc.Localidade)
+		}
+		for _, livro := range loc.Livros {
+			fmt.Fprintf(&b, "  %-40s %4d -> %4d (%+d) %s\n", livro.Livro, livro.Antes, livro.Depois, livro.Variacao(), livro.Situacao)
+		}
+		for _, voluntario := range loc.Voluntarios {
+			sinal := "+"
+			if voluntario.Situacao == SituacaoRemovido {
+				sinal = "-"
+			}
+			fmt.Fprintf(&b, "  %s voluntário %s em %s (%d lançamentos) %s\n",
+				sinal, voluntario.Voluntario, voluntario.Livro, voluntario.Lancamentos, voluntario.Situacao)
+		}
+		for _, alerta := range loc.AlertasNovos {
+			fmt.Fprintf(&b, "  + alerta: %s\n", alerta)
+		}
+		for _, alerta := range loc.AlertasResolvidos {
+			fmt.Fprintf(&b, "  - alerta resolvido: %s\n", alerta)
+		}
+	}
+	return b.String()
+}
+
+// CompararSnapshots compara os dados consolidados de duas entradas, listando
+// por localidade os livros adicionados, removidos ou com contagem alterada, os
+// voluntários que passaram a constar ou deixaram de constar em cada livro e
+// os alertas que surgiram ou foram resolvidos. Os voluntários são comparados
+// e apresentados pela chave da política de privacidade, de modo que uma
+// listagem pode ser comparada a um período do histórico.
+func CompararSnapshots(antes, depois *domain.Snapshot, rotuloAntes, rotuloDepois string, privacidade domain.Privacidade) *Diferenca {
+	diferenca := &Diferenca{Antes: rotuloAntes, Depois: rotuloDepois}
+	antes, depois = pseudonimizar(antes, privacidade), pseudonimizar(depois, privacidade)
+
+	nomes := make(map[string]bool)
+	for localidade := range antes.Localidades {
+		nomes[localidade] = true
+	}
+	for localidade := range depois.Localidades {
+		nomes[localidade] = true
+	}
+
+	for _, localidade := range OrdenarChaves(nomes) {
+		livrosAntes, existiaAntes := antes.Localidades[localidade]
+		livrosDepois, existeDepois := depois.Localidades[localidade]
+
+		loc := DiferencaLocalidade{Localidade: localidade}
+		switch {
+		case !existiaAntes:
+			loc.Situacao = SituacaoAdicionado
+		case !existeDepois:
+			loc.Situacao = SituacaoRemovido
+		}
+
+		loc.Livros = compararLivros(livrosAntes, livrosDepois)
+		loc.Voluntarios = compararVoluntarios(livrosAntes, livrosDepois)
+		loc.AlertasNovos, loc.AlertasResolvidos = compararAlertas(antes.Alertas[localidade], depois.Alertas[localidade])
+
+		if loc.Situacao != "" || len(loc.Livros) > 0 || len(loc.Voluntarios) > 0 ||
+			len(loc.AlertasNovos) > 0 || len(loc.AlertasResolvidos) > 0 {
+			diferenca.Localidades = append(diferenca.Localidades, loc)
+		}
+	}
+
+	return diferenca
+}
+
+func compararLivros(antes, depois map[string]*domain.Summary) []DiferencaLivro {
+	nomes := make(map[string]bool)
+	for livro := range antes {
+		nomes[livro] = true
+	}
+	for livro := range depois {
+		nomes[livro] = true
+	}
+
+	var diferencas []DiferencaLivro
+	for _, livro := range OrdenarChaves(nomes) {
+		a, existiaAntes := antes[livro]
+		d, existeDepois := depois[livro]
+
+		diferenca := DiferencaLivro{Livro: livro}
+		if existiaAntes {
+			diferenca.Antes = a.TotalTrabalhos
+		}
+		if existeDepois {
+			diferenca.Depois = d.TotalTrabalhos
+		}
+
+		switch {
+		case !existiaAntes:
+			diferenca.Situacao = SituacaoAdicionado
+		case !existeDepois:
+			diferenca.Situacao = SituacaoRemovido
+		case diferenca.Antes != diferenca.Depois:
+			diferenca.Situacao = SituacaoAlterado
+		default:
+			continue
+		}
+		diferencas = append(diferencas, diferenca)
+	}
+
+	return diferencas
+}
+
+// compararVoluntarios lista, livro a livro, os voluntários presentes em apenas
+// uma das entradas
+func compararVoluntarios(antes, depois map[string]*domain.Summary) []DiferencaVoluntario {
+	nomes := make(map[string]bool)
+	for livro := range antes {
+		nomes[livro] = true
+	}
+	for livro := range depois {
+		nomes[livro] = true
+	}
+
+	var diferencas []DiferencaVoluntario
+	for _, livro := range OrdenarChaves(nomes) {
+		var voluntariosAntes, voluntariosDepois map[string]int
+		if summary, exists := antes[livro]; exists {
+			voluntariosAntes = summary.Voluntarios
+		}
+		if summary, exists := depois[livro]; exists {
+			voluntariosDepois = summary.Voluntarios
+		}
+
+		for _, voluntario := range OrdenarChaves(voluntariosDepois) {
+			if _, existia := voluntariosAntes[voluntario]; !existia {
+				diferencas = append(diferencas, DiferencaVoluntario{
+					Livro:       livro,
+					Voluntario:  voluntario,
+					Lancamentos: voluntariosDepois[voluntario],
+					Situacao:    SituacaoAdicionado,
+				})
+			}
+		}
+		for _, voluntario := range OrdenarChaves(voluntariosAntes) {
+			if _, existe := voluntariosDepois[voluntario]; !existe {
+				diferencas = append(diferencas, DiferencaVoluntario{
+					Livro:       livro,
+					Voluntario:  voluntario,
+					Lancamentos: voluntariosAntes[voluntario],
+					Situacao:    SituacaoRemovido,
+				})
+			}
+		}
+	}
+
+	return diferencas
+}
+
+func compararAlertas(antes, depois []domain.Alerta) (novos, resolvidos []string) {
+	existiam := make(map[string]bool, len(antes))
+	for _, alerta := range antes {
+		existiam[alerta.Mensagem] = true
+	}
+	existem := make(map[string]bool, len(depois))
+	for _, alerta := range depois {
+		existem[alerta.Mensagem] = true
+	}
+
+	for _, alerta := range depois {
+		if !existiam[alerta.Mensagem] {
+			novos = append(novos, alerta.Mensagem)
+		}
+	}
+	for _, alerta := range antes {
+		if !existem[alerta.Mensagem] {
+			resolvidos = append(resolvidos, alerta.Mensagem)
+		}
+	}
+	return novos, resolvidos
+}
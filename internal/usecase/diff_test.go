@@ -0,0 +1,138 @@
+package usecase
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"report/internal/domain"
+)
+
+// summaryTeste cria o resumo de um livro com os apontamentos de cada voluntário
+func summaryTeste(voluntarios map[string]int) *domain.Summary {
+	summary := &domain.Summary{Voluntarios: voluntarios}
+	for _, n := range voluntarios {
+		summary.TotalTrabalhos += n
+	}
+	return summary
+}
+
+func TestCompararSnapshots(t *testing.T) {
+	antes := &domain.Snapshot{
+		Localidades: map[string]map[string]*domain.Summary{
+			"VILA NOVA": {
+				"LIMPEZA":    summaryTeste(map[string]int{"ANA LIMA": 3, "JOSE DA SILVA": 1}),
+				"JARDINAGEM": summaryTeste(map[string]int{"ANA LIMA": 2}),
+			},
+			"CENTRO": {"LIMPEZA": summaryTeste(map[string]int{"ANA LIMA": 1})},
+		},
+		Alertas: map[string][]domain.Alerta{
+			"VILA NOVA": {{Mensagem: "sem manutenção"}, {Mensagem: "poucos voluntários"}},
+		},
+	}
+	depois := &domain.Snapshot{
+		Localidades: map[string]map[string]*domain.Summary{
+			"VILA NOVA": {
+				// Do histórico, os voluntários já estão identificados pela chave
+				"LIMPEZA": summaryTeste(map[string]int{
+					domain.PrivacidadeIniciais.Chave("ANA LIMA"):   3,
+					domain.PrivacidadeIniciais.Chave("MARIA REIS"): 2,
+				}),
+				"PORTARIA": summaryTeste(map[string]int{"ANA LIMA": 1}),
+			},
+			"JARDIM": {"LIMPEZA": summaryTeste(map[string]int{"ANA LIMA": 1})},
+		},
+		Alertas: map[string][]domain.Alerta{
+			"VILA NOVA": {{Mensagem: "poucos voluntários"}, {Mensagem: "livro sem lançamentos"}},
+		},
+	}
+	chave := domain.PrivacidadeIniciais.Chave
+
+	diferenca := CompararSnapshots(antes, depois, "2025-01", "2025-02", domain.PrivacidadeIniciais)
+
+	esperadas := []DiferencaLocalidade{
+		{
+			Localidade: "CENTRO",
+			Situacao:   SituacaoRemovido,
+			Livros:     []DiferencaLivro{{Livro: "LIMPEZA", Antes: 1, Situacao: SituacaoRemovido}},
+			Voluntarios: []DiferencaVoluntario{
+				{Livro: "LIMPEZA", Voluntario: chave("ANA LIMA"), Lancamentos: 1, Situacao: SituacaoRemovido},
+			},
+		},
+		{
+			Localidade: "JARDIM",
+			Situacao:   SituacaoAdicionado,
+			Livros:     []DiferencaLivro{{Livro: "LIMPEZA", Depois: 1, Situacao: SituacaoAdicionado}},
+			Voluntarios: []DiferencaVoluntario{
+				{Livro: "LIMPEZA", Voluntario: chave("ANA LIMA"), Lancamentos: 1, Situacao: SituacaoAdicionado},
+			},
+		},
+		{
+			Localidade: "VILA NOVA",
+			Livros: []DiferencaLivro{
+				{Livro: "JARDINAGEM", Antes: 2, Situacao: SituacaoRemovido},
+				{Livro: "LIMPEZA", Antes: 4, Depois: 5, Situacao: SituacaoAlterado},
+				{Livro: "PORTARIA", Depois: 1, Situacao: SituacaoAdicionado},
+			},
+			Voluntarios: []DiferencaVoluntario{
+				{Livro: "JARDINAGEM", Voluntario: chave("ANA LIMA"), Lancamentos: 2, Situacao: SituacaoRemovido},
+				{Livro: "LIMPEZA", Voluntario: chave("MARIA REIS"), Lancamentos: 2, Situacao: SituacaoAdicionado},
+				{Livro: "LIMPEZA", Voluntario: chave("JOSE DA SILVA"), Lancamentos: 1, Situacao: SituacaoRemovido},
+				{Livro: "PORTARIA", Voluntario: chave("ANA LIMA"), Lancamentos: 1, Situacao: SituacaoAdicionado},
+			},
+			AlertasNovos:      []string{"livro sem lançamentos"},
+			AlertasResolvidos: []string{"sem manutenção"},
+		},
+	}
+	if !reflect.DeepEqual(diferenca.Localidades, esperadas) {
+		t.Errorf("Localidades =\n%+v\nesperado\n%+v", diferenca.Localidades, esperadas)
+	}
+
+	// Os snapshots comparados não são alterados
+	if _, exists := antes.Localidades["VILA NOVA"]["LIMPEZA"].Voluntarios["ANA LIMA"]; !exists {
+		t.Error("CompararSnapshots alterou os voluntários do snapshot recebido")
+	}
+}
+
+func TestDiferencaString(t *testing.T) {
+	tests := []struct {
+		nome      string
+		diferenca Diferenca
+		contem    []string
+	}{
+		{
+			nome:      "sem diferenças",
+			diferenca: Diferenca{Antes: "a.csv", Depois: "b.csv"},
+			contem:    []string{"Comparação: a.csv -> b.csv\n", "Nenhuma diferença encontrada\n"},
+		},
+		{
+			nome: "com diferenças",
+			diferenca: Diferenca{Antes: "2025-01", Depois: "2025-02", Localidades: []DiferencaLocalidade{{
+				Localidade:        "VILA NOVA",
+				Situacao:          SituacaoAdicionado,
+				Livros:            []DiferencaLivro{{Livro: "LIMPEZA", Antes: 2, Depois: 5, Situacao: SituacaoAlterado}},
+				Voluntarios:       []DiferencaVoluntario{{Livro: "LIMPEZA", Voluntario: "A. L. #00000000", Lancamentos: 1, Situacao: SituacaoRemovido}},
+				AlertasNovos:      []string{"novo"},
+				AlertasResolvidos: []string{"antigo"},
+			}}},
+			contem: []string{
+				"\nVILA NOVA (adicionado)\n",
+				"(+3) alterado",
+				"  - voluntário A. L. #00000000 em LIMPEZA (1 lançamentos) removido\n",
+				"  + alerta: novo\n",
+				"  - alerta resolvido: antigo\n",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.nome, func(t *testing.T) {
+			texto := tt.diferenca.String()
+			for _, trecho := range tt.contem {
+				if !strings.Contains(texto, trecho) {
+					t.Errorf("comparação sem %q:\n%s", trecho, texto)
+				}
+			}
+		})
+	}
+}
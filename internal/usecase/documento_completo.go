@@ -0,0 +1,106 @@
+package usecase
+
+import (
+	"fmt"
+	"path/filepath"
+	"sort"
+	"time"
+
+	"report/internal/domain"
+)
+
+// documentoCompletoNome é o arquivo do documento único com todos os relatórios
+const documentoCompletoNome = "relatorio_completo.pdf"
+
+// CombinedReportData contém os dados do documento único: capa, sumário,
+// matriz resumo e os relatórios das localidades agrupados por setor
+type CombinedReportData struct {
+	Titulo  string
+	Data    time.Time `json:"-"`
+	Periodo string
+	Resumo  *ReportData
+	Setores []SecaoSetor
+}
+
+// SecaoSetor agrupa os relatórios das localidades de um setor
+type SecaoSetor struct {
+	Nome        string
+	Localidades []*ReportData
+}
+
+// WithDocumentoCompleto habilita a geração do documento único com todos os relatórios
+func WithDocumentoCompleto() Option {
+	return func(g *ReportGenerator) {
+		g.documentoCompleto = true
+	}
+}
+
+func (g *ReportGenerator) documentoCompletoOutputPath() string {
+	return filepath.Join(g.outputDir, documentoCompletoNome)
+}
+
+// combinedReportData monta o documento único com os setores em ordem
+// alfabética e as localidades sem setor ao final; relatorios são os dados
+// dos relatórios individuais de cada localidade
+func (g *ReportGenerator) combinedReportData(
+	snapshot *domain.Snapshot,
+	grupos map[string]*grupoSetor,
+	livros map[string]map[string]bool,
+	cumprimentos map[string]*CumprimentoLocalidade,
+	relatorios map[string]*ReportData,
+) *CombinedReportData {
+	saudes := make(map[string]*SaudeLocalidade, len(relatorios))
+	for localidade, relatorio := range relatorios {
+		saudes[localidade] = relatorio.Saude
+	}
+	data := &CombinedReportData{
+		Titulo:  "Relatórios das Localidades",
+		Data:    time.Now(),
+		Periodo: g.periodo,
+		Resumo: &ReportData{
+			Titulo:           "Resumo de Todas as Localidades",
+			Data:             time.Now(),
+			Periodo:          g.periodo,
+			Metrica:          g.metrica,
+			Localidades:      snapshot.Localidades,
+			LivrosMap:        livros,
+			Totais:           consolidarLivros(snapshot.Localidades, OrdenarChaves(snapshot.Localidades)),
+			Ranking:          rankingCumprimento(cumprimentos, OrdenarChaves(snapshot.Localidades)),
+			SaudeLocalidades: saudes,
+		},
+	}
+
+	diretorios := OrdenarChaves(grupos)
+	sort.SliceStable(diretorios, func(i, j int) bool {
+		return diretorios[i] != diretorioOutros && diretorios[j] == diretorioOutros
+	})
+
+	for _, diretorio := range diretorios {
+		grupo := grupos[diretorio]
+		secao := SecaoSetor{Nome: grupo.Nome}
+		if diretorio == diretorioOutros {
+			secao.Nome = nomeSemSetor
+		}
+
+		localidades := append([]string(nil), grupo.Localidades...)
+		sort.Strings(localidades)
+		for _, localidade := range localidades {
+			secao.Localidades = append(secao.Localidades, relatorios[localidade])
+		}
+		data.Setores = append(data.Setores, secao)
+	}
+
+	return data
+}
+
+func (g *ReportGenerator) documentoCompletoDocumento(data *CombinedReportData) documento {
+	caminho := g.documentoCompletoOutputPath()
+	return documento{
+		Nome:    fmt.Sprintf("documento completo de %s", g.periodo),
+		Caminho: caminho,
+		Chave:   g.chaveDocumento("completo", data),
+		gerar: func() error {
+			return g.pdfService.GenerateCombinedReport(data, caminho)
+		},
+	}
+}
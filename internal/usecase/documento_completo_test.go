@@ -0,0 +1,103 @@
+package usecase
+
+import (
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"report/internal/domain"
+)
+
+func TestCombinedReportData(t *testing.T) {
+	snapshot := &domain.Snapshot{Localidades: map[string]map[string]*domain.Summary{
+		"VILA NOVA": {"LIMPEZA": summaryTeste(map[string]int{"A": 2})},
+		"CENTRO":    {"LIMPEZA": summaryTeste(map[string]int{"B": 1})},
+		"JARDIM":    {"PORTARIA": summaryTeste(map[string]int{"A": 3})},
+		"AVULSA":    {"PORTARIA": summaryTeste(map[string]int{"C": 1})},
+	}}
+	grupos := map[string]*grupoSetor{
+		diretorioOutros: {Nome: diretorioOutros, Localidades: []string{"AVULSA"}},
+		"Setor 2":       {Nome: "Setor 2", Localidades: []string{"JARDIM"}},
+		"Setor 1":       {Nome: "Setor 1", Localidades: []string{"VILA NOVA", "CENTRO"}},
+	}
+	relatorios := make(map[string]*ReportData)
+	for localidade := range snapshot.Localidades {
+		relatorios[localidade] = &ReportData{Titulo: localidade, Saude: &SaudeLocalidade{Nota: 50}}
+	}
+	cumprimentos := map[string]*CumprimentoLocalidade{
+		"CENTRO": {Localidade: "CENTRO", Percentual: 80},
+		"JARDIM": {Localidade: "JARDIM", Percentual: 95},
+	}
+	g := NewReportGenerator(nil, nil, nil, &pdfMemoria{}, WithPeriodo("2025-02"))
+
+	data := g.combinedReportData(snapshot, grupos, nil, cumprimentos, relatorios)
+
+	var secoes []string
+	for _, secao := range data.Setores {
+		for _, relatorio := range secao.Localidades {
+			secoes = append(secoes, secao.Nome+"/"+relatorio.Titulo)
+		}
+	}
+	esperadas := []string{"Setor 1/CENTRO", "Setor 1/VILA NOVA", "Setor 2/JARDIM", nomeSemSetor + "/AVULSA"}
+	if !reflect.DeepEqual(secoes, esperadas) {
+		t.Errorf("seções = %v, esperado %v", secoes, esperadas)
+	}
+	if data.Periodo != "2025-02" || data.Resumo.Periodo != "2025-02" {
+		t.Errorf("período = %s/%s, esperado 2025-02", data.Periodo, data.Resumo.Periodo)
+	}
+	totais := map[string]int{}
+	for livro, summary := range data.Resumo.Totais {
+		totais[livro] = summary.TotalTrabalhos
+	}
+	if esperado := map[string]int{"LIMPEZA": 3, "PORTARIA": 4}; !reflect.DeepEqual(totais, esperado) {
+		t.Errorf("totais = %v, esperado %v", totais, esperado)
+	}
+	var ranking []string
+	for _, cumprimento := range data.Resumo.Ranking {
+		ranking = append(ranking, cumprimento.Localidade)
+	}
+	if esperado := []string{"JARDIM", "CENTRO"}; !reflect.DeepEqual(ranking, esperado) {
+		t.Errorf("ranking = %v, esperado %v", ranking, esperado)
+	}
+	if len(data.Resumo.SaudeLocalidades) != len(relatorios) {
+		t.Errorf("%d notas de saúde no resumo, esperado %d", len(data.Resumo.SaudeLocalidades), len(relatorios))
+	}
+}
+
+func TestDocumentoCompletoDocumento(t *testing.T) {
+	pdf := &pdfMemoria{}
+	g := NewReportGenerator(nil, nil, nil, pdf, WithOutputDir("saida"), WithPeriodo("2025-02"))
+	data := &CombinedReportData{Titulo: "Relatórios", Periodo: "2025-02"}
+
+	doc := g.documentoCompletoDocumento(data)
+	if doc.Caminho != filepath.Join("saida", documentoCompletoNome) {
+		t.Errorf("Caminho = %s", doc.Caminho)
+	}
+	if doc.Chave == "" || doc.Chave != g.documentoCompletoDocumento(&CombinedReportData{Titulo: "Relatórios", Periodo: "2025-02"}).Chave {
+		t.Error("a chave deve depender apenas dos dados do documento")
+	}
+	if outro := g.documentoCompletoDocumento(&CombinedReportData{Titulo: "Outro", Periodo: "2025-02"}); outro.Chave == doc.Chave {
+		t.Error("dados diferentes geraram a mesma chave")
+	}
+	if err := doc.gerar(); err != nil {
+		t.Fatal(err)
+	}
+	if pdf.documentos[doc.Caminho] != data {
+		t.Error("documento completo não gerado com os dados montados")
+	}
+}
+
+func TestOrdenarChaves(t *testing.T) {
+	tests := []struct {
+		mapa     map[string]int
+		esperado []string
+	}{
+		{nil, []string{}},
+		{map[string]int{"b": 1, "a": 2, "C": 3}, []string{"C", "a", "b"}},
+	}
+	for _, tt := range tests {
+		if chaves := OrdenarChaves(tt.mapa); !reflect.DeepEqual(chaves, tt.esperado) {
+			t.Errorf("OrdenarChaves(%v) = %v, esperado %v", tt.mapa, chaves, tt.esperado)
+		}
+	}
+}
@@ -0,0 +1,345 @@
+package usecase
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"log/slog"
+	"net/mail"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"text/template"
+	"time"
+
+	"report/internal/domain"
+)
+
+// ModeloEmailPadrao é o corpo usado quando nenhum modelo é configurado
+const ModeloEmailPadrao = `Prezados responsáveis pelo {{.Setor}},
+
+Seguem em anexo os relatórios do período {{.Periodo}}.
+{{if .Alertas}}
+Pontos de atenção:
+{{range .Alertas}}
+{{.Localidade}}:
+{{range .Alertas}}  - {{.Mensagem}}
+{{end}}{{end}}{{else}}
+Nenhum ponto de atenção no período.
+{{end}}
+Anexos:
+{{range .Anexos}}  - {{.}}
+{{end}}
+Mensagem enviada automaticamente pelo gerador de relatórios.
+`
+
+// Mensagem representa um e-mail com anexos
+type Mensagem struct {
+	De      string
+	Para    []string
+	Assunto string
+	Corpo   string
+	Anexos  []string
+}
+
+// EmailService define a interface para envio das mensagens
+type EmailService interface {
+	Send(ctx context.Context, mensagem *Mensagem) error
+}
+
+// Situações da entrega de um setor
+const (
+	EntregaEnviada    = "enviado"
+	EntregaSimulada   = "simulado"
+	EntregaJaEnviada  = "já enviado"
+	EntregaSemContato = "sem contato"
+	EntregaSemAnexos  = "sem anexos"
+	EntregaFalhou     = "falhou"
+)
+
+// EntregaSetor registra o resultado da entrega de um setor
+type EntregaSetor struct {
+	Setor         string
+	Situacao      string
+	Destinatarios []string
+	Anexos        []string
+	Err           error
+}
+
+// ResultadoEntrega resume a entrega dos relatórios de um período
+type ResultadoEntrega struct {
+	Periodo string
+	Setores []EntregaSetor
+}
+
+// Falhas retorna as entregas que não puderam ser concluídas
+func (r *ResultadoEntrega) Falhas() []EntregaSetor {
+	var falhas []EntregaSetor
+	for _, e := range r.Setores {
+		if e.Situacao == EntregaFalhou {
+			falhas = append(falhas, e)
+		}
+	}
+	return falhas
+}
+
+// String formata o resumo da entrega para exibição no console
+func (r *ResultadoEntrega) String() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "Entrega dos relatórios de %s\n", r.Periodo)
+	for _, e := range r.Setores {
+		fmt.Fprintf(&b, "  - %s: %s", e.Setor, e.Situacao)
+		if len(e.Destinatarios) > 0 {
+			fmt.Fprintf(&b, " (%s)", strings.Join(e.Destinatarios, ", "))
+		}
+		if e.Err != nil {
+			fmt.Fprintf(&b, ": %v", e.Err)
+		}
+		b.WriteString("\n")
+	}
+	return b.String()
+}
+
+// OpcoesEntrega define os parâmetros de uma entrega
+type OpcoesEntrega struct {
+	Periodo   string
+	OutputDir string
+	Remetente string
+	// Modelo é o corpo da mensagem em text/template; vazio usa ModeloEmailPadrao
+	Modelo string
+	// Simular grava as mensagens sem registrá-las como enviadas
+	Simular bool
+	// Reenviar ignora o registro de envios anteriores
+	Reenviar bool
+}
+
+// Entrega define o caso de uso de envio dos relatórios aos setores
+type Entrega struct {
+	setorRepo       domain.SetorRepository
+	historicoRepo   domain.HistoricoRepository
+	envioRepo       domain.EnvioRepository
+	manifestService ManifestService
+	emailService    EmailService
+	logger          *slog.Logger
+}
+
+// NewEntrega cria uma nova instância de Entrega. O manifesto do diretório de
+// saída indica o período dos PDFs enviados quando não há pacote ZIP.
+func NewEntrega(
+	setorRepo domain.SetorRepository,
+	historicoRepo domain.HistoricoRepository,
+	envioRepo domain.EnvioRepository,
+	manifestService ManifestService,
+	emailService EmailService,
+	logger *slog.Logger,
+) *Entrega {
+	return &Entrega{
+		setorRepo:       setorRepo,
+		historicoRepo:   historicoRepo,
+		envioRepo:       envioRepo,
+		manifestService: manifestService,
+		emailService:    emailService,
+		logger:          logger,
+	}
+}
+
+// dadosModelo são os campos disponíveis no modelo da mensagem
+type dadosModelo struct {
+	Setor   string
+	Periodo string
+	Alertas []AlertasLocalidade
+	Anexos  []string
+}
+
+// Entregar envia a cada setor com contatos cadastrados o pacote ZIP do
+// período ou, sem ele, os PDFs do diretório do setor, desde que o manifesto
+// do diretório de saída seja do mesmo período. Setores já atendidos no
+// período são ignorados, salvo com opcoes.Reenviar.
+func (e *Entrega) Entregar(ctx context.Context, opcoes OpcoesEntrega) (*ResultadoEntrega, error) {
+	// O remetente é conferido também na simulação, para que as mensagens
+	// gravadas sejam as mesmas que seriam enviadas
+	if _, err := mail.ParseAddress(opcoes.Remetente); err != nil {
+		return nil, fmt.Errorf("remetente inválido %q: %v", opcoes.Remetente, err)
+	}
+
+	modelo := opcoes.Modelo
+	if modelo == "" {
+		modelo = ModeloEmailPadrao
+	}
+	tmpl, err := template.New("email").Parse(modelo)
+	if err != nil {
+		return nil, fmt.Errorf("erro ao ler modelo da mensagem: %v", err)
+	}
+
+	snapshot, err := obterHistorico(e.historicoRepo, opcoes.Periodo)
+	if err != nil {
+		return nil, err
+	}
+
+	manifest, err := e.manifestService.Load(filepath.Join(opcoes.OutputDir, ManifestFileName))
+	if err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return nil, fmt.Errorf("erro ao ler manifesto: %v", err)
+	}
+
+	setores, err := e.setorRepo.GetAll()
+	if err != nil {
+		return nil, fmt.Errorf("erro ao obter setores: %v", err)
+	}
+	porNome := make(map[string]*domain.Setor)
+	for _, setor := range setores {
+		porNome[setor.Nome] = setor
+	}
+
+	resultado := &ResultadoEntrega{Periodo: opcoes.Periodo}
+	for _, nome := range OrdenarChaves(porNome) {
+		if err := ctx.Err(); err != nil {
+			return resultado, err
+		}
+		entrega := e.entregarSetor(ctx, porNome[nome], snapshot, manifest, tmpl, opcoes)
+		if entrega.Err != nil {
+			e.logger.Error("falha ao entregar relatórios", "setor", nome, "erro", entrega.Err)
+		} else {
+			e.logger.Info("entrega do setor", "setor", nome, "situacao", entrega.Situacao)
+		}
+		resultado.Setores = append(resultado.Setores, entrega)
+	}
+	return resultado, nil
+}
+
+func (e *Entrega) entregarSetor(
+	ctx context.Context,
+	setor *domain.Setor,
+	snapshot *domain.Snapshot,
+	manifest *Manifest,
+	tmpl *template.Template,
+	opcoes OpcoesEntrega,
+) EntregaSetor {
+	entrega := EntregaSetor{Setor: setor.Nome, Destinatarios: setor.Emails}
+	if len(setor.Emails) == 0 {
+		entrega.Situacao = EntregaSemContato
+		return entrega
+	}
+
+	anexos := anexosSetor(opcoes.OutputDir, setor.Responsavel, opcoes.Periodo, manifest)
+	if len(anexos) == 0 {
+		entrega.Situacao = EntregaSemAnexos
+		return entrega
+	}
+	entrega.Anexos = anexos
+
+	hash, err := hashArquivos(anexos)
+	if err != nil {
+		return falhaEntrega(entrega, err)
+	}
+
+	if !opcoes.Simular && !opcoes.Reenviar {
+		anterior, err := e.envioRepo.Get(setor.Nome, opcoes.Periodo)
+		if err != nil {
+			return falhaEntrega(entrega, fmt.Errorf("erro ao consultar envios: %v", err))
+		}
+		if anterior != nil {
+			entrega.Situacao = EntregaJaEnviada
+			if anterior.SHA256 != hash {
+				entrega.Situacao += " (anexos alterados desde o envio)"
+			}
+			return entrega
+		}
+	}
+
+	dados := dadosModelo{Setor: setor.Nome, Periodo: opcoes.Periodo}
+	for _, anexo := range anexos {
+		dados.Anexos = append(dados.Anexos, filepath.Base(anexo))
+	}
+	localidades := append([]string(nil), setor.Localidades...)
+	sort.Strings(localidades)
+	for _, localidade := range localidades {
+		if alertas := snapshot.Alertas[localidade]; len(alertas) > 0 {
+			dados.Alertas = append(dados.Alertas, AlertasLocalidade{Localidade: localidade, Setor: setor.Nome, Alertas: alertas})
+		}
+	}
+
+	var corpo strings.Builder
+	if err := tmpl.Execute(&corpo, dados); err != nil {
+		return falhaEntrega(entrega, fmt.Errorf("erro ao montar mensagem: %v", err))
+	}
+
+	mensagem := &Mensagem{
+		De:      opcoes.Remetente,
+		Para:    setor.Emails,
+		Assunto: fmt.Sprintf("Relatórios do %s - %s", setor.Nome, opcoes.Periodo),
+		Corpo:   corpo.String(),
+		Anexos:  anexos,
+	}
+	if err := e.emailService.Send(ctx, mensagem); err != nil {
+		return falhaEntrega(entrega, err)
+	}
+
+	if opcoes.Simular {
+		entrega.Situacao = EntregaSimulada
+		return entrega
+	}
+
+	envio := &domain.Envio{
+		Setor:         setor.Nome,
+		Periodo:       opcoes.Periodo,
+		Destinatarios: setor.Emails,
+		Anexos:        dados.Anexos,
+		SHA256:        hash,
+		EnviadoEm:     time.Now(),
+	}
+	if err := e.envioRepo.Save(envio); err != nil {
+		return falhaEntrega(entrega, fmt.Errorf("mensagem enviada, mas houve erro ao registrar o envio: %v", err))
+	}
+	entrega.Situacao = EntregaEnviada
+	return entrega
+}
+
+// anexosSetor retorna o pacote ZIP do setor no período ou, sem ele, os PDFs
+// do diretório do setor listados no manifesto. PDFs de um manifesto de outro
+// período, ou sem manifesto, não são enviados.
+func anexosSetor(outputDir, diretorio, periodo string, manifest *Manifest) []string {
+	pacote := filepath.Join(outputDir, pacotesDir, fmt.Sprintf("%s_%s.zip", diretorio, periodo))
+	if _, err := os.Stat(pacote); err == nil {
+		return []string{pacote}
+	}
+	if manifest == nil || manifest.Periodo != periodo {
+		return nil
+	}
+
+	var pdfs []string
+	for _, documento := range manifest.Documentos {
+		caminho := filepath.FromSlash(documento.Caminho)
+		if filepath.Dir(caminho) == filepath.Clean(diretorio) && strings.EqualFold(filepath.Ext(caminho), ".pdf") {
+			pdfs = append(pdfs, filepath.Join(outputDir, caminho))
+		}
+	}
+	sort.Strings(pdfs)
+	return pdfs
+}
+
+func hashArquivos(caminhos []string) (string, error) {
+	hash := sha256.New()
+	for _, caminho := range caminhos {
+		file, err := os.Open(caminho)
+		if err != nil {
+			return "", err
+		}
+		_, err = io.Copy(hash, file)
+		file.Close()
+		if err != nil {
+			return "", err
+		}
+	}
+	return hex.EncodeToString(hash.Sum(nil)), nil
+}
+
+func falhaEntrega(entrega EntregaSetor, err error) EntregaSetor {
+	entrega.Situacao = EntregaFalhou
+	entrega.Err = err
+	return entrega
+}
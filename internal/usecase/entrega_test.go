@@ -0,0 +1,179 @@
+package usecase
+
+import (
+	"context"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"report/internal/domain"
+)
+
+// emailMemoria implementa EmailService guardando as mensagens enviadas
+type emailMemoria []*Mensagem
+
+func (s *emailMemoria) Send(ctx context.Context, mensagem *Mensagem) error {
+	*s = append(*s, mensagem)
+	return nil
+}
+
+func TestEntregar(t *testing.T) {
+	setores := setorRepoMemoria{
+		"Setor 1": {Nome: "Setor 1", Responsavel: "Setor 1", Localidades: []string{"VILA NOVA"}, Emails: []string{"setor1@example.org"}},
+		"Setor 2": {Nome: "Setor 2", Responsavel: "Setor 2", Localidades: []string{"JARDIM"}},
+	}
+	historico := historicoRepoMemoria{"2025-02": {
+		Periodo: "2025-02",
+		Alertas: map[string][]domain.Alerta{"VILA NOVA": {{Livro: "LIMPEZA", Mensagem: "poucos voluntários"}}},
+	}}
+
+	tests := []struct {
+		nome     string
+		arquivos []string
+		// periodoManifesto vazio indica diretório sem manifesto
+		periodoManifesto string
+		listados         []string
+		anterior         *domain.Envio
+		reenviar         bool
+		situacao         string
+		anexos           []string
+	}{
+		{
+			nome:             "pacote do período",
+			arquivos:         []string{"pacotes/Setor 1_2025-02.zip", "Setor 1/VILA NOVA.pdf"},
+			periodoManifesto: "2025-02",
+			listados:         []string{"Setor 1/VILA NOVA.pdf"},
+			situacao:         EntregaEnviada,
+			anexos:           []string{"pacotes/Setor 1_2025-02.zip"},
+		},
+		{
+			nome:             "PDFs listados no manifesto do período",
+			arquivos:         []string{"Setor 1/VILA NOVA.pdf", "Setor 1/resumo_setor.pdf", "Setor 1/antigo.pdf"},
+			periodoManifesto: "2025-02",
+			listados:         []string{"Setor 1/VILA NOVA.pdf", "Setor 1/resumo_setor.pdf", "Setor 2/JARDIM.pdf"},
+			situacao:         EntregaEnviada,
+			anexos:           []string{"Setor 1/VILA NOVA.pdf", "Setor 1/resumo_setor.pdf"},
+		},
+		{
+			nome:             "PDFs de outro período",
+			arquivos:         []string{"Setor 1/VILA NOVA.pdf", "Setor 1/resumo_setor.pdf"},
+			periodoManifesto: "2025-01",
+			listados:         []string{"Setor 1/VILA NOVA.pdf", "Setor 1/resumo_setor.pdf"},
+			situacao:         EntregaSemAnexos,
+		},
+		{
+			nome:     "PDFs sem manifesto",
+			arquivos: []string{"Setor 1/VILA NOVA.pdf"},
+			situacao: EntregaSemAnexos,
+		},
+		{
+			nome:             "já enviado",
+			arquivos:         []string{"pacotes/Setor 1_2025-02.zip"},
+			periodoManifesto: "2025-02",
+			anterior:         &domain.Envio{Setor: "Setor 1", Periodo: "2025-02"},
+			situacao:         EntregaJaEnviada + " (anexos alterados desde o envio)",
+		},
+		{
+			nome:             "reenvio",
+			arquivos:         []string{"pacotes/Setor 1_2025-02.zip"},
+			periodoManifesto: "2025-02",
+			anterior:         &domain.Envio{Setor: "Setor 1", Periodo: "2025-02"},
+			reenviar:         true,
+			situacao:         EntregaEnviada,
+			anexos:           []string{"pacotes/Setor 1_2025-02.zip"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.nome, func(t *testing.T) {
+			dir := t.TempDir()
+			for _, arquivo := range tt.arquivos {
+				gravarArquivo(t, filepath.Join(dir, filepath.FromSlash(arquivo)), arquivo)
+			}
+			manifestos := &manifestMemoria{}
+			if tt.periodoManifesto != "" {
+				manifest := &Manifest{Periodo: tt.periodoManifesto}
+				for _, arquivo := range tt.listados {
+					manifest.Documentos = append(manifest.Documentos, ArquivoManifest{Caminho: arquivo})
+				}
+				manifestos.Save(manifest, filepath.Join(dir, ManifestFileName))
+			}
+			envios := envioRepoMemoria{}
+			if tt.anterior != nil {
+				envios.Save(tt.anterior)
+			}
+			emails := &emailMemoria{}
+			entrega := NewEntrega(setores, historico, envios, manifestos, emails, loggerTeste)
+
+			resultado, err := entrega.Entregar(context.Background(), OpcoesEntrega{
+				Periodo:   "2025-02",
+				OutputDir: dir,
+				Remetente: "relatorios@example.org",
+				Reenviar:  tt.reenviar,
+			})
+			if err != nil {
+				t.Fatalf("Entregar: %v", err)
+			}
+
+			porSetor := make(map[string]EntregaSetor)
+			for _, setor := range resultado.Setores {
+				porSetor[setor.Setor] = setor
+			}
+			if situacao := porSetor["Setor 2"].Situacao; situacao != EntregaSemContato {
+				t.Errorf("Setor 2 = %s, esperado %s", situacao, EntregaSemContato)
+			}
+			entregue := porSetor["Setor 1"]
+			if entregue.Situacao != tt.situacao {
+				t.Errorf("Setor 1 = %s (%v), esperado %s", entregue.Situacao, entregue.Err, tt.situacao)
+			}
+			var anexos []string
+			for _, anexo := range entregue.Anexos {
+				anexos = append(anexos, relativo(dir, anexo))
+			}
+			if tt.situacao == EntregaEnviada && !reflect.DeepEqual(anexos, tt.anexos) {
+				t.Errorf("anexos = %v, esperado %v", anexos, tt.anexos)
+			}
+
+			enviadas := 0
+			if tt.situacao == EntregaEnviada {
+				enviadas = 1
+			}
+			if len(*emails) != enviadas {
+				t.Fatalf("%d mensagens enviadas, esperado %d", len(*emails), enviadas)
+			}
+			if enviadas == 1 {
+				mensagem := (*emails)[0]
+				if mensagem.Assunto != "Relatórios do Setor 1 - 2025-02" || !strings.Contains(mensagem.Corpo, "poucos voluntários") {
+					t.Errorf("mensagem = %q\n%s", mensagem.Assunto, mensagem.Corpo)
+				}
+				if envio, _ := envios.Get("Setor 1", "2025-02"); envio == nil || envio.SHA256 == "" {
+					t.Error("envio não registrado")
+				}
+			}
+		})
+	}
+}
+
+func TestEntregarOpcoesInvalidas(t *testing.T) {
+	tests := []struct {
+		nome   string
+		opcoes OpcoesEntrega
+		erro   string
+	}{
+		{"remetente inválido", OpcoesEntrega{Periodo: "2025-02", Remetente: "relatorios"}, "remetente inválido"},
+		{"modelo inválido", OpcoesEntrega{Periodo: "2025-02", Remetente: "a@example.org", Modelo: "{{.Setor"}, "erro ao ler modelo"},
+		{"período inválido", OpcoesEntrega{Periodo: "2025", Remetente: "a@example.org"}, ErrPeriodoInvalido.Error()},
+		{"período sem histórico", OpcoesEntrega{Periodo: "2024-01", Remetente: "a@example.org"}, ErrPeriodoNaoEncontrado.Error()},
+	}
+
+	entrega := NewEntrega(setorRepoMemoria{}, historicoRepoMemoria{}, envioRepoMemoria{}, &manifestMemoria{}, &emailMemoria{}, loggerTeste)
+	for _, tt := range tests {
+		t.Run(tt.nome, func(t *testing.T) {
+			_, err := entrega.Entregar(context.Background(), tt.opcoes)
+			if err == nil || !strings.Contains(err.Error(), tt.erro) {
+				t.Errorf("err = %v, esperado %q", err, tt.erro)
+			}
+		})
+	}
+}
@@ -0,0 +1,151 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"report/internal/domain"
+)
+
+// documento representa um arquivo a ser gerado pelo pool de workers
+type documento struct {
+	Nome    string
+	Caminho string
+	// Chave identifica as entradas do documento; vazia, o documento é
+	// sempre gerado
+	Chave string
+	gerar func() error
+}
+
+// FalhaDocumento registra um documento que não pôde ser gerado
+type FalhaDocumento struct {
+	Nome    string
+	Caminho string
+	Err     error
+}
+
+// Resultado resume uma execução do gerador de relatórios
+type Resultado struct {
+	Leitura domain.EstatisticasLeitura
+	Gerados []string
+	// Mantidos são os documentos reaproveitados da execução anterior
+	Mantidos []string
+	Pacotes  []string
+	Falhas   []FalhaDocumento
+	Duracao  time.Duration
+}
+
+// Progresso é notificado a cada documento concluído, com ou sem sucesso
+type Progresso func(concluidos, total int, caminho string)
+
+// Err agrega as falhas da execução em um único erro
+func (r *Resultado) Err() error {
+	if len(r.Falhas) == 0 {
+		return nil
+	}
+	errs := make([]error, 0, len(r.Falhas))
+	for _, f := range r.Falhas {
+		errs = append(errs, fmt.Errorf("%s: %w", f.Nome, f.Err))
+	}
+	return errors.Join(errs...)
+}
+
+// Documentos retorna os documentos gerados e os mantidos da execução anterior
+func (r *Resultado) Documentos() []string {
+	documentos := append(append([]string(nil), r.Gerados...), r.Mantidos...)
+	sort.Strings(documentos)
+	return documentos
+}
+
+// String formata o resumo da execução para exibição no console
+func (r *Resultado) String() string {
+	var b strings.Builder
+	if r.Leitura.Codificacao != "" {
+		fmt.Fprintf(&b, "Codificação da listagem: %s, delimitador %q\n", r.Leitura.Codificacao, r.Leitura.Delimitador)
+	}
+	fmt.Fprintf(&b, "Linhas lidas: %d\n", r.Leitura.LinhasLidas)
+	fmt.Fprintf(&b, "Linhas válidas: %d\n", r.Leitura.LinhasValidas())
+	fmt.Fprintf(&b, "Linhas ignoradas: %d\n", r.Leitura.TotalIgnoradas())
+	motivos := make([]string, 0, len(r.Leitura.Ignoradas))
+	for motivo := range r.Leitura.Ignoradas {
+		motivos = append(motivos, motivo)
+	}
+	sort.Strings(motivos)
+	for _, motivo := range motivos {
+		fmt.Fprintf(&b, "  - %s: %d\n", motivo, r.Leitura.Ignoradas[motivo])
+	}
+	fmt.Fprintf(&b, "Documentos gerados: %d\n", len(r.Gerados))
+	if len(r.Mantidos) > 0 {
+		fmt.Fprintf(&b, "Documentos sem alteração: %d\n", len(r.Mantidos))
+	}
+	if len(r.Pacotes) > 0 {
+		fmt.Fprintf(&b, "Pacotes gerados: %d\n", len(r.Pacotes))
+	}
+	fmt.Fprintf(&b, "Documentos com falha: %d\n", len(r.Falhas))
+	for _, f := range r.Falhas {
+		fmt.Fprintf(&b, "  - %s: %v\n", f.Nome, f.Err)
+	}
+	fmt.Fprintf(&b, "Tempo total: %s\n", r.Duracao.Round(time.Millisecond))
+	return b.String()
+}
+
+// gerarDocumentos executa a geração dos documentos com no máximo g.workers
+// goroutines simultâneas. Falhas individuais não interrompem os demais
+// documentos; com o contexto cancelado, os documentos restantes são
+// registrados como falha.
+func (g *ReportGenerator) gerarDocumentos(ctx context.Context, documentos []documento) *Resultado {
+	workers := g.workers
+	if workers < 1 {
+		workers = 1
+	}
+
+	resultado := &Resultado{}
+	var mu sync.Mutex
+	registrar := func(doc documento, err error) {
+		mu.Lock()
+		defer mu.Unlock()
+		if err != nil {
+			g.logger.Error("falha ao gerar documento", "documento", doc.Nome, "arquivo", doc.Caminho, "erro", err)
+			resultado.Falhas = append(resultado.Falhas, FalhaDocumento{Nome: doc.Nome, Caminho: doc.Caminho, Err: err})
+		} else {
+			resultado.Gerados = append(resultado.Gerados, doc.Caminho)
+		}
+		if g.progresso != nil {
+			g.progresso(len(resultado.Gerados)+len(resultado.Falhas), len(documentos), doc.Caminho)
+		}
+	}
+
+	fila := make(chan documento)
+	var wg sync.WaitGroup
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for doc := range fila {
+				if err := ctx.Err(); err != nil {
+					registrar(doc, err)
+					continue
+				}
+				registrar(doc, doc.gerar())
+			}
+		}()
+	}
+
+	for _, doc := range documentos {
+		fila <- doc
+	}
+	close(fila)
+	wg.Wait()
+
+	sort.Strings(resultado.Gerados)
+	sort.Slice(resultado.Falhas, func(i, j int) bool {
+		return resultado.Falhas[i].Nome < resultado.Falhas[j].Nome
+	})
+
+	return resultado
+}
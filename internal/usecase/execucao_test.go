@@ -0,0 +1,182 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"report/internal/domain"
+)
+
+// documentosTeste cria n documentos que registram a concorrência máxima
+// atingida; os índices em falhas retornam erro
+func documentosTeste(n int, falhas map[int]bool, ativos, maximo *int32) []documento {
+	documentos := make([]documento, n)
+	for i := range documentos {
+		i := i
+		documentos[i] = documento{
+			Nome:    fmt.Sprintf("doc%02d", i),
+			Caminho: fmt.Sprintf("saida/doc%02d.pdf", i),
+			gerar: func() error {
+				atual := atomic.AddInt32(ativos, 1)
+				defer atomic.AddInt32(ativos, -1)
+				for {
+					anterior := atomic.LoadInt32(maximo)
+					if atual <= anterior || atomic.CompareAndSwapInt32(maximo, anterior, atual) {
+						break
+					}
+				}
+				time.Sleep(time.Millisecond)
+				if falhas[i] {
+					return errors.New("falha simulada")
+				}
+				return nil
+			},
+		}
+	}
+	return documentos
+}
+
+func TestGerarDocumentos(t *testing.T) {
+	tests := []struct {
+		nome      string
+		workers   int
+		total     int
+		falhas    map[int]bool
+		cancelado bool
+		gerados   int
+		nomesErro []string
+	}{
+		{nome: "sequencial", workers: 1, total: 5, gerados: 5},
+		{nome: "workers inválidos usam um", workers: 0, total: 3, gerados: 3},
+		{nome: "paralelo", workers: 4, total: 20, gerados: 20},
+		{nome: "falhas não interrompem os demais", workers: 3, total: 10, falhas: map[int]bool{7: true, 2: true}, gerados: 8, nomesErro: []string{"doc02", "doc07"}},
+		{nome: "contexto cancelado", workers: 2, total: 3, cancelado: true, nomesErro: []string{"doc00", "doc01", "doc02"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.nome, func(t *testing.T) {
+			var ativos, maximo int32
+			g := NewReportGenerator(nil, nil, nil, nil, WithWorkers(tt.workers), WithLogger(loggerTeste))
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+			if tt.cancelado {
+				cancel()
+			}
+
+			resultado := g.gerarDocumentos(ctx, documentosTeste(tt.total, tt.falhas, &ativos, &maximo))
+
+			if len(resultado.Gerados) != tt.gerados {
+				t.Errorf("%d documentos gerados, esperado %d", len(resultado.Gerados), tt.gerados)
+			}
+			var nomesErro []string
+			for _, f := range resultado.Falhas {
+				nomesErro = append(nomesErro, f.Nome)
+				if tt.cancelado && !errors.Is(f.Err, context.Canceled) {
+					t.Errorf("falha de %s = %v, esperado context.Canceled", f.Nome, f.Err)
+				}
+			}
+			if !reflect.DeepEqual(nomesErro, tt.nomesErro) {
+				t.Errorf("falhas = %v, esperado %v", nomesErro, tt.nomesErro)
+			}
+			if (resultado.Err() != nil) != (len(tt.nomesErro) > 0) {
+				t.Errorf("Err() = %v com %d falhas", resultado.Err(), len(tt.nomesErro))
+			}
+			limite := int32(max(tt.workers, 1))
+			if maximo > limite {
+				t.Errorf("%d documentos simultâneos, limite de %d", maximo, limite)
+			}
+		})
+	}
+}
+
+func TestGerarDocumentosProgresso(t *testing.T) {
+	var mu sync.Mutex
+	var concluidos []int
+	progresso := func(n, total int, caminho string) {
+		mu.Lock()
+		defer mu.Unlock()
+		if total != 6 {
+			t.Errorf("total = %d, esperado 6", total)
+		}
+		concluidos = append(concluidos, n)
+	}
+	var ativos, maximo int32
+	g := NewReportGenerator(nil, nil, nil, nil, WithWorkers(3), WithProgresso(progresso), WithLogger(loggerTeste))
+
+	g.gerarDocumentos(context.Background(), documentosTeste(6, map[int]bool{4: true}, &ativos, &maximo))
+
+	if !reflect.DeepEqual(concluidos, []int{1, 2, 3, 4, 5, 6}) {
+		t.Errorf("progresso = %v, esperado uma notificação por documento", concluidos)
+	}
+}
+
+func TestResultadoString(t *testing.T) {
+	tests := []struct {
+		nome      string
+		resultado Resultado
+		contem    []string
+		ausentes  []string
+	}{
+		{
+			nome: "leitura com linhas ignoradas",
+			resultado: Resultado{
+				Leitura: domain.EstatisticasLeitura{
+					LinhasLidas: 10,
+					Ignoradas:   map[string]int{"sem livro": 2, "data inválida": 1},
+					Codificacao: "UTF-8",
+					Delimitador: ";",
+				},
+				Gerados: []string{"a.pdf", "b.pdf"},
+				Duracao: 1500 * time.Millisecond,
+			},
+			contem: []string{
+				"Codificação da listagem: UTF-8, delimitador \";\"\n",
+				"Linhas lidas: 10\n",
+				"Linhas válidas: 7\n",
+				"Linhas ignoradas: 3\n  - data inválida: 1\n  - sem livro: 2\n",
+				"Documentos gerados: 2\n",
+				"Documentos com falha: 0\n",
+				"Tempo total: 1.5s\n",
+			},
+			ausentes: []string{"Documentos sem alteração", "Pacotes gerados"},
+		},
+		{
+			nome: "documentos mantidos, pacotes e falhas",
+			resultado: Resultado{
+				Gerados:  []string{"a.pdf"},
+				Mantidos: []string{"b.pdf", "c.pdf"},
+				Pacotes:  []string{"setor.zip"},
+				Falhas:   []FalhaDocumento{{Nome: "Relatório X", Err: errors.New("disco cheio")}},
+			},
+			contem: []string{
+				"Documentos sem alteração: 2\n",
+				"Pacotes gerados: 1\n",
+				"Documentos com falha: 1\n  - Relatório X: disco cheio\n",
+			},
+			ausentes: []string{"Codificação da listagem"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.nome, func(t *testing.T) {
+			texto := tt.resultado.String()
+			for _, trecho := range tt.contem {
+				if !strings.Contains(texto, trecho) {
+					t.Errorf("resumo sem %q:\n%s", trecho, texto)
+				}
+			}
+			for _, trecho := range tt.ausentes {
+				if strings.Contains(texto, trecho) {
+					t.Errorf("resumo com %q:\n%s", trecho, texto)
+				}
+			}
+		})
+	}
+}
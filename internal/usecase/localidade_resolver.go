@@ -0,0 +1,185 @@
+package usecase
+
+import (
+	"sort"
+	"strings"
+	"unicode/utf8"
+
+	"report/internal/domain"
+)
+
+const (
+	// similaridadeMinima é o limiar a partir do qual uma localidade cadastrada é sugerida
+	similaridadeMinima = 0.75
+	// maxSugestoes limita o número de sugestões por nome não reconhecido
+	maxSugestoes = 3
+)
+
+// MetodoResolucao indica como um nome de localidade foi reconhecido
+type MetodoResolucao string
+
+const (
+	ResolucaoExata      MetodoResolucao = "exata"
+	ResolucaoAlias      MetodoResolucao = "alias"
+	ResolucaoPendente   MetodoResolucao = "pendente"
+	ResolucaoNenhuma    MetodoResolucao = "nenhuma"
+	ResolucaoConfirmada MetodoResolucao = "confirmada"
+)
+
+// Resolucao descreve o resultado da resolução de um nome de localidade
+type Resolucao struct {
+	Original   string
+	Localidade string
+	Metodo     MetodoResolucao
+	Sugestoes  []domain.SugestaoAlias
+}
+
+// LocalidadeResolver associa os nomes encontrados na listagem às localidades
+// cadastradas, usando correspondência exata, tabela de apelidos e similaridade
+type LocalidadeResolver struct {
+	conhecidas map[string]string
+	aliases    map[string]string
+}
+
+// NewLocalidadeResolver cria um resolvedor a partir das localidades cadastradas e dos apelidos
+func NewLocalidadeResolver(conhecidas []string, aliases map[string]string) *LocalidadeResolver {
+	r := &LocalidadeResolver{
+		conhecidas: make(map[string]string, len(conhecidas)),
+		aliases:    make(map[string]string, len(aliases)),
+	}
+	for _, localidade := range conhecidas {
+		r.conhecidas[normalizarNome(localidade)] = localidade
+	}
+	for alias, localidade := range aliases {
+		r.aliases[normalizarNome(alias)] = localidade
+	}
+	return r
+}
+
+// AddAlias registra um apelido confirmado durante a execução
+func (r *LocalidadeResolver) AddAlias(alias, localidade string) {
+	r.aliases[normalizarNome(alias)] = localidade
+}
+
+// Resolve retorna a localidade cadastrada correspondente ao nome informado.
+// Nomes sem correspondência exata ou por apelido permanecem inalterados e
+// recebem sugestões ordenadas por similaridade.
+func (r *LocalidadeResolver) Resolve(nome string) Resolucao {
+	chave := normalizarNome(nome)
+
+	if localidade, exists := r.conhecidas[chave]; exists {
+		return Resolucao{Original: nome, Localidade: localidade, Metodo: ResolucaoExata}
+	}
+	if localidade, exists := r.aliases[chave]; exists {
+		return Resolucao{Original: nome, Localidade: localidade, Metodo: ResolucaoAlias}
+	}
+
+	resolucao := Resolucao{Original: nome, Localidade: nome, Metodo: ResolucaoNenhuma}
+	for normalizada, localidade := range r.conhecidas {
+		similaridade := similaridadeNomes(chave, normalizada)
+		if similaridade >= similaridadeMinima {
+			resolucao.Sugestoes = append(resolucao.Sugestoes, domain.SugestaoAlias{
+				Alias:        chave,
+				Localidade:   localidade,
+				Similaridade: similaridade,
+			})
+		}
+	}
+
+	sort.Slice(resolucao.Sugestoes, func(i, j int) bool {
+		if resolucao.Sugestoes[i].Similaridade != resolucao.Sugestoes[j].Similaridade {
+			return resolucao.Sugestoes[i].Similaridade > resolucao.Sugestoes[j].Similaridade
+		}
+		return resolucao.Sugestoes[i].Localidade < resolucao.Sugestoes[j].Localidade
+	})
+	if len(resolucao.Sugestoes) > maxSugestoes {
+		resolucao.Sugestoes = resolucao.Sugestoes[:maxSugestoes]
+	}
+	if len(resolucao.Sugestoes) > 0 {
+		resolucao.Metodo = ResolucaoPendente
+	}
+
+	return resolucao
+}
+
+func normalizarNome(nome string) string {
+	return strings.ToUpper(strings.Join(strings.Fields(nome), " "))
+}
+
+// similaridadeNomes combina a distância de Levenshtein normalizada com a
+// similaridade entre os conjuntos de palavras, retornando um valor entre 0 e 1
+func similaridadeNomes(a, b string) float64 {
+	lev := similaridadeLevenshtein(a, b)
+	tokens := similaridadeTokens(a, b)
+	if tokens > lev {
+		return tokens
+	}
+	return lev
+}
+
+func similaridadeLevenshtein(a, b string) float64 {
+	maior := utf8.RuneCountInString(a)
+	if n := utf8.RuneCountInString(b); n > maior {
+		maior = n
+	}
+	if maior == 0 {
+		return 1
+	}
+	return 1 - float64(levenshtein(a, b))/float64(maior)
+}
+
+func levenshtein(a, b string) int {
+	ra, rb := []rune(a), []rune(b)
+	anterior := make([]int, len(rb)+1)
+	atual := make([]int, len(rb)+1)
+	for j := range anterior {
+		anterior[j] = j
+	}
+
+	for i := 1; i <= len(ra); i++ {
+		atual[0] = i
+		for j := 1; j <= len(rb); j++ {
+			custo := 1
+			if ra[i-1] == rb[j-1] {
+				custo = 0
+			}
+			atual[j] = min(anterior[j]+1, atual[j-1]+1, anterior[j-1]+custo)
+		}
+		anterior, atual = atual, anterior
+	}
+
+	return anterior[len(rb)]
+}
+
+// similaridadeTokens compara as palavras dos nomes, tolerando pequenas
+// diferenças de grafia em cada palavra (ex.: CHACARA x CHACARAS)
+func similaridadeTokens(a, b string) float64 {
+	ta, tb := strings.Fields(a), strings.Fields(b)
+	if len(ta) == 0 || len(tb) == 0 {
+		return 0
+	}
+
+	usados := make([]bool, len(tb))
+	var soma float64
+	for _, pa := range ta {
+		melhor, indice := 0.0, -1
+		for j, pb := range tb {
+			if usados[j] {
+				continue
+			}
+			if s := similaridadeLevenshtein(pa, pb); s > melhor {
+				melhor, indice = s, j
+			}
+		}
+		if indice >= 0 && melhor >= similaridadeMinima {
+			usados[indice] = true
+			soma += melhor
+		}
+	}
+
+	total := len(ta)
+	if len(tb) > total {
+		total = len(tb)
+	}
+	return soma / float64(total)
+}
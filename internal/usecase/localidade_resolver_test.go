@@ -0,0 +1,118 @@
+package usecase
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestLocalidadeResolverResolve(t *testing.T) {
+	conhecidas := []string{"VILA NOVA", "VILA NOVA II", "CHACARAS REUNIDAS", "JARDIM DAS FLORES", "CENTRO"}
+	aliases := map[string]string{"V. NOVA": "VILA NOVA"}
+
+	tests := []struct {
+		nome       string
+		metodo     MetodoResolucao
+		localidade string
+		sugestoes  []string
+	}{
+		{"VILA NOVA", ResolucaoExata, "VILA NOVA", nil},
+		{"  vila   nova ", ResolucaoExata, "VILA NOVA", nil},
+		{"v. nova", ResolucaoAlias, "VILA NOVA", nil},
+		{"CHACARA REUNIDAS", ResolucaoPendente, "CHACARA REUNIDAS", []string{"CHACARAS REUNIDAS"}},
+		{"VILA NOVAS", ResolucaoPendente, "VILA NOVAS", []string{"VILA NOVA", "VILA NOVA II"}},
+		{"PARQUE INDUSTRIAL", ResolucaoNenhuma, "PARQUE INDUSTRIAL", nil},
+	}
+
+	resolver := NewLocalidadeResolver(conhecidas, aliases)
+	for _, tt := range tests {
+		t.Run(tt.nome, func(t *testing.T) {
+			resolucao := resolver.Resolve(tt.nome)
+			if resolucao.Metodo != tt.metodo {
+				t.Errorf("Metodo = %s, esperado %s", resolucao.Metodo, tt.metodo)
+			}
+			if resolucao.Localidade != tt.localidade {
+				t.Errorf("Localidade = %q, esperado %q", resolucao.Localidade, tt.localidade)
+			}
+			var sugestoes []string
+			for _, s := range resolucao.Sugestoes {
+				sugestoes = append(sugestoes, s.Localidade)
+				if s.Similaridade < similaridadeMinima {
+					t.Errorf("sugestão %s com similaridade %.2f abaixo do mínimo", s.Localidade, s.Similaridade)
+				}
+			}
+			if !reflect.DeepEqual(sugestoes, tt.sugestoes) {
+				t.Errorf("Sugestoes = %v, esperado %v", sugestoes, tt.sugestoes)
+			}
+		})
+	}
+}
+
+func TestLocalidadeResolverLimitaSugestoes(t *testing.T) {
+	resolver := NewLocalidadeResolver([]string{"VILA A", "VILA B", "VILA C", "VILA D", "VILA E"}, nil)
+
+	resolucao := resolver.Resolve("VILA X")
+	if resolucao.Metodo != ResolucaoPendente {
+		t.Fatalf("Metodo = %s, esperado %s", resolucao.Metodo, ResolucaoPendente)
+	}
+	if len(resolucao.Sugestoes) != maxSugestoes {
+		t.Fatalf("%d sugestões, esperado %d", len(resolucao.Sugestoes), maxSugestoes)
+	}
+	// Com a mesma similaridade, as sugestões seguem a ordem alfabética
+	for i, esperada := range []string{"VILA A", "VILA B", "VILA C"} {
+		if resolucao.Sugestoes[i].Localidade != esperada {
+			t.Errorf("Sugestoes[%d] = %s, esperado %s", i, resolucao.Sugestoes[i].Localidade, esperada)
+		}
+	}
+}
+
+func TestLocalidadeResolverAddAlias(t *testing.T) {
+	resolver := NewLocalidadeResolver([]string{"VILA NOVA"}, nil)
+	resolver.AddAlias("vl nova", "VILA NOVA")
+
+	resolucao := resolver.Resolve("VL NOVA")
+	if resolucao.Metodo != ResolucaoAlias || resolucao.Localidade != "VILA NOVA" {
+		t.Errorf("Resolve = %s/%s, esperado %s/VILA NOVA", resolucao.Metodo, resolucao.Localidade, ResolucaoAlias)
+	}
+}
+
+func TestSimilaridadeNomes(t *testing.T) {
+	tests := []struct {
+		a, b   string
+		minimo float64
+		maximo float64
+	}{
+		{"VILA NOVA", "VILA NOVA", 1, 1},
+		{"", "", 1, 1},
+		{"CHACARA REUNIDAS", "CHACARAS REUNIDAS", 0.9, 1},
+		{"NOVA VILA", "VILA NOVA", 1, 1},
+		{"CENTRO", "PARQUE INDUSTRIAL", 0, 0.5},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.a+"/"+tt.b, func(t *testing.T) {
+			s := similaridadeNomes(tt.a, tt.b)
+			if s < tt.minimo || s > tt.maximo {
+				t.Errorf("similaridadeNomes = %.3f, esperado entre %.2f e %.2f", s, tt.minimo, tt.maximo)
+			}
+		})
+	}
+}
+
+func TestLevenshtein(t *testing.T) {
+	tests := []struct {
+		a, b      string
+		distancia int
+	}{
+		{"", "", 0},
+		{"CASA", "", 4},
+		{"CASA", "CASAS", 1},
+		{"SÃO", "SAO", 1},
+		{"KITTEN", "SITTING", 3},
+	}
+
+	for _, tt := range tests {
+		if d := levenshtein(tt.a, tt.b); d != tt.distancia {
+			t.Errorf("levenshtein(%q, %q) = %d, esperado %d", tt.a, tt.b, d, tt.distancia)
+		}
+	}
+}
@@ -0,0 +1,182 @@
+package usecase
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+// ManifestFileName é o nome do manifesto gravado no diretório de saída
+const ManifestFileName = "manifest.json"
+
+// ArquivoManifest descreve um arquivo de entrada ou um documento gerado
+type ArquivoManifest struct {
+	Caminho string `json:"caminho"`
+	SHA256  string `json:"sha256"`
+	Tamanho int64  `json:"tamanho"`
+	Paginas int    `json:"paginas,omitempty"`
+	// Chave identifica as entradas a partir das quais o documento foi gerado
+	Chave string `json:"chave,omitempty"`
+}
+
+// Manifest registra o que foi gerado em uma execução e a partir de quais entradas
+type Manifest struct {
+	Versao     string            `json:"versao"`
+	GeradoEm   time.Time         `json:"gerado_em"`
+	Periodo    string            `json:"periodo"`
+	Config     map[string]string `json:"config"`
+	Entradas   []ArquivoManifest `json:"entradas"`
+	Documentos []ArquivoManifest `json:"documentos"`
+	Alertas    map[string]int    `json:"alertas"`
+}
+
+// ManifestInfo contém as informações da execução que não são conhecidas pelo gerador
+type ManifestInfo struct {
+	Versao   string
+	Entradas []string
+	Config   map[string]string
+}
+
+// ManifestService define as operações de leitura e escrita de manifestos
+type ManifestService interface {
+	Inspect(path string) (ArquivoManifest, error)
+	Save(manifest *Manifest, path string) error
+	Load(path string) (*Manifest, error)
+}
+
+// writeManifest grava o manifesto da execução no diretório de saída
+func (g *ReportGenerator) writeManifest(resultado *Resultado, alertas map[string]int, chaves map[string]string) error {
+	manifest := &Manifest{
+		Versao:   g.manifestInfo.Versao,
+		GeradoEm: time.Now(),
+		Periodo:  g.periodo,
+		Config:   g.manifestConfig(),
+		Alertas:  alertas,
+	}
+
+	for _, entrada := range g.manifestInfo.Entradas {
+		arquivo, err := g.manifestService.Inspect(entrada)
+		if err != nil {
+			return fmt.Errorf("erro ao inspecionar entrada %s: %v", entrada, err)
+		}
+		manifest.Entradas = append(manifest.Entradas, arquivo)
+	}
+
+	for _, caminho := range resultado.Documentos() {
+		arquivo, err := g.manifestService.Inspect(caminho)
+		if err != nil {
+			return fmt.Errorf("erro ao inspecionar documento %s: %v", caminho, err)
+		}
+		arquivo.Caminho = relativo(g.outputDir, caminho)
+		arquivo.Chave = chaves[caminho]
+		manifest.Documentos = append(manifest.Documentos, arquivo)
+	}
+
+	return g.manifestService.Save(manifest, filepath.Join(g.outputDir, ManifestFileName))
+}
+
+func (g *ReportGenerator) manifestConfig() map[string]string {
+	config := map[string]string{
+		"output":  g.outputDir,
+		"workers": fmt.Sprintf("%d", g.workers),
+	}
+	for chave, valor := range g.manifestInfo.Config {
+		config[chave] = valor
+	}
+	return config
+}
+
+// Verificacao descreve as divergências entre um diretório de saída e seu manifesto
+type Verificacao struct {
+	Verificados int
+	Ausentes    []string
+	Alterados   []string
+	NaoListados []string
+}
+
+// OK indica se o diretório corresponde exatamente ao manifesto
+func (v *Verificacao) OK() bool {
+	return len(v.Ausentes) == 0 && len(v.Alterados) == 0 && len(v.NaoListados) == 0
+}
+
+// String formata a verificação para exibição no console
+func (v *Verificacao) String() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "Documentos verificados: %d\n", v.Verificados)
+	for _, caminho := range v.Ausentes {
+		fmt.Fprintf(&b, "  ausente: %s\n", caminho)
+	}
+	for _, caminho := range v.Alterados {
+		fmt.Fprintf(&b, "  alterado: %s\n", caminho)
+	}
+	for _, caminho := range v.NaoListados {
+		fmt.Fprintf(&b, "  não listado no manifesto: %s\n", caminho)
+	}
+	return b.String()
+}
+
+// ManifestVerifier define o caso de uso de verificação de um diretório de saída
+type ManifestVerifier struct {
+	manifestService ManifestService
+}
+
+// NewManifestVerifier cria uma nova instância de ManifestVerifier
+func NewManifestVerifier(manifestService ManifestService) *ManifestVerifier {
+	return &ManifestVerifier{manifestService: manifestService}
+}
+
+// Verify confere os documentos de outputDir com o manifesto gravado nele
+func (v *ManifestVerifier) Verify(outputDir string) (*Verificacao, error) {
+	manifest, err := v.manifestService.Load(filepath.Join(outputDir, ManifestFileName))
+	if err != nil {
+		return nil, fmt.Errorf("erro ao ler manifesto: %v", err)
+	}
+
+	verificacao := &Verificacao{}
+	listados := make(map[string]bool, len(manifest.Documentos))
+	for _, esperado := range manifest.Documentos {
+		listados[filepath.ToSlash(esperado.Caminho)] = true
+		verificacao.Verificados++
+
+		atual, err := v.manifestService.Inspect(filepath.Join(outputDir, esperado.Caminho))
+		if os.IsNotExist(err) {
+			verificacao.Ausentes = append(verificacao.Ausentes, esperado.Caminho)
+			continue
+		}
+		if err != nil {
+			return nil, fmt.Errorf("erro ao inspecionar %s: %v", esperado.Caminho, err)
+		}
+		if atual.SHA256 != esperado.SHA256 || atual.Paginas != esperado.Paginas {
+			verificacao.Alterados = append(verificacao.Alterados, esperado.Caminho)
+		}
+	}
+
+	err = filepath.WalkDir(outputDir, func(path string, d os.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() || !strings.EqualFold(filepath.Ext(path), ".pdf") {
+			return nil
+		}
+		if caminho := relativo(outputDir, path); !listados[caminho] {
+			verificacao.NaoListados = append(verificacao.NaoListados, caminho)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("erro ao percorrer %s: %v", outputDir, err)
+	}
+	sort.Strings(verificacao.NaoListados)
+
+	return verificacao, nil
+}
+
+func relativo(base, path string) string {
+	if rel, err := filepath.Rel(base, path); err == nil {
+		return filepath.ToSlash(rel)
+	}
+	return filepath.ToSlash(path)
+}
@@ -0,0 +1,155 @@
+package usecase
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+// manifestMemoria implementa ManifestService guardando os manifestos em
+// memória e lendo os arquivos do disco
+type manifestMemoria struct {
+	manifestos map[string]*Manifest
+}
+
+func (s *manifestMemoria) Inspect(path string) (ArquivoManifest, error) {
+	conteudo, err := os.ReadFile(path)
+	if err != nil {
+		return ArquivoManifest{}, err
+	}
+	soma := sha256.Sum256(conteudo)
+	return ArquivoManifest{Caminho: path, SHA256: hex.EncodeToString(soma[:]), Tamanho: int64(len(conteudo))}, nil
+}
+
+func (s *manifestMemoria) Save(manifest *Manifest, path string) error {
+	if s.manifestos == nil {
+		s.manifestos = make(map[string]*Manifest)
+	}
+	s.manifestos[path] = manifest
+	return nil
+}
+
+func (s *manifestMemoria) Load(path string) (*Manifest, error) {
+	manifest, exists := s.manifestos[path]
+	if !exists {
+		return nil, os.ErrNotExist
+	}
+	return manifest, nil
+}
+
+func gravarArquivo(t *testing.T, path, conteudo string) {
+	t.Helper()
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, []byte(conteudo), 0o644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestManifestVerifierVerify(t *testing.T) {
+	tests := []struct {
+		nome     string
+		alterar  func(t *testing.T, dir string)
+		ok       bool
+		esperada Verificacao
+	}{
+		{
+			nome:     "diretório inalterado",
+			alterar:  func(t *testing.T, dir string) {},
+			ok:       true,
+			esperada: Verificacao{Verificados: 2},
+		},
+		{
+			nome: "documento removido",
+			alterar: func(t *testing.T, dir string) {
+				os.Remove(filepath.Join(dir, "SETOR 1", "VILA NOVA.pdf"))
+			},
+			esperada: Verificacao{Verificados: 2, Ausentes: []string{"SETOR 1/VILA NOVA.pdf"}},
+		},
+		{
+			nome: "documento alterado",
+			alterar: func(t *testing.T, dir string) {
+				gravarArquivo(t, filepath.Join(dir, "resumo.pdf"), "outro conteúdo")
+			},
+			esperada: Verificacao{Verificados: 2, Alterados: []string{"resumo.pdf"}},
+		},
+		{
+			nome: "PDF fora do manifesto",
+			alterar: func(t *testing.T, dir string) {
+				gravarArquivo(t, filepath.Join(dir, "SETOR 1", "extra.pdf"), "extra")
+				gravarArquivo(t, filepath.Join(dir, "notas.txt"), "não é PDF")
+			},
+			esperada: Verificacao{Verificados: 2, NaoListados: []string{"SETOR 1/extra.pdf"}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.nome, func(t *testing.T) {
+			dir := t.TempDir()
+			gravarArquivo(t, filepath.Join(dir, "resumo.pdf"), "resumo")
+			gravarArquivo(t, filepath.Join(dir, "SETOR 1", "VILA NOVA.pdf"), "vila nova")
+			service := &manifestMemoria{}
+			g := NewReportGenerator(nil, nil, nil, nil, WithOutputDir(dir), WithPeriodo("2025-02"),
+				WithManifest(service, ManifestInfo{Versao: "teste"}))
+			resultado := &Resultado{Gerados: []string{filepath.Join(dir, "resumo.pdf")}, Mantidos: []string{filepath.Join(dir, "SETOR 1", "VILA NOVA.pdf")}}
+			if err := g.writeManifest(resultado, map[string]int{"VILA NOVA": 1}, map[string]string{filepath.Join(dir, "resumo.pdf"): "abc"}); err != nil {
+				t.Fatalf("writeManifest: %v", err)
+			}
+
+			tt.alterar(t, dir)
+			verificacao, err := NewManifestVerifier(service).Verify(dir)
+			if err != nil {
+				t.Fatalf("Verify: %v", err)
+			}
+			if verificacao.OK() != tt.ok {
+				t.Errorf("OK() = %v, esperado %v", verificacao.OK(), tt.ok)
+			}
+			if !reflect.DeepEqual(*verificacao, tt.esperada) {
+				t.Errorf("Verify = %+v, esperado %+v", *verificacao, tt.esperada)
+			}
+		})
+	}
+}
+
+func TestWriteManifest(t *testing.T) {
+	dir := t.TempDir()
+	entrada := filepath.Join(t.TempDir(), "listagem.csv")
+	gravarArquivo(t, entrada, "dados")
+	gravarArquivo(t, filepath.Join(dir, "SETOR 1", "VILA NOVA.pdf"), "vila nova")
+	service := &manifestMemoria{}
+	g := NewReportGenerator(nil, nil, nil, nil, WithOutputDir(dir), WithPeriodo("2025-02"), WithWorkers(2),
+		WithManifest(service, ManifestInfo{Versao: "1.2.3", Entradas: []string{entrada}, Config: map[string]string{"input": entrada}}))
+
+	caminho := filepath.Join(dir, "SETOR 1", "VILA NOVA.pdf")
+	if err := g.writeManifest(&Resultado{Gerados: []string{caminho}}, map[string]int{"VILA NOVA": 2}, map[string]string{caminho: "chave"}); err != nil {
+		t.Fatalf("writeManifest: %v", err)
+	}
+
+	manifest := service.manifestos[filepath.Join(dir, ManifestFileName)]
+	if manifest == nil {
+		t.Fatal("manifesto não gravado no diretório de saída")
+	}
+	if manifest.Versao != "1.2.3" || manifest.Periodo != "2025-02" {
+		t.Errorf("versão/período = %s/%s, esperado 1.2.3/2025-02", manifest.Versao, manifest.Periodo)
+	}
+	esperada := map[string]string{"input": entrada, "output": dir, "workers": "2"}
+	if !reflect.DeepEqual(manifest.Config, esperada) {
+		t.Errorf("Config = %v, esperado %v", manifest.Config, esperada)
+	}
+	if len(manifest.Entradas) != 1 || manifest.Entradas[0].Caminho != entrada {
+		t.Errorf("Entradas = %+v, esperado %s", manifest.Entradas, entrada)
+	}
+	if len(manifest.Documentos) != 1 {
+		t.Fatalf("%d documentos, esperado 1", len(manifest.Documentos))
+	}
+	if documento := manifest.Documentos[0]; documento.Caminho != "SETOR 1/VILA NOVA.pdf" || documento.Chave != "chave" {
+		t.Errorf("documento = %s (%s), esperado caminho relativo com a chave", documento.Caminho, documento.Chave)
+	}
+	if manifest.Alertas["VILA NOVA"] != 2 {
+		t.Errorf("Alertas = %v", manifest.Alertas)
+	}
+}
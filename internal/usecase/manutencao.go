@@ -0,0 +1,243 @@
+package usecase
+
+import (
+	"fmt"
+	"time"
+
+	"report/internal/domain"
+)
+
+// minimoDiasSemana é o número de dias abaixo do qual a semana que começa ou
+// termina no meio do período é somada à semana vizinha
+const minimoDiasSemana = 4
+
+// SituacaoJanela classifica uma semana, mês ou trimestre do plano de manutenção
+type SituacaoJanela string
+
+// Situações possíveis; janelas em andamento ou futuras não contam como falta
+const (
+	JanelaCumprida      SituacaoJanela = "cumprida"
+	JanelaSemManutencao SituacaoJanela = "sem manutenção"
+	JanelaEmAndamento   SituacaoJanela = "em andamento"
+	JanelaFutura        SituacaoJanela = "futura"
+)
+
+// JanelaManutencao é uma semana, mês ou trimestre em que a tarefa deve ser feita
+type JanelaManutencao struct {
+	Inicio      time.Time
+	Fim         time.Time
+	Lancamentos int
+	Situacao    SituacaoJanela
+}
+
+// TarefaPlano é uma tarefa do plano com a situação de cada janela do período
+type TarefaPlano struct {
+	Tarefa     string
+	Livro      string
+	Frequencia domain.Frequencia
+	Janelas    []JanelaManutencao
+}
+
+// PlanoLocalidade reúne o cumprimento do plano de manutenção de uma localidade
+type PlanoLocalidade struct {
+	// Semanas são as colunas da grade, do início ao fim do período
+	Semanas       []JanelaManutencao
+	Tarefas       []TarefaPlano
+	Cumpridas     int
+	SemManutencao int
+}
+
+// WithPlanoManutencao habilita a verificação do plano de manutenção
+// preventiva, que substitui o mínimo de manutenção nos alertas das
+// localidades com plano
+func WithPlanoManutencao(planoRepo domain.PlanoManutencaoRepository) Option {
+	return func(g *ReportGenerator) {
+		g.planoRepo = planoRepo
+	}
+}
+
+// avaliarPlanos verifica o plano de cada localidade. As tarefas de uma
+// localidade substituem as tarefas gerais, cadastradas sem localidade.
+func (g *ReportGenerator) avaliarPlanos(localidades map[string]map[string]*domain.Summary) (map[string]*PlanoLocalidade, error) {
+	tarefas, err := g.planoRepo.GetAll()
+	if err != nil {
+		return nil, fmt.Errorf("erro ao obter plano de manutenção: %v", err)
+	}
+	gerais := make([]domain.TarefaManutencao, 0, len(tarefas))
+	porLocalidade := make(map[string][]domain.TarefaManutencao)
+	for _, tarefa := range tarefas {
+		if tarefa.Localidade == "" {
+			gerais = append(gerais, tarefa)
+		} else {
+			porLocalidade[tarefa.Localidade] = append(porLocalidade[tarefa.Localidade], tarefa)
+		}
+	}
+
+	inicio, err := time.Parse(domain.LayoutPeriodo, g.periodo)
+	if err != nil {
+		return nil, fmt.Errorf("período inválido, use AAAA-MM: %s", g.periodo)
+	}
+	referencia := g.dataReferencia()
+	anteriores := g.carregarTrimestre(inicio)
+
+	planos := make(map[string]*PlanoLocalidade, len(localidades))
+	for localidade, livros := range localidades {
+		plano := porLocalidade[localidade]
+		if len(plano) == 0 {
+			plano = gerais
+		}
+		if len(plano) == 0 {
+			continue
+		}
+		var historico []map[string]*domain.Summary
+		for _, snapshot := range anteriores {
+			historico = append(historico, snapshot.Localidades[localidade])
+		}
+		planos[localidade] = avaliarPlano(plano, livros, historico, inicio, referencia)
+	}
+	return planos, nil
+}
+
+// carregarTrimestre retorna o histórico dos meses do trimestre anteriores ao
+// período, usado pelas tarefas trimestrais. Meses sem histórico são ignorados.
+func (g *ReportGenerator) carregarTrimestre(inicio time.Time) []*domain.Snapshot {
+	if g.historicoRepo == nil {
+		return nil
+	}
+	var snapshots []*domain.Snapshot
+	for mes := inicioTrimestre(inicio); mes.Before(inicio); mes = mes.AddDate(0, 1, 0) {
+		snapshot, err := g.historicoRepo.Get(mes.Format(domain.LayoutPeriodo))
+		if err != nil {
+			g.logger.Debug("histórico do trimestre indisponível", "periodo", mes.Format(domain.LayoutPeriodo), "erro", err)
+			continue
+		}
+		snapshots = append(snapshots, snapshot)
+	}
+	return snapshots
+}
+
+// avaliarPlano verifica cada tarefa nas janelas do período que começa em
+// inicio. Os apontamentos do dia vêm da listagem e, para os trimestres, do
+// histórico dos meses anteriores.
+func avaliarPlano(
+	tarefas []domain.TarefaManutencao,
+	livros map[string]*domain.Summary,
+	historico []map[string]*domain.Summary,
+	inicio, referencia time.Time,
+) *PlanoLocalidade {
+	fim := inicio.AddDate(0, 1, -1)
+	plano := &PlanoLocalidade{Semanas: semanasPeriodo(inicio, fim)}
+
+	for _, tarefa := range tarefas {
+		dias := make(map[string]int)
+		if summary, exists := livros[tarefa.Livro]; exists {
+			for dia, n := range summary.LancamentosDia {
+				dias[dia] = n
+			}
+		}
+
+		var janelas []JanelaManutencao
+		switch tarefa.Frequencia {
+		case domain.FrequenciaSemanal:
+			janelas = append(janelas, plano.Semanas...)
+		case domain.FrequenciaMensal:
+			janelas = []JanelaManutencao{{Inicio: inicio, Fim: fim}}
+		case domain.FrequenciaTrimestral:
+			for _, livrosMes := range historico {
+				if summary, exists := livrosMes[tarefa.Livro]; exists {
+					for dia, n := range summary.LancamentosDia {
+						// A listagem pode trazer o mesmo dia que o histórico
+						dias[dia] = max(dias[dia], n)
+					}
+				}
+			}
+			trimestre := inicioTrimestre(inicio)
+			janelas = []JanelaManutencao{{Inicio: trimestre, Fim: trimestre.AddDate(0, 3, -1)}}
+		}
+
+		for i := range janelas {
+			janela := &janelas[i]
+			for dia := janela.Inicio; !dia.After(janela.Fim); dia = dia.AddDate(0, 0, 1) {
+				janela.Lancamentos += dias[dia.Format(domain.LayoutDia)]
+			}
+			switch {
+			case janela.Lancamentos > 0:
+				janela.Situacao = JanelaCumprida
+				plano.Cumpridas++
+			case janela.Inicio.After(referencia):
+				janela.Situacao = JanelaFutura
+			case janela.Fim.After(referencia):
+				janela.Situacao = JanelaEmAndamento
+			default:
+				janela.Situacao = JanelaSemManutencao
+				plano.SemManutencao++
+			}
+		}
+		plano.Tarefas = append(plano.Tarefas, TarefaPlano{
+			Tarefa:     tarefa.Tarefa,
+			Livro:      tarefa.Livro,
+			Frequencia: tarefa.Frequencia,
+			Janelas:    janelas,
+		})
+	}
+	return plano
+}
+
+// semanasPeriodo divide o período em semanas de segunda a domingo. As pontas
+// com menos de minimoDiasSemana dias são somadas à semana vizinha.
+func semanasPeriodo(inicio, fim time.Time) []JanelaManutencao {
+	var semanas []JanelaManutencao
+	for dia := inicio; !dia.After(fim); {
+		domingo := dia.AddDate(0, 0, (7-int(dia.Weekday()))%7)
+		if domingo.After(fim) {
+			domingo = fim
+		}
+		semanas = append(semanas, JanelaManutencao{Inicio: dia, Fim: domingo})
+		dia = domingo.AddDate(0, 0, 1)
+	}
+
+	dias := func(j JanelaManutencao) int { return int(j.Fim.Sub(j.Inicio).Hours()/24) + 1 }
+	if len(semanas) > 1 && dias(semanas[0]) < minimoDiasSemana {
+		semanas[1].Inicio = semanas[0].Inicio
+		semanas = semanas[1:]
+	}
+	if n := len(semanas); n > 1 && dias(semanas[n-1]) < minimoDiasSemana {
+		semanas[n-2].Fim = semanas[n-1].Fim
+		semanas = semanas[:n-1]
+	}
+	return semanas
+}
+
+func inicioTrimestre(data time.Time) time.Time {
+	mes := (data.Month()-1)/3*3 + 1
+	return time.Date(data.Year(), mes, 1, 0, 0, 0, 0, data.Location())
+}
+
+// alertasPlano aponta as tarefas com semanas, mês ou trimestre sem manutenção
+func alertasPlano(plano *PlanoLocalidade) []domain.Alerta {
+	var alertas []domain.Alerta
+	for _, tarefa := range plano.Tarefas {
+		faltas := 0
+		for _, janela := range tarefa.Janelas {
+			if janela.Situacao == JanelaSemManutencao {
+				faltas++
+			}
+		}
+		if faltas == 0 {
+			continue
+		}
+
+		var mensagem string
+		switch tarefa.Frequencia {
+		case domain.FrequenciaSemanal:
+			mensagem = fmt.Sprintf("Plano de manutenção: %s sem apontamentos em %d de %d semanas.",
+				tarefa.Tarefa, faltas, len(tarefa.Janelas))
+		case domain.FrequenciaMensal:
+			mensagem = fmt.Sprintf("Plano de manutenção: %s sem apontamentos no mês.", tarefa.Tarefa)
+		default:
+			mensagem = fmt.Sprintf("Plano de manutenção: %s sem apontamentos no trimestre.", tarefa.Tarefa)
+		}
+		alertas = append(alertas, domain.Alerta{Livro: tarefa.Livro, Mensagem: mensagem, Severidade: domain.SeveridadeMedia})
+	}
+	return alertas
+}
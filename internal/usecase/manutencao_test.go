@@ -0,0 +1,193 @@
+package usecase
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"report/internal/domain"
+)
+
+// planoRepoMemoria implementa PlanoManutencaoRepository em memória
+type planoRepoMemoria []domain.TarefaManutencao
+
+func (r planoRepoMemoria) GetAll() ([]domain.TarefaManutencao, error) {
+	return r, nil
+}
+
+// lancamentosDia monta um livro com os lançamentos dos dias informados
+func lancamentosDia(dias ...string) *domain.Summary {
+	summary := &domain.Summary{TotalTrabalhos: len(dias), LancamentosDia: make(map[string]int)}
+	for _, dia := range dias {
+		summary.LancamentosDia[dia]++
+	}
+	return summary
+}
+
+func TestSemanasPeriodo(t *testing.T) {
+	tests := []struct {
+		periodo  string
+		esperado [][2]int
+	}{
+		// Começa num sábado: os dois primeiros dias vão para a semana seguinte
+		{"2025-02", [][2]int{{1, 9}, {10, 16}, {17, 23}, {24, 28}}},
+		// Termina numa segunda-feira, somada à semana anterior
+		{"2025-03", [][2]int{{1, 9}, {10, 16}, {17, 23}, {24, 31}}},
+		{"2025-09", [][2]int{{1, 7}, {8, 14}, {15, 21}, {22, 30}}},
+		{"2025-06", [][2]int{{1, 8}, {9, 15}, {16, 22}, {23, 30}}},
+		{"2024-02", [][2]int{{1, 4}, {5, 11}, {12, 18}, {19, 25}, {26, 29}}},
+	}
+	for _, tt := range tests {
+		inicio, _ := time.Parse(domain.LayoutPeriodo, tt.periodo)
+		var semanas [][2]int
+		for _, semana := range semanasPeriodo(inicio, inicio.AddDate(0, 1, -1)) {
+			semanas = append(semanas, [2]int{semana.Inicio.Day(), semana.Fim.Day()})
+		}
+		if !reflect.DeepEqual(semanas, tt.esperado) {
+			t.Errorf("semanas de %s = %v, esperado %v", tt.periodo, semanas, tt.esperado)
+		}
+	}
+}
+
+func TestAvaliarPlano(t *testing.T) {
+	inicio := dataUTC(2025, 2, 1)
+	tarefas := []domain.TarefaManutencao{
+		{Tarefa: "Limpeza das calhas", Frequencia: domain.FrequenciaSemanal, Livro: "LIMPEZA"},
+		{Tarefa: "Poda", Frequencia: domain.FrequenciaMensal, Livro: "JARDINAGEM"},
+		{Tarefa: "Extintores", Frequencia: domain.FrequenciaTrimestral, Livro: domain.LivroManutencao},
+	}
+	livros := map[string]*domain.Summary{"LIMPEZA": lancamentosDia("2025-02-05", "2025-02-12", "2025-02-12")}
+	historico := []map[string]*domain.Summary{{domain.LivroManutencao: lancamentosDia("2025-01-15")}}
+
+	tests := []struct {
+		nome          string
+		referencia    time.Time
+		historico     []map[string]*domain.Summary
+		situacoes     map[string][]SituacaoJanela
+		cumpridas     int
+		semManutencao int
+		alertas       []string
+	}{
+		{
+			nome:       "período em andamento",
+			referencia: dataUTC(2025, 2, 20),
+			historico:  historico,
+			situacoes: map[string][]SituacaoJanela{
+				"Limpeza das calhas": {JanelaCumprida, JanelaCumprida, JanelaEmAndamento, JanelaFutura},
+				"Poda":               {JanelaEmAndamento},
+				"Extintores":         {JanelaCumprida},
+			},
+			cumpridas: 3,
+		},
+		{
+			nome:       "período encerrado sem histórico do trimestre",
+			referencia: dataUTC(2025, 3, 10),
+			situacoes: map[string][]SituacaoJanela{
+				"Limpeza das calhas": {JanelaCumprida, JanelaCumprida, JanelaSemManutencao, JanelaSemManutencao},
+				"Poda":               {JanelaSemManutencao},
+				"Extintores":         {JanelaEmAndamento},
+			},
+			cumpridas:     2,
+			semManutencao: 3,
+			alertas: []string{
+				"Plano de manutenção: Limpeza das calhas sem apontamentos em 2 de 4 semanas.",
+				"Plano de manutenção: Poda sem apontamentos no mês.",
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.nome, func(t *testing.T) {
+			plano := avaliarPlano(tarefas, livros, tt.historico, inicio, tt.referencia)
+
+			situacoes := make(map[string][]SituacaoJanela)
+			for _, tarefa := range plano.Tarefas {
+				for _, janela := range tarefa.Janelas {
+					situacoes[tarefa.Tarefa] = append(situacoes[tarefa.Tarefa], janela.Situacao)
+				}
+			}
+			if !reflect.DeepEqual(situacoes, tt.situacoes) {
+				t.Errorf("situações = %v, esperado %v", situacoes, tt.situacoes)
+			}
+			if plano.Cumpridas != tt.cumpridas || plano.SemManutencao != tt.semManutencao {
+				t.Errorf("cumpridas = %d, sem manutenção = %d; esperado %d e %d",
+					plano.Cumpridas, plano.SemManutencao, tt.cumpridas, tt.semManutencao)
+			}
+			if n := plano.Tarefas[0].Janelas[1].Lancamentos; n != 2 {
+				t.Errorf("lançamentos da segunda semana = %d, esperado 2", n)
+			}
+
+			var alertas []string
+			for _, alerta := range alertasPlano(plano) {
+				alertas = append(alertas, alerta.Mensagem)
+			}
+			if !reflect.DeepEqual(alertas, tt.alertas) {
+				t.Errorf("alertas = %q, esperado %q", alertas, tt.alertas)
+			}
+		})
+	}
+}
+
+func TestAvaliarPlanos(t *testing.T) {
+	localidades := map[string]map[string]*domain.Summary{
+		"VILA NOVA": {"LIMPEZA": lancamentosDia("2025-02-05")},
+		"CENTRO":    {},
+	}
+	historico := historicoRepoMemoria{
+		"2025-01": {Periodo: "2025-01", Localidades: map[string]map[string]*domain.Summary{
+			"VILA NOVA": {domain.LivroManutencao: lancamentosDia("2025-01-15")},
+		}},
+	}
+
+	tests := []struct {
+		nome      string
+		plano     planoRepoMemoria
+		tarefas   map[string][]string
+		cumpridas map[string]int
+	}{
+		{
+			nome: "tarefas da localidade substituem as gerais",
+			plano: planoRepoMemoria{
+				{Tarefa: "Limpeza", Frequencia: domain.FrequenciaMensal, Livro: "LIMPEZA"},
+				{Localidade: "VILA NOVA", Tarefa: "Extintores", Frequencia: domain.FrequenciaTrimestral, Livro: domain.LivroManutencao},
+			},
+			tarefas:   map[string][]string{"VILA NOVA": {"Extintores"}, "CENTRO": {"Limpeza"}},
+			cumpridas: map[string]int{"VILA NOVA": 1, "CENTRO": 0},
+		},
+		{
+			nome: "só localidades com plano próprio",
+			plano: planoRepoMemoria{
+				{Localidade: "VILA NOVA", Tarefa: "Limpeza", Frequencia: domain.FrequenciaMensal, Livro: "LIMPEZA"},
+			},
+			tarefas:   map[string][]string{"VILA NOVA": {"Limpeza"}},
+			cumpridas: map[string]int{"VILA NOVA": 1},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.nome, func(t *testing.T) {
+			g := NewReportGenerator(nil, nil, nil, nil,
+				WithLogger(loggerTeste),
+				WithPeriodo("2025-02"),
+				WithPlanoManutencao(tt.plano),
+				WithHistorico(historico),
+			)
+			planos, err := g.avaliarPlanos(localidades)
+			if err != nil {
+				t.Fatal(err)
+			}
+			tarefas := make(map[string][]string)
+			cumpridas := make(map[string]int)
+			for localidade, plano := range planos {
+				for _, tarefa := range plano.Tarefas {
+					tarefas[localidade] = append(tarefas[localidade], tarefa.Tarefa)
+				}
+				cumpridas[localidade] = plano.Cumpridas
+			}
+			if !reflect.DeepEqual(tarefas, tt.tarefas) {
+				t.Errorf("tarefas = %v, esperado %v", tarefas, tt.tarefas)
+			}
+			if !reflect.DeepEqual(cumpridas, tt.cumpridas) {
+				t.Errorf("cumpridas = %v, esperado %v", cumpridas, tt.cumpridas)
+			}
+		})
+	}
+}
@@ -0,0 +1,125 @@
+package usecase
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"report/internal/domain"
+)
+
+// CumprimentoLivro compara o realizado em um livro com a sua meta
+type CumprimentoLivro struct {
+	Meta      domain.Meta
+	Realizado float64
+	// Percentual é limitado a 100, para que um livro acima da meta não
+	// compense outro abaixo no cumprimento geral
+	Percentual float64
+}
+
+// CumprimentoLocalidade reúne o cumprimento das metas de uma localidade. O
+// percentual geral é a média dos percentuais dos livros com meta.
+type CumprimentoLocalidade struct {
+	Localidade string
+	Livros     map[string]CumprimentoLivro
+	Percentual float64
+}
+
+// WithMetaRepository habilita as metas por livro e o cálculo do cumprimento
+// nos relatórios das localidades e nos resumos
+func WithMetaRepository(metaRepo domain.MetaRepository) Option {
+	return func(g *ReportGenerator) {
+		g.metaRepo = metaRepo
+	}
+}
+
+// carregarMetas lê as metas cadastradas; sem repositório, não há metas
+func (g *ReportGenerator) carregarMetas() ([]domain.Meta, error) {
+	if g.metaRepo == nil {
+		return nil, nil
+	}
+	metas, err := g.metaRepo.GetAll()
+	if err != nil {
+		return nil, fmt.Errorf("erro ao obter metas: %v", err)
+	}
+	return metas, nil
+}
+
+// metasLocalidade seleciona a meta de cada livro para a localidade: a meta
+// da localidade prevalece sobre a do setor, que prevalece sobre a geral
+func metasLocalidade(metas []domain.Meta, setor *domain.Setor, localidade string) map[string]domain.Meta {
+	selecionadas := make(map[string]domain.Meta)
+	prioridades := make(map[string]int)
+
+	for _, meta := range metas {
+		prioridade := 0
+		switch {
+		case meta.Localidade != "":
+			if meta.Localidade != localidade {
+				continue
+			}
+			prioridade = 2
+		case meta.Setor != "":
+			if setor == nil || !strings.EqualFold(meta.Setor, setor.Nome) {
+				continue
+			}
+			prioridade = 1
+		}
+
+		if atual, exists := prioridades[meta.Livro]; !exists || prioridade >= atual {
+			selecionadas[meta.Livro] = meta
+			prioridades[meta.Livro] = prioridade
+		}
+	}
+
+	return selecionadas
+}
+
+// avaliarCumprimento calcula o cumprimento das metas de uma localidade.
+// Livros com meta e sem apontamentos contam como zero; metas com valor zero
+// dispensam o livro. Retorna nil quando nenhuma meta se aplica.
+func avaliarCumprimento(localidade string, livros map[string]*domain.Summary, metas map[string]domain.Meta) *CumprimentoLocalidade {
+	cumprimento := &CumprimentoLocalidade{
+		Localidade: localidade,
+		Livros:     make(map[string]CumprimentoLivro),
+	}
+
+	total := 0.0
+	for livro, meta := range metas {
+		if meta.Valor <= 0 {
+			continue
+		}
+		realizado := 0.0
+		if summary, exists := livros[livro]; exists {
+			realizado = summary.Valor(meta.Metrica)
+		}
+		percentual := min(realizado/meta.Valor, 1) * 100
+		cumprimento.Livros[livro] = CumprimentoLivro{Meta: meta, Realizado: realizado, Percentual: percentual}
+		total += percentual
+	}
+
+	if len(cumprimento.Livros) == 0 {
+		return nil
+	}
+	cumprimento.Percentual = total / float64(len(cumprimento.Livros))
+	return cumprimento
+}
+
+// rankingCumprimento ordena as localidades informadas pelo cumprimento geral
+// das metas, do maior para o menor; localidades sem metas ficam de fora
+func rankingCumprimento(cumprimentos map[string]*CumprimentoLocalidade, localidades []string) []*CumprimentoLocalidade {
+	var ranking []*CumprimentoLocalidade
+	for _, localidade := range localidades {
+		if cumprimento := cumprimentos[localidade]; cumprimento != nil {
+			ranking = append(ranking, cumprimento)
+		}
+	}
+
+	sort.Slice(ranking, func(i, j int) bool {
+		if ranking[i].Percentual != ranking[j].Percentual {
+			return ranking[i].Percentual > ranking[j].Percentual
+		}
+		return ranking[i].Localidade < ranking[j].Localidade
+	})
+	return ranking
+}
@@ -0,0 +1,128 @@
+package usecase
+
+import (
+	"reflect"
+	"testing"
+
+	"report/internal/domain"
+)
+
+func TestMetasLocalidade(t *testing.T) {
+	metas := []domain.Meta{
+		{Livro: "LIMPEZA", Valor: 4},
+		{Livro: "LIMPEZA", Valor: 6, Setor: "setor 1"},
+		{Livro: "LIMPEZA", Valor: 10, Localidade: "VILA NOVA"},
+		{Livro: "PORTARIA", Valor: 2},
+		{Livro: "PORTARIA", Valor: 3, Setor: "Setor 2"},
+		{Livro: "JARDINAGEM", Valor: 1, Localidade: "CENTRO"},
+	}
+	setor1 := &domain.Setor{Nome: "Setor 1"}
+
+	tests := []struct {
+		nome       string
+		setor      *domain.Setor
+		localidade string
+		esperado   map[string]float64
+	}{
+		{"meta da localidade prevalece", setor1, "VILA NOVA", map[string]float64{"LIMPEZA": 10, "PORTARIA": 2}},
+		{"meta do setor prevalece sobre a geral", setor1, "CENTRO", map[string]float64{"LIMPEZA": 6, "PORTARIA": 2, "JARDINAGEM": 1}},
+		{"localidade sem setor usa a geral", nil, "JARDIM", map[string]float64{"LIMPEZA": 4, "PORTARIA": 2}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.nome, func(t *testing.T) {
+			valores := make(map[string]float64)
+			for livro, meta := range metasLocalidade(metas, tt.setor, tt.localidade) {
+				valores[livro] = meta.Valor
+			}
+			if !reflect.DeepEqual(valores, tt.esperado) {
+				t.Errorf("metasLocalidade = %v, esperado %v", valores, tt.esperado)
+			}
+		})
+	}
+}
+
+func TestAvaliarCumprimento(t *testing.T) {
+	livros := map[string]*domain.Summary{
+		"LIMPEZA":  {TotalTrabalhos: 3, Horas: 9, Voluntarios: map[string]int{"A": 3}},
+		"PORTARIA": {TotalTrabalhos: 12, Horas: 30, Voluntarios: map[string]int{"A": 6, "B": 6}},
+	}
+
+	tests := []struct {
+		nome       string
+		metas      map[string]domain.Meta
+		percentual float64
+		livros     map[string]float64
+	}{
+		{
+			nome:       "sem metas",
+			metas:      map[string]domain.Meta{},
+			percentual: -1,
+		},
+		{
+			nome:       "meta zero dispensa o livro",
+			metas:      map[string]domain.Meta{"LIMPEZA": {Livro: "LIMPEZA", Valor: 0}},
+			percentual: -1,
+		},
+		{
+			nome: "acima da meta limitado a 100",
+			metas: map[string]domain.Meta{
+				"LIMPEZA":  {Livro: "LIMPEZA", Metrica: domain.MetricaLancamentos, Valor: 6},
+				"PORTARIA": {Livro: "PORTARIA", Metrica: domain.MetricaLancamentos, Valor: 4},
+			},
+			percentual: 75,
+			livros:     map[string]float64{"LIMPEZA": 50, "PORTARIA": 100},
+		},
+		{
+			nome: "métricas diferentes por livro e livro sem apontamentos",
+			metas: map[string]domain.Meta{
+				"LIMPEZA":    {Livro: "LIMPEZA", Metrica: domain.MetricaHoras, Valor: 12},
+				"PORTARIA":   {Livro: "PORTARIA", Metrica: domain.MetricaVoluntarios, Valor: 4},
+				"JARDINAGEM": {Livro: "JARDINAGEM", Metrica: domain.MetricaLancamentos, Valor: 2},
+			},
+			percentual: (75 + 50 + 0) / 3.0,
+			livros:     map[string]float64{"LIMPEZA": 75, "PORTARIA": 50, "JARDINAGEM": 0},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.nome, func(t *testing.T) {
+			cumprimento := avaliarCumprimento("VILA NOVA", livros, tt.metas)
+			if tt.percentual < 0 {
+				if cumprimento != nil {
+					t.Errorf("avaliarCumprimento = %+v, esperado nil", cumprimento)
+				}
+				return
+			}
+			if cumprimento == nil {
+				t.Fatal("avaliarCumprimento = nil")
+			}
+			if cumprimento.Percentual != tt.percentual {
+				t.Errorf("Percentual = %g, esperado %g", cumprimento.Percentual, tt.percentual)
+			}
+			percentuais := make(map[string]float64)
+			for livro, c := range cumprimento.Livros {
+				percentuais[livro] = c.Percentual
+			}
+			if !reflect.DeepEqual(percentuais, tt.livros) {
+				t.Errorf("livros = %v, esperado %v", percentuais, tt.livros)
+			}
+		})
+	}
+}
+
+func TestRankingCumprimento(t *testing.T) {
+	cumprimentos := map[string]*CumprimentoLocalidade{
+		"VILA NOVA": {Localidade: "VILA NOVA", Percentual: 80},
+		"CENTRO":    {Localidade: "CENTRO", Percentual: 95},
+		"JARDIM":    {Localidade: "JARDIM", Percentual: 80},
+	}
+
+	var ranking []string
+	for _, c := range rankingCumprimento(cumprimentos, []string{"VILA NOVA", "CENTRO", "JARDIM", "SEM METAS"}) {
+		ranking = append(ranking, c.Localidade)
+	}
+	if esperado := []string{"CENTRO", "JARDIM", "VILA NOVA"}; !reflect.DeepEqual(ranking, esperado) {
+		t.Errorf("ranking = %v, esperado %v", ranking, esperado)
+	}
+}
@@ -0,0 +1,259 @@
+package usecase
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"report/internal/domain"
+)
+
+// Nomes dos arquivos gerados para os pacotes
+const (
+	pacotesDir      = "pacotes"
+	pacoteGeral     = "geral"
+	dadosFileName   = "dados.xlsx"
+	resumoSetorNome = "resumo_setor.pdf"
+	dadosSetorNome  = "dados_setor.xlsx"
+)
+
+// Aba representa uma aba de planilha: cabeçalho e linhas com valores string, int ou float64
+type Aba struct {
+	Nome      string
+	Cabecalho []string
+	Linhas    [][]interface{}
+}
+
+// PlanilhaService define a interface para gravação de planilhas XLSX
+type PlanilhaService interface {
+	GenerateXLSX(abas []Aba, outputPath string) error
+}
+
+// ArquivoPacote indica um arquivo a incluir no pacote e o nome dele dentro do pacote
+type ArquivoPacote struct {
+	Caminho string
+	Nome    string
+}
+
+// PacoteService define a interface para criação dos pacotes ZIP
+type PacoteService interface {
+	Create(outputPath string, arquivos []ArquivoPacote) error
+}
+
+// WithPacotes habilita a geração da planilha de cada setor e o empacotamento
+// dos documentos em um ZIP por setor e um geral
+func WithPacotes(planilhaService PlanilhaService, pacoteService PacoteService) Option {
+	return func(g *ReportGenerator) {
+		g.planilhaService = planilhaService
+		g.pacoteService = pacoteService
+	}
+}
+
+// grupoSetor reúne as localidades gravadas no mesmo diretório de setor
+type grupoSetor struct {
+	Nome          string
+	Diretorio     string
+	Administracao string
+	Localidades   []string
+}
+
+// documentosResumosSetores monta o resumo em PDF de cada setor, gerado com
+// ou sem os pacotes. O calendário do setor soma as suas localidades e marca
+// os dias de culto da agenda geral, já que as agendas próprias das
+// localidades podem divergir.
+func (g *ReportGenerator) documentosResumosSetores(
+	snapshot *domain.Snapshot,
+	grupos map[string]*grupoSetor,
+	livros map[string]map[string]bool,
+	cumprimentos map[string]*CumprimentoLocalidade,
+	saudes map[string]*SaudeLocalidade,
+	cultosGerais []time.Weekday,
+) []documento {
+	var documentos []documento
+
+	for _, diretorio := range OrdenarChaves(grupos) {
+		grupo := grupos[diretorio]
+		localidades := make(map[string]map[string]*domain.Summary, len(grupo.Localidades))
+		livrosSetor := make(map[string]map[string]bool, len(grupo.Localidades))
+		saudesSetor := make(map[string]*SaudeLocalidade, len(grupo.Localidades))
+		dadosSetor := make([]map[string]*domain.Summary, 0, len(grupo.Localidades))
+		for _, localidade := range grupo.Localidades {
+			localidades[localidade] = snapshot.Localidades[localidade]
+			dadosSetor = append(dadosSetor, snapshot.Localidades[localidade])
+			saudesSetor[localidade] = saudes[localidade]
+			if catalogo, exists := livros[localidade]; exists {
+				livrosSetor[localidade] = catalogo
+			}
+		}
+
+		resumoPath := filepath.Join(grupo.Diretorio, resumoSetorNome)
+		reportData := &ReportData{
+			Titulo:           fmt.Sprintf("Resumo do %s", grupo.Nome),
+			Data:             time.Now(),
+			Periodo:          g.periodo,
+			Metrica:          g.metrica,
+			Localidades:      localidades,
+			LivrosMap:        livrosSetor,
+			Totais:           consolidarLivros(localidades, grupo.Localidades),
+			Ranking:          rankingCumprimento(cumprimentos, grupo.Localidades),
+			Calendario:       g.montarCalendario(dadosSetor, cultosGerais),
+			SaudeLocalidades: saudesSetor,
+		}
+		documentos = append(documentos, documento{
+			Nome:    fmt.Sprintf("resumo do setor %s", grupo.Nome),
+			Caminho: resumoPath,
+			Chave:   g.chaveDocumento("resumo", reportData),
+			gerar: func() error {
+				return g.pdfService.GenerateSummaryReport(reportData, resumoPath)
+			},
+		})
+	}
+
+	return documentos
+}
+
+// documentosSetores monta a planilha de dados de cada setor e a planilha geral
+func (g *ReportGenerator) documentosSetores(snapshot *domain.Snapshot, grupos map[string]*grupoSetor) []documento {
+	var documentos []documento
+
+	for _, diretorio := range OrdenarChaves(grupos) {
+		grupo := grupos[diretorio]
+		dadosPath := filepath.Join(grupo.Diretorio, dadosSetorNome)
+		abas := abasDados(snapshot, []*grupoSetor{grupo})
+		documentos = append(documentos, documento{
+			Nome:    fmt.Sprintf("planilha do setor %s", grupo.Nome),
+			Caminho: dadosPath,
+			Chave:   g.chaveDocumento("planilha", abas),
+			gerar: func() error {
+				return g.planilhaService.GenerateXLSX(abas, dadosPath)
+			},
+		})
+	}
+
+	todos := make([]*grupoSetor, 0, len(grupos))
+	for _, diretorio := range OrdenarChaves(grupos) {
+		todos = append(todos, grupos[diretorio])
+	}
+	dadosPath := filepath.Join(g.outputDir, dadosFileName)
+	abas := abasDados(snapshot, todos)
+	documentos = append(documentos, documento{
+		Nome:    "planilha geral",
+		Caminho: dadosPath,
+		Chave:   g.chaveDocumento("planilha", abas),
+		gerar: func() error {
+			return g.planilhaService.GenerateXLSX(abas, dadosPath)
+		},
+	})
+
+	return documentos
+}
+
+// documentosPacotes monta um ZIP por setor com os documentos do diretório do
+// setor e o manifesto, e um ZIP geral com todos os documentos. Os pacotes
+// existentes sem nenhum documento gerado nesta execução são mantidos.
+func (g *ReportGenerator) documentosPacotes(resultado *Resultado, grupos map[string]*grupoSetor) ([]documento, []string) {
+	gerados := resultado.Documentos()
+	alterados := make(map[string]bool, len(resultado.Gerados))
+	for _, caminho := range resultado.Gerados {
+		alterados[caminho] = true
+	}
+
+	var manifesto []ArquivoPacote
+	if g.manifestService != nil {
+		manifesto = append(manifesto, ArquivoPacote{
+			Caminho: filepath.Join(g.outputDir, ManifestFileName),
+			Nome:    ManifestFileName,
+		})
+	}
+
+	var documentos []documento
+	var mantidos []string
+	incluir := func(doc documento, arquivos []ArquivoPacote) {
+		for _, arquivo := range arquivos {
+			if alterados[arquivo.Caminho] {
+				documentos = append(documentos, doc)
+				return
+			}
+		}
+		if _, err := os.Stat(doc.Caminho); err != nil {
+			documentos = append(documentos, doc)
+			return
+		}
+		mantidos = append(mantidos, doc.Caminho)
+	}
+
+	for _, diretorio := range OrdenarChaves(grupos) {
+		grupo := grupos[diretorio]
+		arquivos := append([]ArquivoPacote(nil), manifesto...)
+		for _, caminho := range gerados {
+			if filepath.Dir(caminho) == grupo.Diretorio {
+				arquivos = append(arquivos, ArquivoPacote{Caminho: caminho, Nome: filepath.Base(caminho)})
+			}
+		}
+		incluir(g.documentoPacote(grupo.Nome, diretorio, arquivos), arquivos)
+	}
+
+	arquivos := append([]ArquivoPacote(nil), manifesto...)
+	for _, caminho := range gerados {
+		arquivos = append(arquivos, ArquivoPacote{Caminho: caminho, Nome: relativo(g.outputDir, caminho)})
+	}
+	incluir(g.documentoPacote(pacoteGeral, pacoteGeral, arquivos), arquivos)
+
+	return documentos, mantidos
+}
+
+func (g *ReportGenerator) documentoPacote(nome, prefixo string, arquivos []ArquivoPacote) documento {
+	sort.Slice(arquivos, func(i, j int) bool {
+		return arquivos[i].Nome < arquivos[j].Nome
+	})
+	caminho := g.pacoteOutputPath(prefixo)
+	return documento{
+		Nome:    fmt.Sprintf("pacote %s", nome),
+		Caminho: caminho,
+		gerar: func() error {
+			return g.pacoteService.Create(caminho, arquivos)
+		},
+	}
+}
+
+// pacoteOutputPath segue a convenção <setor>_<AAAA-MM>.zip
+func (g *ReportGenerator) pacoteOutputPath(prefixo string) string {
+	nome := strings.ReplaceAll(prefixo, string(filepath.Separator), "_")
+	return filepath.Join(g.outputDir, pacotesDir, fmt.Sprintf("%s_%s.zip", nome, g.periodo))
+}
+
+// abasDados monta as abas de totais por livro e de alertas das localidades dos grupos
+func abasDados(snapshot *domain.Snapshot, grupos []*grupoSetor) []Aba {
+	totais := Aba{
+		Nome:      "Totais",
+		Cabecalho: []string{"Setor", "Localidade", "Livro", "Total Lançados", "Horas", "Voluntários"},
+	}
+	alertas := Aba{
+		Nome:      "Alertas",
+		Cabecalho: []string{"Setor", "Localidade", "Livro", "Alerta"},
+	}
+
+	for _, grupo := range grupos {
+		localidades := append([]string(nil), grupo.Localidades...)
+		sort.Strings(localidades)
+		for _, localidade := range localidades {
+			livros := snapshot.Localidades[localidade]
+			for _, livro := range OrdenarChaves(livros) {
+				summary := livros[livro]
+				totais.Linhas = append(totais.Linhas, []interface{}{
+					grupo.Nome, localidade, livro, summary.TotalTrabalhos, summary.Horas, summary.TotalVoluntarios(),
+				})
+			}
+			for _, alerta := range snapshot.Alertas[localidade] {
+				alertas.Linhas = append(alertas.Linhas, []interface{}{
+					grupo.Nome, localidade, alerta.Livro, alerta.Mensagem,
+				})
+			}
+		}
+	}
+
+	return []Aba{totais, alertas}
+}
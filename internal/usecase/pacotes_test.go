@@ -0,0 +1,148 @@
+package usecase
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"report/internal/domain"
+)
+
+// pacoteMemoria implementa PacoteService registrando os arquivos de cada pacote
+type pacoteMemoria map[string][]string
+
+func (s pacoteMemoria) Create(outputPath string, arquivos []ArquivoPacote) error {
+	for _, arquivo := range arquivos {
+		s[outputPath] = append(s[outputPath], arquivo.Nome)
+	}
+	return os.WriteFile(outputPath, []byte("zip"), 0o644)
+}
+
+func TestDocumentosPacotes(t *testing.T) {
+	dir := t.TempDir()
+	grupos := map[string]*grupoSetor{
+		"SETOR 1": {Nome: "Setor 1", Diretorio: filepath.Join(dir, "SETOR 1")},
+		"SETOR 2": {Nome: "Setor 2", Diretorio: filepath.Join(dir, "SETOR 2")},
+	}
+	vilaNova := filepath.Join(dir, "SETOR 1", "VILA NOVA.pdf")
+	centro := filepath.Join(dir, "SETOR 1", "CENTRO.pdf")
+	jardim := filepath.Join(dir, "SETOR 2", "JARDIM.pdf")
+	resumo := filepath.Join(dir, "resumo.pdf")
+
+	tests := []struct {
+		nome       string
+		existentes []string
+		resultado  *Resultado
+		gerados    map[string][]string
+		mantidos   []string
+	}{
+		{
+			nome:      "primeira execução gera todos os pacotes",
+			resultado: &Resultado{Gerados: []string{vilaNova, centro, jardim, resumo}},
+			gerados: map[string][]string{
+				"SETOR 1_2025-02.zip": {"CENTRO.pdf", "VILA NOVA.pdf", ManifestFileName},
+				"SETOR 2_2025-02.zip": {"JARDIM.pdf", ManifestFileName},
+				"geral_2025-02.zip": {
+					"SETOR 1/CENTRO.pdf", "SETOR 1/VILA NOVA.pdf", "SETOR 2/JARDIM.pdf", ManifestFileName, "resumo.pdf",
+				},
+			},
+		},
+		{
+			nome:       "pacote sem documento novo é mantido",
+			existentes: []string{"SETOR 1_2025-02.zip", "SETOR 2_2025-02.zip", "geral_2025-02.zip"},
+			resultado:  &Resultado{Gerados: []string{jardim}, Mantidos: []string{vilaNova, centro, resumo}},
+			gerados: map[string][]string{
+				"SETOR 2_2025-02.zip": {"JARDIM.pdf", ManifestFileName},
+				"geral_2025-02.zip": {
+					"SETOR 1/CENTRO.pdf", "SETOR 1/VILA NOVA.pdf", "SETOR 2/JARDIM.pdf", ManifestFileName, "resumo.pdf",
+				},
+			},
+			mantidos: []string{"SETOR 1_2025-02.zip"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.nome, func(t *testing.T) {
+			os.RemoveAll(filepath.Join(dir, pacotesDir))
+			os.MkdirAll(filepath.Join(dir, pacotesDir), 0o755)
+			for _, nome := range tt.existentes {
+				gravarArquivo(t, filepath.Join(dir, pacotesDir, nome), "zip")
+			}
+			pacotes := pacoteMemoria{}
+			g := NewReportGenerator(nil, nil, nil, nil, WithOutputDir(dir), WithPeriodo("2025-02"),
+				WithPacotes(nil, pacotes), WithManifest(&manifestMemoria{}, ManifestInfo{}))
+
+			documentos, mantidos := g.documentosPacotes(tt.resultado, grupos)
+			for _, doc := range documentos {
+				if err := doc.gerar(); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			gerados := make(map[string][]string)
+			for caminho, arquivos := range pacotes {
+				gerados[filepath.Base(caminho)] = arquivos
+			}
+			if !reflect.DeepEqual(gerados, tt.gerados) {
+				t.Errorf("pacotes gerados = %v, esperado %v", gerados, tt.gerados)
+			}
+			var nomes []string
+			for _, caminho := range mantidos {
+				nomes = append(nomes, filepath.Base(caminho))
+			}
+			if !reflect.DeepEqual(nomes, tt.mantidos) {
+				t.Errorf("pacotes mantidos = %v, esperado %v", nomes, tt.mantidos)
+			}
+		})
+	}
+}
+
+func TestPacoteOutputPath(t *testing.T) {
+	g := NewReportGenerator(nil, nil, nil, nil, WithOutputDir("saida"), WithPeriodo("2025-02"))
+	tests := []struct {
+		prefixo  string
+		esperado string
+	}{
+		{"geral", filepath.Join("saida", pacotesDir, "geral_2025-02.zip")},
+		{"Setor 9.1", filepath.Join("saida", pacotesDir, "Setor 9.1_2025-02.zip")},
+		{filepath.Join("ADM", "Setor 1"), filepath.Join("saida", pacotesDir, "ADM_Setor 1_2025-02.zip")},
+	}
+	for _, tt := range tests {
+		if caminho := g.pacoteOutputPath(tt.prefixo); caminho != tt.esperado {
+			t.Errorf("pacoteOutputPath(%q) = %s, esperado %s", tt.prefixo, caminho, tt.esperado)
+		}
+	}
+}
+
+func TestAbasDados(t *testing.T) {
+	snapshot := &domain.Snapshot{
+		Localidades: map[string]map[string]*domain.Summary{
+			"VILA NOVA": {"LIMPEZA": {TotalTrabalhos: 3, Horas: 6.5, Voluntarios: map[string]int{"A": 2, "B": 1}}},
+			"CENTRO":    {"PORTARIA": {TotalTrabalhos: 1, Horas: 2, Voluntarios: map[string]int{"A": 1}}},
+		},
+		Alertas: map[string][]domain.Alerta{"CENTRO": {{Livro: "PORTARIA", Mensagem: "poucos voluntários"}}},
+	}
+	grupos := []*grupoSetor{{Nome: "Setor 1", Localidades: []string{"VILA NOVA", "CENTRO"}}}
+
+	abas := abasDados(snapshot, grupos)
+
+	esperadas := []Aba{
+		{
+			Nome:      "Totais",
+			Cabecalho: []string{"Setor", "Localidade", "Livro", "Total Lançados", "Horas", "Voluntários"},
+			Linhas: [][]interface{}{
+				{"Setor 1", "CENTRO", "PORTARIA", 1, 2.0, 1},
+				{"Setor 1", "VILA NOVA", "LIMPEZA", 3, 6.5, 2},
+			},
+		},
+		{
+			Nome:      "Alertas",
+			Cabecalho: []string{"Setor", "Localidade", "Livro", "Alerta"},
+			Linhas:    [][]interface{}{{"Setor 1", "CENTRO", "PORTARIA", "poucos voluntários"}},
+		},
+	}
+	if !reflect.DeepEqual(abas, esperadas) {
+		t.Errorf("abasDados =\n%v\nesperado\n%v", abas, esperadas)
+	}
+}
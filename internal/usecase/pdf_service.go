@@ -2,6 +2,12 @@ package usecase
 
 // PDFService define as operações para geração de PDFs
 type PDFService interface {
+	// Modelo identifica o layout dos documentos; muda quando o layout muda
+	Modelo() string
 	GenerateLocalidadeReport(data *ReportData, outputPath string) error
 	GenerateSummaryReport(data *ReportData, outputPath string) error
+	GenerateDiffReport(diff *Diferenca, outputPath string) error
+	GenerateCombinedReport(data *CombinedReportData, outputPath string) error
+	GenerateAdministracaoReport(data *AdministracaoReportData, outputPath string) error
+	GenerateClassificacaoReport(data *ClassificacaoReportData, outputPath string) error
 }
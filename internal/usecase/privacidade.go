@@ -0,0 +1,48 @@
+package usecase
+
+import (
+	"time"
+
+	"report/internal/domain"
+)
+
+// pseudonimizar retorna uma cópia do snapshot em que os voluntários são
+// identificados pela chave da política de privacidade, usada em tudo o que é
+// gravado ou comparado fora da geração dos relatórios. O snapshot recebido,
+// cujos mapas são compartilhados com o repositório, não é alterado. Aplicada
+// a um snapshot já pseudonimizado, não muda as chaves.
+func pseudonimizar(snapshot *domain.Snapshot, privacidade domain.Privacidade) *domain.Snapshot {
+	copia := *snapshot
+	copia.Localidades = make(map[string]map[string]*domain.Summary, len(snapshot.Localidades))
+	for localidade, livros := range snapshot.Localidades {
+		copia.Localidades[localidade] = make(map[string]*domain.Summary, len(livros))
+		for livro, summary := range livros {
+			copia.Localidades[localidade][livro] = pseudonimizarSummary(summary, privacidade)
+		}
+	}
+	return &copia
+}
+
+func pseudonimizarSummary(summary *domain.Summary, privacidade domain.Privacidade) *domain.Summary {
+	copia := *summary
+	copia.Voluntarios = make(map[string]int, len(summary.Voluntarios))
+	for voluntario, n := range summary.Voluntarios {
+		copia.Voluntarios[privacidade.Chave(voluntario)] += n
+	}
+	if summary.HorasVoluntarios != nil {
+		copia.HorasVoluntarios = make(map[string]float64, len(summary.HorasVoluntarios))
+		for voluntario, horas := range summary.HorasVoluntarios {
+			copia.HorasVoluntarios[privacidade.Chave(voluntario)] += horas
+		}
+	}
+	if summary.UltimoLancamentoVoluntarios != nil {
+		copia.UltimoLancamentoVoluntarios = make(map[string]time.Time, len(summary.UltimoLancamentoVoluntarios))
+		for voluntario, data := range summary.UltimoLancamentoVoluntarios {
+			chave := privacidade.Chave(voluntario)
+			if data.After(copia.UltimoLancamentoVoluntarios[chave]) {
+				copia.UltimoLancamentoVoluntarios[chave] = data
+			}
+		}
+	}
+	return &copia
+}
@@ -0,0 +1,81 @@
+package usecase
+
+import (
+	"maps"
+	"reflect"
+	"testing"
+	"time"
+
+	"report/internal/domain"
+)
+
+func TestPseudonimizar(t *testing.T) {
+	fevereiro := time.Date(2025, 2, 10, 0, 0, 0, 0, time.UTC)
+	marco := time.Date(2025, 3, 5, 0, 0, 0, 0, time.UTC)
+	jose := domain.PrivacidadeIniciais.Chave("JOSE DA SILVA")
+	joao := domain.PrivacidadeIniciais.Chave("JOAO DA SILVA")
+
+	tests := []struct {
+		nome        string
+		privacidade domain.Privacidade
+		summary     *domain.Summary
+		esperado    *domain.Summary
+	}{
+		{
+			nome:        "homônimos de iniciais ficam separados",
+			privacidade: domain.PrivacidadeIniciais,
+			summary: &domain.Summary{
+				TotalTrabalhos:              3,
+				Voluntarios:                 map[string]int{"JOSE DA SILVA": 2, "JOAO DA SILVA": 1},
+				HorasVoluntarios:            map[string]float64{"JOSE DA SILVA": 4, "JOAO DA SILVA": 1.5},
+				UltimoLancamentoVoluntarios: map[string]time.Time{"JOSE DA SILVA": marco, "JOAO DA SILVA": fevereiro},
+			},
+			esperado: &domain.Summary{
+				TotalTrabalhos:              3,
+				Voluntarios:                 map[string]int{jose: 2, joao: 1},
+				HorasVoluntarios:            map[string]float64{jose: 4, joao: 1.5},
+				UltimoLancamentoVoluntarios: map[string]time.Time{jose: marco, joao: fevereiro},
+			},
+		},
+		{
+			nome:        "nome e chave do mesmo voluntário são somados",
+			privacidade: domain.PrivacidadeIniciais,
+			summary: &domain.Summary{
+				Voluntarios:                 map[string]int{"JOSE DA SILVA": 2, jose: 1},
+				HorasVoluntarios:            map[string]float64{"JOSE DA SILVA": 4, jose: 1},
+				UltimoLancamentoVoluntarios: map[string]time.Time{"JOSE DA SILVA": fevereiro, jose: marco},
+			},
+			esperado: &domain.Summary{
+				Voluntarios:                 map[string]int{jose: 3},
+				HorasVoluntarios:            map[string]float64{jose: 5},
+				UltimoLancamentoVoluntarios: map[string]time.Time{jose: marco},
+			},
+		},
+		{
+			nome:        "nome completo",
+			privacidade: domain.PrivacidadeNomeCompleto,
+			summary:     &domain.Summary{Voluntarios: map[string]int{"JOSE  DA SILVA": 2}},
+			esperado:    &domain.Summary{Voluntarios: map[string]int{"JOSE DA SILVA": 2}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.nome, func(t *testing.T) {
+			snapshot := &domain.Snapshot{
+				Periodo:     "2025-03",
+				Localidades: map[string]map[string]*domain.Summary{"VILA NOVA": {"MANUTENCAO": tt.summary}},
+			}
+			voluntarios := maps.Clone(tt.summary.Voluntarios)
+
+			copia := pseudonimizar(snapshot, tt.privacidade)
+			if resultado := copia.Localidades["VILA NOVA"]["MANUTENCAO"]; !reflect.DeepEqual(resultado, tt.esperado) {
+				t.Errorf("summary = %+v, esperado %+v", resultado, tt.esperado)
+			}
+			if !reflect.DeepEqual(tt.summary.Voluntarios, voluntarios) {
+				t.Error("o snapshot original foi alterado")
+			}
+			if deNovo := pseudonimizar(copia, tt.privacidade); !reflect.DeepEqual(deNovo, copia) {
+				t.Error("pseudonimizar um snapshot já pseudonimizado mudou as chaves")
+			}
+		})
+	}
+}
@@ -1,18 +1,138 @@
 package usecase
 
 import (
+	"context"
 	"fmt"
+	"log/slog"
+	"path/filepath"
+	"runtime"
+	"sort"
 	"time"
 
 	"report/internal/domain"
 )
 
+// DefaultOutputDir é o diretório padrão dos relatórios gerados
+const DefaultOutputDir = "./files/output"
+
+// diretorioOutros recebe os relatórios das localidades sem setor
+const diretorioOutros = "outros"
+
 // ReportGenerator define o caso de uso para geração de relatórios
 type ReportGenerator struct {
 	localidadeRepo domain.LocalidadeRepository
 	setorRepo      domain.SetorRepository
 	livroRepo      domain.LivroRepository
+	aliasRepo      domain.AliasRepository
+	metaRepo       domain.MetaRepository
+	observacaoRepo domain.ObservacaoRepository
+	brigadaRepo    domain.BrigadaRepository
+	planoRepo      domain.PlanoManutencaoRepository
+	cultosRepo     domain.AgendaCultosRepository
 	pdfService     PDFService
+	confirmarAlias func(domain.SugestaoAlias) bool
+	validacao      *Validacao
+	workers        int
+	logger         *slog.Logger
+	progresso      Progresso
+	outputDir      string
+	periodo        string
+	metrica        domain.Metrica
+	privacidade    domain.Privacidade
+	pesosSaude     domain.PesosSaude
+
+	minimoManutencao float64
+
+	validadeBrigada int
+
+	manifestService ManifestService
+	manifestInfo    ManifestInfo
+	historicoRepo   domain.HistoricoRepository
+	planilhaService PlanilhaService
+	pacoteService   PacoteService
+
+	documentoCompleto bool
+	classificacao     bool
+	semCache          bool
+}
+
+// Option configura parâmetros opcionais do ReportGenerator
+type Option func(*ReportGenerator)
+
+// WithAliasRepository habilita a tabela de apelidos na resolução de localidades
+func WithAliasRepository(aliasRepo domain.AliasRepository) Option {
+	return func(g *ReportGenerator) {
+		g.aliasRepo = aliasRepo
+	}
+}
+
+// WithWorkers define o número de documentos gerados simultaneamente
+func WithWorkers(workers int) Option {
+	return func(g *ReportGenerator) {
+		if workers > 0 {
+			g.workers = workers
+		}
+	}
+}
+
+// WithLogger define o logger usado para registrar as etapas da execução
+func WithLogger(logger *slog.Logger) Option {
+	return func(g *ReportGenerator) {
+		g.logger = logger
+	}
+}
+
+// WithProgresso define a função notificada a cada documento concluído
+func WithProgresso(progresso Progresso) Option {
+	return func(g *ReportGenerator) {
+		g.progresso = progresso
+	}
+}
+
+// WithOutputDir define o diretório onde os relatórios são gravados
+func WithOutputDir(outputDir string) Option {
+	return func(g *ReportGenerator) {
+		g.outputDir = outputDir
+	}
+}
+
+// WithPeriodo define o período de referência (AAAA-MM) dos relatórios
+func WithPeriodo(periodo string) Option {
+	return func(g *ReportGenerator) {
+		g.periodo = periodo
+	}
+}
+
+// WithMetrica define a métrica usada pelas regras de alerta e pela matriz resumo
+func WithMetrica(metrica domain.Metrica) Option {
+	return func(g *ReportGenerator) {
+		if metrica != "" {
+			g.metrica = metrica
+		}
+	}
+}
+
+// WithManifest habilita a gravação do manifesto da execução no diretório de saída
+func WithManifest(manifestService ManifestService, info ManifestInfo) Option {
+	return func(g *ReportGenerator) {
+		g.manifestService = manifestService
+		g.manifestInfo = info
+	}
+}
+
+// WithHistorico habilita a gravação dos dados consolidados do período a cada execução
+func WithHistorico(historicoRepo domain.HistoricoRepository) Option {
+	return func(g *ReportGenerator) {
+		g.historicoRepo = historicoRepo
+	}
+}
+
+// WithConfirmacaoAlias define a função usada para confirmar interativamente
+// as sugestões de apelidos; apelidos confirmados são persistidos
+func WithConfirmacaoAlias(confirmar func(domain.SugestaoAlias) bool) Option {
+	return func(g *ReportGenerator) {
+		g.confirmarAlias = confirmar
+	}
 }
 
 // NewReportGenerator cria uma nova instância de ReportGenerator
@@ -21,102 +141,420 @@ func NewReportGenerator(
 	setorRepo domain.SetorRepository,
 	livroRepo domain.LivroRepository,
 	pdfService PDFService,
+	opts ...Option,
 ) *ReportGenerator {
-	return &ReportGenerator{
+	g := &ReportGenerator{
 		localidadeRepo: localidadeRepo,
 		setorRepo:      setorRepo,
 		livroRepo:      livroRepo,
 		pdfService:     pdfService,
+		workers:        runtime.NumCPU(),
+		logger:         slog.Default(),
+		outputDir:      DefaultOutputDir,
+		periodo:        time.Now().Format(domain.LayoutPeriodo),
+		metrica:        domain.MetricaLancamentos,
+		privacidade:    domain.PrivacidadeIniciais,
+		pesosSaude:     domain.PesosSaudePadrao,
+	}
+	for _, opt := range opts {
+		opt(g)
 	}
+	return g
 }
 
-// GenerateReports gera todos os relatórios
-func (g *ReportGenerator) GenerateReports() error {
-	localidades, err := g.localidadeRepo.GetAll()
+// GenerateReports gera todos os relatórios. Os documentos são gerados em
+// paralelo e as falhas individuais são reunidas no Resultado, sem interromper
+// a geração dos demais; o erro retornado indica falha na leitura dos dados.
+func (g *ReportGenerator) GenerateReports(ctx context.Context) (*Resultado, error) {
+	inicio := time.Now()
+
+	consolidado, err := g.consolidar()
 	if err != nil {
-		return fmt.Errorf("erro ao obter localidades: %v", err)
+		return nil, err
 	}
+	g.validacao = consolidado.validacao
+	snapshot := consolidado.snapshot
+	localidades := snapshot.Localidades
 
+	etapa := time.Now()
 	livros, err := g.livroRepo.GetAll()
 	if err != nil {
-		return fmt.Errorf("erro ao obter livros: %v", err)
+		return nil, fmt.Errorf("erro ao obter livros: %v", err)
+	}
+	livros = alinharCatalogo(livros, nomesLivros(localidades))
+	g.logger.Info("etapa concluída", "etapa", "catalogo", "localidades", len(livros), "duracao", time.Since(etapa))
+
+	metas, err := g.carregarMetas()
+	if err != nil {
+		return nil, err
+	}
+	observacoes, err := g.carregarObservacoes()
+	if err != nil {
+		return nil, err
 	}
+	cultos, cultosGerais, err := g.carregarCultos()
+	if err != nil {
+		return nil, err
+	}
+	anterior := g.carregarAnterior()
+	referencia := g.dataReferencia()
+	porVoluntario := localidadesPorVoluntario(localidades)
+
+	var documentos []documento
+	contagemAlertas := make(map[string]int, len(localidades))
+	grupos := make(map[string]*grupoSetor)
+	cumprimentos := make(map[string]*CumprimentoLocalidade)
+	relatorios := make(map[string]*ReportData, len(localidades))
+	saudes := make(map[string]*SaudeLocalidade, len(localidades))
 
-	// Gera relatórios individuais
+	// Relatórios individuais
 	for localidade, dadosLocalidade := range localidades {
 		setor, err := g.setorRepo.GetByLocalidade(localidade)
 		if err != nil {
-			return fmt.Errorf("erro ao obter setor para localidade %s: %v", localidade, err)
+			return nil, fmt.Errorf("erro ao obter setor para localidade %s: %v", localidade, err)
 		}
 
-		err = g.generateLocalidadeReport(localidade, dadosLocalidade, setor)
+		localidade, dadosLocalidade := localidade, dadosLocalidade
+		alertas := snapshot.Alertas[localidade]
+		contagemAlertas[localidade] = len(alertas)
+		cumprimento := avaliarCumprimento(localidade, dadosLocalidade, metasLocalidade(metas, setor, localidade))
+		cumprimentos[localidade] = cumprimento
+		esperados := livros[localidade]
+		if semCadastro := livrosSemCadastro(dadosLocalidade, esperados); len(semCadastro) > 0 {
+			g.logger.Warn("livros com apontamentos sem cadastro", "localidade", localidade, "livros", semCadastro)
+		}
+
+		outputPath := g.getOutputPath(setor, localidade)
+		diretorio := filepath.Base(filepath.Dir(outputPath))
+		if grupos[diretorio] == nil {
+			grupos[diretorio] = &grupoSetor{Nome: diretorio, Diretorio: filepath.Dir(outputPath)}
+			if setor != nil {
+				grupos[diretorio].Nome = setor.Nome
+				grupos[diretorio].Administracao = administracaoSetor(setor, snapshot.Administracoes)
+			}
+		}
+		grupos[diretorio].Localidades = append(grupos[diretorio].Localidades, localidade)
+
+		reportData := g.localidadeReportData(localidade, dadosLocalidade, esperados, alertas, cumprimento)
+		var anteriores map[string]*domain.Summary
+		comAnterior := false
+		if anterior != nil {
+			anteriores, comAnterior = anterior.Localidades[localidade]
+		}
+		reportData.Apontamentos = avaliarApontamentos(dadosLocalidade, anteriores, comAnterior, g.metrica, referencia)
+		reportData.Saude = avaliarSaude(g.pesosSaude, dadosLocalidade, esperados, cumprimento, alertas, anteriores, comAnterior, g.metrica)
+		saudes[localidade] = reportData.Saude
+		reportData.Observacoes = observacoes[localidade]
+		reportData.Cobertura = avaliarCobertura(localidade, dadosLocalidade, porVoluntario, g.privacidade)
+		reportData.Brigada = consolidado.brigadas[localidade]
+		reportData.Manutencao = consolidado.planos[localidade]
+		reportData.Calendario = g.montarCalendario([]map[string]*domain.Summary{dadosLocalidade}, cultosLocalidade(cultos, cultosGerais, localidade))
+		relatorios[localidade] = reportData
+		documentos = append(documentos, documento{
+			Nome:    fmt.Sprintf("relatório da localidade %s", localidade),
+			Caminho: outputPath,
+			Chave:   g.chaveDocumento("localidade", reportData),
+			gerar: func() error {
+				return g.pdfService.GenerateLocalidadeReport(reportData, outputPath)
+			},
+		})
+	}
+
+	// Relatório resumo
+	resumo := g.summaryReportData(localidades, livros, rankingCumprimento(cumprimentos, OrdenarChaves(localidades)), saudes)
+	documentos = append(documentos, documento{
+		Nome:    "relatório resumo",
+		Caminho: g.summaryOutputPath(),
+		Chave:   g.chaveDocumento("resumo", resumo),
+		gerar: func() error {
+			return g.pdfService.GenerateSummaryReport(resumo, g.summaryOutputPath())
+		},
+	})
+
+	// Relatórios consolidados das administrações
+	administracoes, err := g.documentosAdministracoes(snapshot, grupos, cumprimentos)
+	if err != nil {
+		return nil, err
+	}
+	documentos = append(documentos, administracoes...)
+
+	// Classificação das localidades
+	if g.classificacao {
+		classificacao, err := g.classificacaoReportData(snapshot, anterior, livros, metas)
 		if err != nil {
-			return fmt.Errorf("erro ao gerar relatório para localidade %s: %v", localidade, err)
+			return nil, err
+		}
+		documentos = append(documentos, g.documentosClassificacao(classificacao)...)
+	}
+
+	// Resumos dos setores
+	documentos = append(documentos, g.documentosResumosSetores(snapshot, grupos, livros, cumprimentos, saudes, cultosGerais)...)
+
+	if g.pacoteService != nil {
+		documentos = append(documentos, g.documentosSetores(snapshot, grupos)...)
+	}
+
+	if g.documentoCompleto {
+		documentos = append(documentos, g.documentoCompletoDocumento(g.combinedReportData(snapshot, grupos, livros, cumprimentos, relatorios)))
+	}
+
+	chaves := make(map[string]string, len(documentos))
+	for _, doc := range documentos {
+		chaves[doc.Caminho] = doc.Chave
+	}
+	documentos, mantidos := g.reaproveitar(documentos)
+
+	etapa = time.Now()
+	g.logger.Info("gerando documentos", "documentos", len(documentos), "workers", g.workers)
+	resultado := g.gerarDocumentos(ctx, documentos)
+	resultado.Mantidos = mantidos
+	g.logger.Info("etapa concluída", "etapa", "geracao",
+		"gerados", len(resultado.Gerados),
+		"mantidos", len(resultado.Mantidos),
+		"falhas", len(resultado.Falhas),
+		"duracao", time.Since(etapa))
+
+	resultado.Leitura = g.localidadeRepo.Estatisticas()
+
+	if g.manifestService != nil {
+		if err := g.writeManifest(resultado, contagemAlertas, chaves); err != nil {
+			return nil, fmt.Errorf("erro ao gravar manifesto: %v", err)
+		}
+	}
+
+	// Os pacotes são montados depois do manifesto, que vai dentro de cada um
+	if g.pacoteService != nil {
+		etapa = time.Now()
+		documentosPacotes, pacotesMantidos := g.documentosPacotes(resultado, grupos)
+		pacotes := g.gerarDocumentos(ctx, documentosPacotes)
+		resultado.Pacotes = append(pacotes.Gerados, pacotesMantidos...)
+		resultado.Falhas = append(resultado.Falhas, pacotes.Falhas...)
+		g.logger.Info("etapa concluída", "etapa", "pacotes",
+			"gerados", len(pacotes.Gerados),
+			"falhas", len(pacotes.Falhas),
+			"duracao", time.Since(etapa))
+	}
+
+	if g.historicoRepo != nil {
+		// O histórico é servido pela API e comparado entre períodos; os
+		// voluntários são gravados pela chave da política de privacidade
+		if err := g.historicoRepo.Save(pseudonimizar(snapshot, g.privacidade)); err != nil {
+			return nil, fmt.Errorf("erro ao gravar histórico: %v", err)
 		}
 	}
+	if g.brigadaRepo != nil {
+		if err := g.brigadaRepo.Save(consolidado.registroBrigada); err != nil {
+			return nil, fmt.Errorf("erro ao gravar registro da brigada: %v", err)
+		}
+	}
+
+	resultado.Duracao = time.Since(inicio)
+	return resultado, nil
+}
+
+// consolidacao reúne o snapshot de uma leitura da listagem e as avaliações
+// feitas com ele, comuns a Snapshot e GenerateReports
+type consolidacao struct {
+	snapshot  *domain.Snapshot
+	validacao *Validacao
+	planos    map[string]*PlanoLocalidade
+	brigadas  map[string]*BrigadaLocalidade
+	// registroBrigada é o registro atualizado, gravado por GenerateReports
+	registroBrigada domain.RegistroBrigada
+}
+
+// Snapshot lê a listagem, resolve os nomes das localidades e calcula os
+// alertas de cada uma, inclusive os do plano de manutenção e da brigada, como
+// são gravados no histórico por GenerateReports. Não gera documentos nem grava
+// o registro da brigada.
+func (g *ReportGenerator) Snapshot() (*domain.Snapshot, error) {
+	c, err := g.consolidar()
+	if err != nil {
+		return nil, err
+	}
+	return c.snapshot, nil
+}
+
+// consolidar lê e resolve a listagem e avalia os alertas, os planos de
+// manutenção e a brigada de cada localidade
+func (g *ReportGenerator) consolidar() (*consolidacao, error) {
+	if err := domain.ValidarPeriodo(g.periodo); err != nil {
+		return nil, err
+	}
+
+	etapa := time.Now()
+	localidades, err := g.localidadeRepo.GetAll()
+	if err != nil {
+		return nil, fmt.Errorf("erro ao obter localidades: %v", err)
+	}
+	g.logger.Info("etapa concluída", "etapa", "leitura", "localidades", len(localidades), "duracao", time.Since(etapa))
 
-	// Gera relatório resumo
-	err = g.generateSummaryReport(localidades, livros)
+	etapa = time.Now()
+	localidades, administracoes, validacao, err := g.resolveLocalidades(localidades, g.localidadeRepo.Administracoes())
 	if err != nil {
-		return fmt.Errorf("erro ao gerar relatório resumo: %v", err)
+		return nil, fmt.Errorf("erro ao resolver localidades: %v", err)
+	}
+	g.logger.Info("etapa concluída", "etapa", "resolucao",
+		"localidades", len(localidades),
+		"nao_resolvidas", len(validacao.NaoResolvidas()),
+		"duracao", time.Since(etapa))
+
+	c := &consolidacao{
+		snapshot: &domain.Snapshot{
+			Periodo:        g.periodo,
+			GeradoEm:       time.Now(),
+			Localidades:    localidades,
+			Alertas:        make(map[string][]domain.Alerta, len(localidades)),
+			Administracoes: administracoes,
+		},
+		validacao: validacao,
+	}
+	if g.planoRepo != nil {
+		if c.planos, err = g.avaliarPlanos(localidades); err != nil {
+			return nil, err
+		}
+	}
+	if g.brigadaRepo != nil {
+		if c.brigadas, c.registroBrigada, err = g.avaliarBrigadas(localidades); err != nil {
+			return nil, err
+		}
+	}
+	for localidade, livros := range localidades {
+		plano, comPlano := c.planos[localidade]
+		alertas := avaliarAlertas(livros, g.metrica, g.minimoManutencaoMetrica(), comPlano)
+		if comPlano {
+			alertas = append(alertas, alertasPlano(plano)...)
+		}
+		if brigada, exists := c.brigadas[localidade]; exists {
+			alertas = append(alertas, alertasBrigada(brigada)...)
+		}
+		c.snapshot.Alertas[localidade] = alertas
 	}
 
-	return nil
+	return c, nil
 }
 
-func (g *ReportGenerator) generateLocalidadeReport(
+// localidadeReportData monta o relatório da localidade; esperados são os
+// livros cadastrados para ela, listados mesmo sem apontamentos
+func (g *ReportGenerator) localidadeReportData(
 	localidade string,
 	dados map[string]*domain.Summary,
-	setor *domain.Setor,
-) error {
+	esperados map[string]bool,
+	alertas []domain.Alerta,
+	cumprimento *CumprimentoLocalidade,
+) *ReportData {
 	config := &domain.RelatorioConfig{
-		LarguraLivro:          100.0,
-		LarguraTotalTrabalhos: 45.0,
-		LarguraApontamentos:   45.0,
+		LarguraLivro:          68.0,
+		LarguraTotalTrabalhos: 26.0,
+		LarguraHoras:          20.0,
+		LarguraVoluntarios:    24.0,
+		LarguraApontamentos:   52.0,
 		MargemPagina:          10.0,
 	}
-
-	reportData := &ReportData{
-		Titulo:     fmt.Sprintf("Relatório - %s", localidade),
-		Data:       time.Now(),
-		Localidade: localidade,
-		Livros:     dados,
-		Config:     config,
+	// Com metas, as colunas de meta e cumprimento ocupam parte da largura
+	if cumprimento != nil {
+		config.LarguraLivro = 56.0
+		config.LarguraTotalTrabalhos = 22.0
+		config.LarguraHoras = 16.0
+		config.LarguraVoluntarios = 22.0
+		config.LarguraApontamentos = 42.0
+		config.LarguraMeta = 16.0
+		config.LarguraCumprimento = 16.0
 	}
 
-	outputPath := g.getOutputPath(setor, localidade)
-	return g.pdfService.GenerateLocalidadeReport(reportData, outputPath)
+	return &ReportData{
+		Titulo:      fmt.Sprintf("Relatório - %s", localidade),
+		Data:        time.Now(),
+		Localidade:  localidade,
+		Periodo:     g.periodo,
+		Metrica:     g.metrica,
+		Livros:      dados,
+		Esperados:   esperados,
+		SemCadastro: livrosSemCadastro(dados, esperados),
+		Alertas:     alertas,
+		Cumprimento: cumprimento,
+		Config:      config,
+	}
 }
 
-func (g *ReportGenerator) generateSummaryReport(
+func (g *ReportGenerator) summaryReportData(
 	localidades map[string]map[string]*domain.Summary,
 	livros map[string]map[string]bool,
-) error {
-	reportData := &ReportData{
-		Titulo:      "Resumo de Todas as Localidades",
-		Data:        time.Now(),
-		Localidades: localidades,
-		LivrosMap:   livros,
+	ranking []*CumprimentoLocalidade,
+	saudes map[string]*SaudeLocalidade,
+) *ReportData {
+	return &ReportData{
+		Titulo:           "Resumo de Todas as Localidades",
+		Data:             time.Now(),
+		Periodo:          g.periodo,
+		Metrica:          g.metrica,
+		Localidades:      localidades,
+		LivrosMap:        livros,
+		Totais:           consolidarLivros(localidades, OrdenarChaves(localidades)),
+		Ranking:          ranking,
+		SaudeLocalidades: saudes,
 	}
-
-	return g.pdfService.GenerateSummaryReport(reportData, "./files/output/resumo_localidades.pdf")
 }
 
 func (g *ReportGenerator) getOutputPath(setor *domain.Setor, localidade string) string {
-	diretorio := "./files/output/outros"
+	diretorio := filepath.Join(g.outputDir, diretorioOutros)
 	if setor != nil {
-		diretorio = fmt.Sprintf("./files/output/%s", setor.Responsavel)
+		diretorio = filepath.Join(g.outputDir, setor.Responsavel)
 	}
-	return fmt.Sprintf("%s/relatorio-%s.pdf", diretorio, localidade)
+	return filepath.Join(diretorio, fmt.Sprintf("relatorio-%s.pdf", localidade))
+}
+
+func (g *ReportGenerator) summaryOutputPath() string {
+	return filepath.Join(g.outputDir, "resumo_localidades.pdf")
 }
 
 // ReportData contém os dados necessários para gerar um relatório
 type ReportData struct {
-	Titulo      string
-	Data        time.Time
-	Localidade  string
-	Livros      map[string]*domain.Summary
+	Titulo     string
+	Data       time.Time `json:"-"`
+	Periodo    string
+	Metrica    domain.Metrica
+	Localidade string
+	Livros     map[string]*domain.Summary
+	// Esperados são os livros cadastrados para a localidade
+	Esperados map[string]bool
+	// SemCadastro são os livros com apontamentos que não estão cadastrados
+	SemCadastro []string
+	Alertas     []domain.Alerta
 	Localidades map[string]map[string]*domain.Summary
 	LivrosMap   map[string]map[string]bool
-	Config      *domain.RelatorioConfig
+	// Totais consolida os livros de todas as localidades do resumo
+	Totais map[string]*domain.Summary
+	// Cumprimento das metas da localidade; nil quando não há metas
+	Cumprimento *CumprimentoLocalidade
+	// Ranking das localidades do resumo pelo cumprimento das metas
+	Ranking []*CumprimentoLocalidade
+	// Apontamentos resume cada livro da localidade na coluna Apontamentos
+	Apontamentos map[string]*ApontamentoLivro
+	// Observacoes são as anotações do período impressas em OBSERVAÇÕES
+	Observacoes []string
+	// Cobertura traz a concentração dos livros e a escala de voluntários
+	Cobertura *CoberturaLocalidade
+	// Brigada traz a validade dos treinamentos; nil sem o registro da brigada
+	Brigada *BrigadaLocalidade
+	// Manutencao traz a grade do plano de manutenção; nil sem plano
+	Manutencao *PlanoLocalidade
+	// Calendario é o mapa de calor da atividade no período, da localidade
+	// ou, no resumo do setor, do setor
+	Calendario *Calendario
+	// Saude é a nota de saúde da localidade, com a composição
+	Saude *SaudeLocalidade
+	// SaudeLocalidades traz a nota de cada localidade do resumo
+	SaudeLocalidades map[string]*SaudeLocalidade
+	Config           *domain.RelatorioConfig
+}
+
+// OrdenarChaves retorna as chaves do mapa em ordem alfabética
+func OrdenarChaves[V any](m map[string]V) []string {
+	chaves := make([]string, 0, len(m))
+	for chave := range m {
+		chaves = append(chaves, chave)
+	}
+	sort.Strings(chaves)
+	return chaves
 }
@@ -0,0 +1,212 @@
+package usecase
+
+import (
+	"io"
+	"io/fs"
+	"log/slog"
+	"sync"
+
+	"report/internal/domain"
+)
+
+// loggerTeste descarta os registros gerados durante os testes
+var loggerTeste = slog.New(slog.NewTextHandler(io.Discard, nil))
+
+// localidadeRepoMemoria implementa LocalidadeRepository sobre os livros de
+// cada localidade
+type localidadeRepoMemoria map[string]map[string]*domain.Summary
+
+func (r localidadeRepoMemoria) GetAll() (map[string]map[string]*domain.Summary, error) {
+	return r, nil
+}
+
+func (r localidadeRepoMemoria) Save(localidade *domain.Localidade) error {
+	return nil
+}
+
+func (r localidadeRepoMemoria) Estatisticas() domain.EstatisticasLeitura {
+	return domain.EstatisticasLeitura{}
+}
+
+func (r localidadeRepoMemoria) Administracoes() map[string]string {
+	return nil
+}
+
+// setorRepoMemoria implementa SetorRepository sobre um mapa de setores
+type setorRepoMemoria map[string]*domain.Setor
+
+func (r setorRepoMemoria) GetAll() (map[string]*domain.Setor, error) {
+	localidades := make(map[string]*domain.Setor)
+	for _, setor := range r {
+		for _, localidade := range setor.Localidades {
+			localidades[localidade] = setor
+		}
+	}
+	return localidades, nil
+}
+
+func (r setorRepoMemoria) GetByLocalidade(localidade string) (*domain.Setor, error) {
+	localidades, _ := r.GetAll()
+	return localidades[localidade], nil
+}
+
+func (r setorRepoMemoria) GetAdministracoes() (map[string]*domain.Administracao, error) {
+	return nil, nil
+}
+
+// aliasRepoMemoria implementa AliasRepository em memória, registrando as
+// gravações para conferência
+type aliasRepoMemoria struct {
+	aliases   map[string]string
+	pendentes []domain.SugestaoAlias
+	gravados  []string
+}
+
+func (r *aliasRepoMemoria) GetAll() (map[string]string, error) {
+	aliases := make(map[string]string, len(r.aliases))
+	for alias, localidade := range r.aliases {
+		aliases[alias] = localidade
+	}
+	return aliases, nil
+}
+
+func (r *aliasRepoMemoria) Save(alias, localidade string) error {
+	if r.aliases == nil {
+		r.aliases = make(map[string]string)
+	}
+	r.aliases[alias] = localidade
+	r.gravados = append(r.gravados, alias)
+	return nil
+}
+
+func (r *aliasRepoMemoria) GetPendentes() ([]domain.SugestaoAlias, error) {
+	return r.pendentes, nil
+}
+
+func (r *aliasRepoMemoria) SavePendentes(sugestoes []domain.SugestaoAlias) error {
+	r.pendentes = sugestoes
+	return nil
+}
+
+// livroRepoMemoria implementa LivroRepository sobre os livros de cada localidade
+type livroRepoMemoria map[string]map[string]bool
+
+func (r livroRepoMemoria) GetByLocalidade(localidade string) (map[string]bool, error) {
+	return r[localidade], nil
+}
+
+func (r livroRepoMemoria) GetAll() (map[string]map[string]bool, error) {
+	return r, nil
+}
+
+// historicoRepoMemoria implementa HistoricoRepository sobre um mapa de snapshots por período
+type historicoRepoMemoria map[string]*domain.Snapshot
+
+func (r historicoRepoMemoria) Save(snapshot *domain.Snapshot) error {
+	if err := domain.ValidarPeriodo(snapshot.Periodo); err != nil {
+		return err
+	}
+	r[snapshot.Periodo] = snapshot
+	return nil
+}
+
+func (r historicoRepoMemoria) Get(periodo string) (*domain.Snapshot, error) {
+	snapshot, exists := r[periodo]
+	if !exists {
+		return nil, fs.ErrNotExist
+	}
+	return snapshot, nil
+}
+
+func (r historicoRepoMemoria) GetAnterior(periodo string) (*domain.Snapshot, error) {
+	periodos, _ := r.List()
+	for i := len(periodos) - 1; i >= 0; i-- {
+		if periodos[i] < periodo {
+			return r[periodos[i]], nil
+		}
+	}
+	return nil, nil
+}
+
+func (r historicoRepoMemoria) List() ([]string, error) {
+	return OrdenarChaves(r), nil
+}
+
+// brigadaRepoMemoria implementa BrigadaRepository em memória, contando as gravações
+type brigadaRepoMemoria struct {
+	registro  domain.RegistroBrigada
+	gravacoes int
+}
+
+func (r *brigadaRepoMemoria) Get() (domain.RegistroBrigada, error) {
+	registro := make(domain.RegistroBrigada)
+	for localidade, voluntarios := range r.registro {
+		for voluntario, datas := range voluntarios {
+			for _, data := range datas {
+				registro.Registrar(localidade, voluntario, data)
+			}
+		}
+	}
+	return registro, nil
+}
+
+func (r *brigadaRepoMemoria) Save(registro domain.RegistroBrigada) error {
+	r.registro = registro
+	r.gravacoes++
+	return nil
+}
+
+// envioRepoMemoria implementa EnvioRepository em memória
+type envioRepoMemoria map[string]*domain.Envio
+
+func (r envioRepoMemoria) Get(setor, periodo string) (*domain.Envio, error) {
+	return r[setor+"/"+periodo], nil
+}
+
+func (r envioRepoMemoria) Save(envio *domain.Envio) error {
+	r[envio.Setor+"/"+envio.Periodo] = envio
+	return nil
+}
+
+// pdfMemoria implementa PDFService guardando os dados de cada documento
+// gerado, por caminho, sem gravar nenhum arquivo
+type pdfMemoria struct {
+	mu         sync.Mutex
+	documentos map[string]interface{}
+}
+
+func (s *pdfMemoria) registrar(caminho string, dados interface{}) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.documentos == nil {
+		s.documentos = make(map[string]interface{})
+	}
+	s.documentos[caminho] = dados
+	return nil
+}
+
+func (s *pdfMemoria) Modelo() string { return "teste" }
+
+func (s *pdfMemoria) GenerateLocalidadeReport(data *ReportData, outputPath string) error {
+	return s.registrar(outputPath, data)
+}
+
+func (s *pdfMemoria) GenerateSummaryReport(data *ReportData, outputPath string) error {
+	return s.registrar(outputPath, data)
+}
+
+func (s *pdfMemoria) GenerateDiffReport(diff *Diferenca, outputPath string) error {
+	return s.registrar(outputPath, diff)
+}
+
+func (s *pdfMemoria) GenerateCombinedReport(data *CombinedReportData, outputPath string) error {
+	return s.registrar(outputPath, data)
+}
+
+func (s *pdfMemoria) GenerateAdministracaoReport(data *AdministracaoReportData, outputPath string) error {
+	return s.registrar(outputPath, data)
+}
+
+func (s *pdfMemoria) GenerateClassificacaoReport(data *ClassificacaoReportData, outputPath string) error {
+	return s.registrar(outputPath, data)
+}
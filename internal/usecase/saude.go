@@ -0,0 +1,159 @@
+package usecase
+
+import (
+	"fmt"
+	"math"
+	"strings"
+
+	"report/internal/domain"
+)
+
+// Pontos descontados do componente de alertas por alerta, conforme a severidade
+const (
+	penalidadeAlta  = 40.0
+	penalidadeMedia = 20.0
+	penalidadeBaixa = 10.0
+)
+
+// Faixas da nota de saúde
+const (
+	notaBoa     = 80
+	notaAtencao = 60
+)
+
+// ComponenteSaude é uma linha da composição da nota de saúde
+type ComponenteSaude struct {
+	Nome string
+	// Valor vai de 0 a 100
+	Valor float64
+	Peso  float64
+	// Pontos é a parcela da nota que vem do componente
+	Pontos  float64
+	Detalhe string
+	// Aplicavel é falso quando a localidade não tem dados para o componente,
+	// cujo peso é redistribuído entre os demais
+	Aplicavel bool
+}
+
+// SaudeLocalidade é a nota de 0 a 100 que combina os componentes ponderados
+type SaudeLocalidade struct {
+	Nota        int
+	Componentes []ComponenteSaude
+}
+
+// Faixa classifica a nota em boa, atenção ou crítica
+func (s *SaudeLocalidade) Faixa() string {
+	switch {
+	case s.Nota >= notaBoa:
+		return "boa"
+	case s.Nota >= notaAtencao:
+		return "atenção"
+	default:
+		return "crítica"
+	}
+}
+
+// WithPesosSaude define os pesos dos componentes da nota de saúde
+func WithPesosSaude(pesos domain.PesosSaude) Option {
+	return func(g *ReportGenerator) {
+		g.pesosSaude = pesos
+	}
+}
+
+// avaliarSaude calcula a nota de saúde da localidade: a cobertura dos livros
+// cadastrados, o cumprimento das metas, os alertas pesados pela severidade e
+// a tendência em relação ao período anterior. Componentes sem dados ficam de
+// fora e a nota é a média ponderada dos demais.
+func avaliarSaude(
+	pesos domain.PesosSaude,
+	dados map[string]*domain.Summary,
+	esperados map[string]bool,
+	cumprimento *CumprimentoLocalidade,
+	alertas []domain.Alerta,
+	anteriores map[string]*domain.Summary,
+	comAnterior bool,
+	metrica domain.Metrica,
+) *SaudeLocalidade {
+	cobertura := ComponenteSaude{Nome: "Cobertura dos livros", Peso: pesos.Cobertura, Detalhe: "sem livros cadastrados"}
+	if len(esperados) > 0 {
+		atendidos := 0
+		for livro := range esperados {
+			if _, exists := dados[livro]; exists {
+				atendidos++
+			}
+		}
+		cobertura.Valor = float64(atendidos) / float64(len(esperados)) * 100
+		cobertura.Detalhe = fmt.Sprintf("%d de %d livros cadastrados com apontamentos", atendidos, len(esperados))
+		cobertura.Aplicavel = true
+	}
+
+	metas := ComponenteSaude{Nome: "Metas", Peso: pesos.Metas, Detalhe: "sem metas"}
+	if cumprimento != nil {
+		metas.Valor = cumprimento.Percentual
+		metas.Detalhe = fmt.Sprintf("cumprimento geral dos %d livros com meta", len(cumprimento.Livros))
+		metas.Aplicavel = true
+	}
+
+	pontosAlertas := ComponenteSaude{Nome: "Alertas", Peso: pesos.Alertas, Valor: 100, Detalhe: "nenhum alerta", Aplicavel: true}
+	contagem := make(map[domain.Severidade]int)
+	for _, alerta := range alertas {
+		severidade := alerta.Severidade
+		switch severidade {
+		case domain.SeveridadeAlta:
+			pontosAlertas.Valor -= penalidadeAlta
+		case domain.SeveridadeBaixa:
+			pontosAlertas.Valor -= penalidadeBaixa
+		default:
+			severidade = domain.SeveridadeMedia
+			pontosAlertas.Valor -= penalidadeMedia
+		}
+		contagem[severidade]++
+	}
+	pontosAlertas.Valor = max(pontosAlertas.Valor, 0)
+	if len(alertas) > 0 {
+		var partes []string
+		for _, severidade := range []domain.Severidade{domain.SeveridadeAlta, domain.SeveridadeMedia, domain.SeveridadeBaixa} {
+			if n := contagem[severidade]; n > 0 {
+				partes = append(partes, fmt.Sprintf("%d de severidade %s", n, severidade))
+			}
+		}
+		pontosAlertas.Detalhe = strings.Join(partes, ", ")
+	}
+
+	tendencia := ComponenteSaude{Nome: "Tendência", Peso: pesos.Tendencia, Detalhe: "sem período anterior"}
+	if comAnterior {
+		atual, anterior := 0.0, 0.0
+		for _, summary := range dados {
+			atual += summary.Valor(metrica)
+		}
+		for _, summary := range anteriores {
+			anterior += summary.Valor(metrica)
+		}
+		if anterior > 0 {
+			tendencia.Valor = min(atual/anterior, 1) * 100
+			tendencia.Detalhe = fmt.Sprintf("%.0f%% dos %s do período anterior", atual/anterior*100, metrica.Unidade())
+			tendencia.Aplicavel = true
+		}
+	}
+
+	saude := &SaudeLocalidade{Componentes: []ComponenteSaude{cobertura, metas, pontosAlertas, tendencia}}
+	total := 0.0
+	for _, componente := range saude.Componentes {
+		if componente.Aplicavel {
+			total += componente.Peso
+		}
+	}
+	if total == 0 {
+		return nil
+	}
+	nota := 0.0
+	for i := range saude.Componentes {
+		componente := &saude.Componentes[i]
+		if componente.Aplicavel {
+			componente.Pontos = componente.Valor * componente.Peso / total
+			nota += componente.Pontos
+		}
+	}
+	saude.Nota = int(math.Round(nota))
+	return saude
+}
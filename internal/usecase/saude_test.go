@@ -0,0 +1,103 @@
+package usecase
+
+import (
+	"testing"
+
+	"report/internal/domain"
+)
+
+func TestAvaliarSaude(t *testing.T) {
+	dados := map[string]*domain.Summary{"LIMPEZA": {TotalTrabalhos: 4}}
+
+	tests := []struct {
+		nome        string
+		pesos       domain.PesosSaude
+		esperados   map[string]bool
+		cumprimento *CumprimentoLocalidade
+		alertas     []domain.Alerta
+		anteriores  map[string]*domain.Summary
+		comAnterior bool
+		// nota é -1 quando nenhum componente se aplica
+		nota         int
+		faixa        string
+		aplicaveis   [4]bool
+		detalheAlert string
+	}{
+		{
+			nome:         "todos os componentes",
+			pesos:        domain.PesosSaudePadrao,
+			esperados:    map[string]bool{"LIMPEZA": true, "JARDINAGEM": true},
+			cumprimento:  &CumprimentoLocalidade{Percentual: 80},
+			alertas:      []domain.Alerta{{Severidade: domain.SeveridadeAlta}, {Severidade: domain.SeveridadeBaixa}},
+			anteriores:   map[string]*domain.Summary{"LIMPEZA": {TotalTrabalhos: 8}},
+			comAnterior:  true,
+			nota:         59,
+			faixa:        "crítica",
+			aplicaveis:   [4]bool{true, true, true, true},
+			detalheAlert: "1 de severidade alta, 1 de severidade baixa",
+		},
+		{
+			nome:         "sem metas nem período anterior, pesos redistribuídos",
+			pesos:        domain.PesosSaudePadrao,
+			esperados:    map[string]bool{"LIMPEZA": true},
+			nota:         100,
+			faixa:        "boa",
+			aplicaveis:   [4]bool{true, false, true, false},
+			detalheAlert: "nenhum alerta",
+		},
+		{
+			nome:         "tendência limitada a 100 e severidade desconhecida como média",
+			pesos:        domain.PesosSaude{Alertas: 50, Tendencia: 50},
+			alertas:      []domain.Alerta{{}},
+			anteriores:   map[string]*domain.Summary{"LIMPEZA": {TotalTrabalhos: 2}},
+			comAnterior:  true,
+			nota:         90,
+			faixa:        "boa",
+			aplicaveis:   [4]bool{false, false, true, true},
+			detalheAlert: "1 de severidade media",
+		},
+		{
+			nome:         "alertas não descontam abaixo de zero; sem apontamentos antes, sem tendência",
+			pesos:        domain.PesosSaudePadrao,
+			alertas:      []domain.Alerta{{Severidade: domain.SeveridadeAlta}, {Severidade: domain.SeveridadeAlta}, {Severidade: domain.SeveridadeAlta}},
+			anteriores:   map[string]*domain.Summary{},
+			comAnterior:  true,
+			nota:         0,
+			faixa:        "crítica",
+			aplicaveis:   [4]bool{false, false, true, false},
+			detalheAlert: "3 de severidade alta",
+		},
+		{
+			nome:  "sem componentes com peso",
+			pesos: domain.PesosSaude{Cobertura: 10},
+			nota:  -1,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.nome, func(t *testing.T) {
+			saude := avaliarSaude(tt.pesos, dados, tt.esperados, tt.cumprimento, tt.alertas, tt.anteriores, tt.comAnterior, domain.MetricaLancamentos)
+			if tt.nota < 0 {
+				if saude != nil {
+					t.Fatalf("saúde = %+v, esperado nil", saude)
+				}
+				return
+			}
+			if saude.Nota != tt.nota || saude.Faixa() != tt.faixa {
+				t.Errorf("nota = %d (%s), esperado %d (%s)", saude.Nota, saude.Faixa(), tt.nota, tt.faixa)
+			}
+			pontos := 0.0
+			for i, componente := range saude.Componentes {
+				if componente.Aplicavel != tt.aplicaveis[i] {
+					t.Errorf("%s aplicável = %v, esperado %v", componente.Nome, componente.Aplicavel, tt.aplicaveis[i])
+				}
+				pontos += componente.Pontos
+			}
+			if int(pontos+0.5) != saude.Nota {
+				t.Errorf("soma dos pontos = %g, nota %d", pontos, saude.Nota)
+			}
+			if detalhe := saude.Componentes[2].Detalhe; detalhe != tt.detalheAlert {
+				t.Errorf("detalhe dos alertas = %q, esperado %q", detalhe, tt.detalheAlert)
+			}
+		})
+	}
+}
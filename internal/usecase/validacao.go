@@ -0,0 +1,210 @@
+package usecase
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"report/internal/domain"
+)
+
+// Validacao reúne as ocorrências encontradas durante a leitura dos dados
+type Validacao struct {
+	Resolucoes []Resolucao
+}
+
+// NaoResolvidas retorna os nomes de localidade que não foram reconhecidos
+func (v *Validacao) NaoResolvidas() []Resolucao {
+	var resolucoes []Resolucao
+	for _, r := range v.Resolucoes {
+		if r.Metodo == ResolucaoPendente || r.Metodo == ResolucaoNenhuma {
+			resolucoes = append(resolucoes, r)
+		}
+	}
+	return resolucoes
+}
+
+// String formata a validação para exibição no console
+func (v *Validacao) String() string {
+	var b strings.Builder
+	for _, r := range v.Resolucoes {
+		switch r.Metodo {
+		case ResolucaoAlias, ResolucaoConfirmada:
+			fmt.Fprintf(&b, "Localidade \"%s\" associada a %s (%s)\n", r.Original, r.Localidade, r.Metodo)
+		case ResolucaoPendente:
+			fmt.Fprintf(&b, "Localidade \"%s\" não reconhecida. Sugestões:\n", r.Original)
+			for _, s := range r.Sugestoes {
+				fmt.Fprintf(&b, "  - %s (%.0f%%)\n", s.Localidade, s.Similaridade*100)
+			}
+		case ResolucaoNenhuma:
+			fmt.Fprintf(&b, "Localidade \"%s\" não reconhecida e sem sugestões\n", r.Original)
+		}
+	}
+	return b.String()
+}
+
+// Validacao retorna a validação da última geração de relatórios
+func (g *ReportGenerator) Validacao() *Validacao {
+	return g.validacao
+}
+
+// resolveLocalidades associa os nomes da listagem às localidades cadastradas,
+// agrupando os dados de nomes diferentes que correspondem à mesma localidade.
+// As administrações da listagem são associadas aos nomes resolvidos.
+func (g *ReportGenerator) resolveLocalidades(
+	localidades map[string]map[string]*domain.Summary,
+	administracoes map[string]string,
+) (map[string]map[string]*domain.Summary, map[string]string, *Validacao, error) {
+	setores, err := g.setorRepo.GetAll()
+	if err != nil {
+		return nil, nil, nil, fmt.Errorf("erro ao obter setores: %v", err)
+	}
+	conhecidas := make([]string, 0, len(setores))
+	for localidade := range setores {
+		conhecidas = append(conhecidas, localidade)
+	}
+
+	aliases := make(map[string]string)
+	if g.aliasRepo != nil {
+		if err := g.promoverAliasesConfirmados(); err != nil {
+			return nil, nil, nil, err
+		}
+		if aliases, err = g.aliasRepo.GetAll(); err != nil {
+			return nil, nil, nil, fmt.Errorf("erro ao obter apelidos: %v", err)
+		}
+	}
+
+	nomes := make([]string, 0, len(localidades))
+	for nome := range localidades {
+		nomes = append(nomes, nome)
+	}
+	sort.Strings(nomes)
+
+	resolver := NewLocalidadeResolver(conhecidas, aliases)
+	resolvidas := make(map[string]map[string]*domain.Summary)
+	administracoesResolvidas := make(map[string]string)
+	validacao := &Validacao{}
+	var pendentes []domain.SugestaoAlias
+
+	for _, nome := range nomes {
+		resolucao := resolver.Resolve(nome)
+		if resolucao.Metodo == ResolucaoPendente {
+			if err := g.confirmarSugestoes(resolver, &resolucao); err != nil {
+				return nil, nil, nil, err
+			}
+		}
+		if resolucao.Metodo != ResolucaoExata {
+			validacao.Resolucoes = append(validacao.Resolucoes, resolucao)
+		}
+		switch resolucao.Metodo {
+		case ResolucaoPendente:
+			pendentes = append(pendentes, resolucao.Sugestoes...)
+			g.logger.Warn("localidade não reconhecida", "nome", nome, "sugestao", resolucao.Sugestoes[0].Localidade)
+		case ResolucaoNenhuma:
+			g.logger.Warn("localidade não reconhecida", "nome", nome)
+		case ResolucaoAlias, ResolucaoConfirmada:
+			g.logger.Debug("localidade associada por apelido", "nome", nome, "localidade", resolucao.Localidade)
+		}
+
+		mergeLivros(resolvidas, resolucao.Localidade, localidades[nome])
+		if administracao := administracoes[nome]; administracao != "" {
+			administracoesResolvidas[resolucao.Localidade] = administracao
+		}
+	}
+
+	if g.aliasRepo != nil {
+		if err := g.aliasRepo.SavePendentes(pendentes); err != nil {
+			return nil, nil, nil, fmt.Errorf("erro ao salvar sugestões de apelidos: %v", err)
+		}
+	}
+
+	return resolvidas, administracoesResolvidas, validacao, nil
+}
+
+// confirmarSugestoes pergunta pela confirmação de cada sugestão, persistindo
+// a primeira aceita como apelido
+func (g *ReportGenerator) confirmarSugestoes(resolver *LocalidadeResolver, resolucao *Resolucao) error {
+	if g.confirmarAlias == nil {
+		return nil
+	}
+
+	for _, sugestao := range resolucao.Sugestoes {
+		if !g.confirmarAlias(sugestao) {
+			continue
+		}
+		if g.aliasRepo != nil {
+			if err := g.aliasRepo.Save(sugestao.Alias, sugestao.Localidade); err != nil {
+				return fmt.Errorf("erro ao salvar apelido %s: %v", sugestao.Alias, err)
+			}
+		}
+		resolver.AddAlias(sugestao.Alias, sugestao.Localidade)
+		resolucao.Localidade = sugestao.Localidade
+		resolucao.Metodo = ResolucaoConfirmada
+		resolucao.Sugestoes = nil
+		return nil
+	}
+
+	return nil
+}
+
+// promoverAliasesConfirmados incorpora à tabela de apelidos as sugestões
+// marcadas como confirmadas no arquivo de pendências
+func (g *ReportGenerator) promoverAliasesConfirmados() error {
+	pendentes, err := g.aliasRepo.GetPendentes()
+	if err != nil {
+		return fmt.Errorf("erro ao obter sugestões de apelidos: %v", err)
+	}
+
+	promovidos := make(map[string]bool)
+	for _, sugestao := range pendentes {
+		if !sugestao.Confirmado || promovidos[sugestao.Alias] {
+			continue
+		}
+		if err := g.aliasRepo.Save(sugestao.Alias, sugestao.Localidade); err != nil {
+			return fmt.Errorf("erro ao salvar apelido %s: %v", sugestao.Alias, err)
+		}
+		promovidos[sugestao.Alias] = true
+	}
+
+	return nil
+}
+
+func mergeLivros(destino map[string]map[string]*domain.Summary, localidade string, livros map[string]*domain.Summary) {
+	if _, exists := destino[localidade]; !exists {
+		destino[localidade] = make(map[string]*domain.Summary)
+	}
+	for livro, summary := range livros {
+		if _, exists := destino[localidade][livro]; !exists {
+			destino[localidade][livro] = &domain.Summary{
+				Voluntarios:                 make(map[string]int),
+				HorasVoluntarios:            make(map[string]float64),
+				UltimoLancamentoVoluntarios: make(map[string]time.Time),
+				LancamentosDia:              make(map[string]int),
+				HorasDia:                    make(map[string]float64),
+			}
+		}
+		destino[localidade][livro].TotalTrabalhos += summary.TotalTrabalhos
+		destino[localidade][livro].Horas += summary.Horas
+		if summary.UltimoLancamento.After(destino[localidade][livro].UltimoLancamento) {
+			destino[localidade][livro].UltimoLancamento = summary.UltimoLancamento
+		}
+		for voluntario, n := range summary.Voluntarios {
+			destino[localidade][livro].Voluntarios[voluntario] += n
+		}
+		for voluntario, horas := range summary.HorasVoluntarios {
+			destino[localidade][livro].HorasVoluntarios[voluntario] += horas
+		}
+		for voluntario, data := range summary.UltimoLancamentoVoluntarios {
+			if data.After(destino[localidade][livro].UltimoLancamentoVoluntarios[voluntario]) {
+				destino[localidade][livro].UltimoLancamentoVoluntarios[voluntario] = data
+			}
+		}
+		for dia, n := range summary.LancamentosDia {
+			destino[localidade][livro].LancamentosDia[dia] += n
+		}
+		for dia, horas := range summary.HorasDia {
+			destino[localidade][livro].HorasDia[dia] += horas
+		}
+	}
+}
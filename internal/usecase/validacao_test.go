@@ -0,0 +1,103 @@
+package usecase
+
+import (
+	"reflect"
+	"testing"
+
+	"report/internal/domain"
+)
+
+func TestResolveLocalidades(t *testing.T) {
+	setores := setorRepoMemoria{"SETOR 1": {Nome: "SETOR 1", Localidades: []string{"VILA NOVA", "CHACARAS REUNIDAS"}}}
+	livros := func(n int) map[string]*domain.Summary {
+		return map[string]*domain.Summary{"LIMPEZA": {TotalTrabalhos: n, Voluntarios: map[string]int{"ANA": n}}}
+	}
+
+	tests := []struct {
+		nome      string
+		aliases   map[string]string
+		pendentes []domain.SugestaoAlias
+		confirmar bool
+		// esperados
+		trabalhos         map[string]int
+		gravados          []string
+		metodos           []MetodoResolucao
+		pendentesGravados int
+	}{
+		{
+			nome:              "sugestão fica pendente",
+			trabalhos:         map[string]int{"VILA NOVA": 2, "CHACARA REUNIDAS": 3},
+			metodos:           []MetodoResolucao{ResolucaoPendente},
+			pendentesGravados: 1,
+		},
+		{
+			nome:      "apelido cadastrado agrupa os dados",
+			aliases:   map[string]string{"CHACARA REUNIDAS": "CHACARAS REUNIDAS"},
+			trabalhos: map[string]int{"VILA NOVA": 2, "CHACARAS REUNIDAS": 3},
+			metodos:   []MetodoResolucao{ResolucaoAlias},
+		},
+		{
+			nome:      "confirmação interativa grava o apelido",
+			confirmar: true,
+			trabalhos: map[string]int{"VILA NOVA": 2, "CHACARAS REUNIDAS": 3},
+			gravados:  []string{"CHACARA REUNIDAS"},
+			metodos:   []MetodoResolucao{ResolucaoConfirmada},
+		},
+		{
+			nome: "pendência confirmada é promovida uma única vez",
+			pendentes: []domain.SugestaoAlias{
+				{Alias: "CHACARA REUNIDAS", Localidade: "CHACARAS REUNIDAS", Confirmado: true},
+				{Alias: "CHACARA REUNIDAS", Localidade: "CHACARAS REUNIDAS", Confirmado: true},
+				{Alias: "VL NOVA", Localidade: "VILA NOVA"},
+			},
+			trabalhos: map[string]int{"VILA NOVA": 2, "CHACARAS REUNIDAS": 3},
+			gravados:  []string{"CHACARA REUNIDAS"},
+			metodos:   []MetodoResolucao{ResolucaoAlias},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.nome, func(t *testing.T) {
+			aliasRepo := &aliasRepoMemoria{aliases: tt.aliases, pendentes: tt.pendentes}
+			opts := []Option{WithAliasRepository(aliasRepo), WithLogger(loggerTeste)}
+			if tt.confirmar {
+				opts = append(opts, WithConfirmacaoAlias(func(domain.SugestaoAlias) bool { return true }))
+			}
+			g := NewReportGenerator(nil, setores, nil, nil, opts...)
+
+			resolvidas, administracoes, validacao, err := g.resolveLocalidades(
+				map[string]map[string]*domain.Summary{"VILA NOVA": livros(2), "CHACARA REUNIDAS": livros(3)},
+				map[string]string{"CHACARA REUNIDAS": "ADM 1"},
+			)
+			if err != nil {
+				t.Fatalf("resolveLocalidades: %v", err)
+			}
+
+			trabalhos := make(map[string]int)
+			for localidade, livros := range resolvidas {
+				trabalhos[localidade] = livros["LIMPEZA"].TotalTrabalhos
+			}
+			if !reflect.DeepEqual(trabalhos, tt.trabalhos) {
+				t.Errorf("localidades = %v, esperado %v", trabalhos, tt.trabalhos)
+			}
+			for localidade := range tt.trabalhos {
+				if localidade != "VILA NOVA" && administracoes[localidade] != "ADM 1" {
+					t.Errorf("administração de %s = %q, esperado ADM 1", localidade, administracoes[localidade])
+				}
+			}
+			if !reflect.DeepEqual(aliasRepo.gravados, tt.gravados) {
+				t.Errorf("apelidos gravados = %v, esperado %v", aliasRepo.gravados, tt.gravados)
+			}
+			var metodos []MetodoResolucao
+			for _, r := range validacao.Resolucoes {
+				metodos = append(metodos, r.Metodo)
+			}
+			if !reflect.DeepEqual(metodos, tt.metodos) {
+				t.Errorf("resoluções = %v, esperado %v", metodos, tt.metodos)
+			}
+			if len(aliasRepo.pendentes) != tt.pendentesGravados {
+				t.Errorf("%d pendências gravadas, esperado %d", len(aliasRepo.pendentes), tt.pendentesGravados)
+			}
+		})
+	}
+}
@@ -1,41 +1,333 @@
 package main
 
 import (
+	"bufio"
+	"context"
+	"flag"
 	"fmt"
-	"log"
+	"io"
+	"log/slog"
 	"os"
+	"os/signal"
+	"runtime"
+	"strconv"
+	"strings"
+	"syscall"
+	"time"
 
+	"report/internal/domain"
 	"report/internal/infrastructure"
 	"report/internal/usecase"
 )
 
+// defaultHistoricoDir é o diretório padrão do histórico de períodos
+const defaultHistoricoDir = "./files/history"
+
+// version é definida na compilação com -ldflags "-X main.version=..."
+var version = "dev"
+
 func main() {
-	// Configuração dos caminhos dos arquivos
-	inputPath := "./files/input.csv"
-	booksPath := "./files/books.csv"
+	args := os.Args[1:]
+	comando := "generate"
+	if len(args) > 0 && !strings.HasPrefix(args[0], "-") {
+		comando, args = args[0], args[1:]
+	}
 
-	// Verifica se os arquivos existem
-	if err := checkFiles(inputPath, booksPath); err != nil {
-		log.Fatal(err)
+	switch comando {
+	case "generate":
+		runGenerate(args)
+	case "verify":
+		runVerify(args)
+	case "diff":
+		runDiff(args)
+	case "watch":
+		runWatch(args)
+	case "serve":
+		runServe(args)
+	case "send":
+		runSend(args)
+	case "version":
+		fmt.Println(version)
+	default:
+		fmt.Fprintf(os.Stderr, "comando desconhecido: %s\n", comando)
+		fmt.Fprintln(os.Stderr, "comandos disponíveis: generate, watch, verify, diff, serve, send, version")
+		os.Exit(2)
 	}
+}
+
+// geracaoConfig reúne as opções compartilhadas pelos comandos que geram relatórios
+type geracaoConfig struct {
+	inputPath          string
+	booksPath          string
+	aliasesPath        string
+	pendentesPath      string
+	metasPath          string
+	observacoesPath    string
+	manutencaoPath     string
+	cultosPath         string
+	administracoesPath string
+	brigadaPath        string
+	brigadaValidade    int
+	codificacao        string
+	outputDir          string
+	historicoDir       string
+	periodo            string
+	metrica            domain.Metrica
+	minimoManutencao   float64
+	privacidade        domain.Privacidade
+	pesosSaude         domain.PesosSaude
+	workers            int
+	pacotes            bool
+	completo           bool
+	classificacao      bool
+	forcar             bool
+}
+
+// geracaoFlags registra em fs as opções de geração de relatórios
+func geracaoFlags(fs *flag.FlagSet) *geracaoConfig {
+	c := &geracaoConfig{}
+
+	// Configuração dos caminhos dos arquivos
+	fs.StringVar(&c.inputPath, "input", "./files/input.csv", "listagem de horas (CSV, XLS ou XLSX)")
+	fs.StringVar(&c.booksPath, "books", "./files/books.csv", "arquivo CSV com os livros por localidade")
+	fs.StringVar(&c.aliasesPath, "aliases", "./files/aliases.csv", "tabela de apelidos de localidades")
+	fs.StringVar(&c.pendentesPath, "aliases-pendentes", "./files/aliases_pendentes.csv", "sugestões de apelidos aguardando confirmação")
+	fs.StringVar(&c.metasPath, "metas", "./files/metas.csv", "metas mensais por livro, setor ou localidade")
+	fs.StringVar(&c.observacoesPath, "observacoes", "./files/observacoes.csv", "observações de cada localidade por período")
+	fs.StringVar(&c.manutencaoPath, "manutencao", "./files/manutencao.csv", "plano de manutenção preventiva por localidade")
+	fs.StringVar(&c.administracoesPath, "administracoes", "./files/administracoes.csv", "administração de cada setor (colunas setor,administracao)")
+	fs.StringVar(&c.cultosPath, "cultos", "./files/cultos.csv", "dias de culto de cada localidade, destacados no calendário")
+	fs.StringVar(&c.brigadaPath, "brigada", "./files/brigada.json", "registro dos treinamentos da Brigada de Incêndio")
+	fs.IntVar(&c.brigadaValidade, "brigada-validade", usecase.ValidadeBrigadaPadrao, "validade, em meses, do treinamento da Brigada de Incêndio")
+	c.codificacao = infrastructure.CodificacaoAuto
+	fs.Func("codificacao", "codificação da listagem em CSV: auto (padrão), utf-8, windows-1252 ou iso-8859-1", func(valor string) error {
+		codificacao, err := infrastructure.ParseCodificacao(valor)
+		c.codificacao = codificacao
+		return err
+	})
+	fs.StringVar(&c.outputDir, "output", usecase.DefaultOutputDir, "diretório dos relatórios gerados")
+	fs.StringVar(&c.historicoDir, "historico", defaultHistoricoDir, "diretório do histórico de períodos")
+	c.periodo = time.Now().Format(domain.LayoutPeriodo)
+	fs.Func("periodo", "período de referência dos relatórios: AAAA-MM (padrão: o mês atual)", func(valor string) error {
+		c.periodo = valor
+		return domain.ValidarPeriodo(valor)
+	})
+	c.metrica = domain.MetricaLancamentos
+	fs.Func("metrica", "métrica das regras e do resumo: lancamentos (padrão), horas ou voluntarios", func(valor string) error {
+		metrica, err := domain.ParseMetrica(valor)
+		c.metrica = metrica
+		return err
+	})
+	fs.Float64Var(&c.minimoManutencao, "minimo-manutencao", 0, "mínimo mensal de MANUTENÇÃO na métrica das regras (padrão: 8 lançamentos, 24 horas ou 3 voluntários)")
+	c.privacidade = domain.PrivacidadeIniciais
+	fs.Func("privacidade", "nomes dos voluntários na escala: iniciais (padrão), primeiro-nome ou completo", func(valor string) error {
+		privacidade, err := domain.ParsePrivacidade(valor)
+		c.privacidade = privacidade
+		return err
+	})
+	c.pesosSaude = domain.PesosSaudePadrao
+	fs.Func("pesos-saude", "pesos da nota de saúde, como cobertura=30,metas=30,alertas=25,tendencia=15", func(valor string) error {
+		pesos, err := domain.ParsePesosSaude(valor)
+		c.pesosSaude = pesos
+		return err
+	})
+	fs.IntVar(&c.workers, "workers", runtime.NumCPU(), "número de documentos gerados simultaneamente")
+	fs.BoolVar(&c.completo, "completo", false, "gera também um único PDF com capa, sumário, resumo e todos os relatórios")
+	fs.BoolVar(&c.forcar, "forcar", false, "gera todos os documentos, mesmo os que não mudaram desde a última execução")
+	fs.BoolVar(&c.classificacao, "classificacao", false, "gera a classificação das localidades, geral e por setor, em PDF e XLSX")
+	fs.BoolVar(&c.pacotes, "pacotes", false, "gera a planilha de cada setor e um ZIP por setor e um geral")
 
+	return c
+}
+
+// newReportGenerator monta o gerador de relatórios com os repositórios e
+// serviços da infraestrutura
+func (c *geracaoConfig) newReportGenerator(logger *slog.Logger, extra ...usecase.Option) (*usecase.ReportGenerator, error) {
 	// Inicializa os repositórios
-	localidadeRepo, setorRepo, livroRepo := infrastructure.NewCSVRepositories(inputPath, booksPath)
+	localidadeRepo, setorRepo, livroRepo := infrastructure.NewCSVRepositories(c.inputPath, c.booksPath, logger)
+	localidadeRepo.SetCodificacao(c.codificacao)
+	localidadeRepo.SetPeriodo(c.periodo)
+	if err := setorRepo.LoadAdministracoes(c.administracoesPath); err != nil {
+		return nil, err
+	}
+	aliasRepo := infrastructure.NewCSVAliasRepository(c.aliasesPath, c.pendentesPath)
+
+	// Inicializa os serviços de PDF e de manifesto
+	pdfService := infrastructure.NewGofpdfService(logger)
+	manifestService := infrastructure.NewJSONManifestService()
+
+	opts := []usecase.Option{
+		usecase.WithAliasRepository(aliasRepo),
+		usecase.WithMetaRepository(infrastructure.NewCSVMetaRepository(c.metasPath)),
+		usecase.WithObservacaoRepository(infrastructure.NewCSVObservacaoRepository(c.observacoesPath)),
+		usecase.WithPlanoManutencao(infrastructure.NewCSVPlanoManutencaoRepository(c.manutencaoPath)),
+		usecase.WithAgendaCultos(infrastructure.NewCSVAgendaCultosRepository(c.cultosPath)),
+		usecase.WithWorkers(c.workers),
+		usecase.WithLogger(logger),
+		usecase.WithOutputDir(c.outputDir),
+		usecase.WithPeriodo(c.periodo),
+		usecase.WithMetrica(c.metrica),
+		usecase.WithMinimoManutencao(c.minimoManutencao),
+		usecase.WithPrivacidade(c.privacidade),
+		usecase.WithPesosSaude(c.pesosSaude),
+		usecase.WithBrigada(infrastructure.NewJSONBrigadaRepository(c.brigadaPath), c.brigadaValidade),
+		usecase.WithHistorico(infrastructure.NewJSONHistoricoRepository(c.historicoDir)),
+		usecase.WithManifest(manifestService, usecase.ManifestInfo{
+			Versao:   version,
+			Entradas: []string{c.inputPath, c.booksPath},
+			Config: map[string]string{
+				"input":             c.inputPath,
+				"books":             c.booksPath,
+				"aliases":           c.aliasesPath,
+				"metas":             c.metasPath,
+				"observacoes":       c.observacoesPath,
+				"manutencao":        c.manutencaoPath,
+				"cultos":            c.cultosPath,
+				"brigada":           c.brigadaPath,
+				"brigada-validade":  strconv.Itoa(c.brigadaValidade),
+				"metrica":           string(c.metrica),
+				"minimo-manutencao": strconv.FormatFloat(c.minimoManutencao, 'f', -1, 64),
+				"codificacao":       c.codificacao,
+				"privacidade":       string(c.privacidade),
+				"pesos-saude": fmt.Sprintf("cobertura=%g,metas=%g,alertas=%g,tendencia=%g",
+					c.pesosSaude.Cobertura, c.pesosSaude.Metas, c.pesosSaude.Alertas, c.pesosSaude.Tendencia),
+			},
+		}),
+	}
+	if c.completo {
+		opts = append(opts, usecase.WithDocumentoCompleto())
+	}
+	if c.forcar {
+		opts = append(opts, usecase.WithGeracaoCompleta())
+	}
+	if c.classificacao {
+		opts = append(opts, usecase.WithClassificacao(infrastructure.NewXLSXPlanilhaService()))
+	}
+	if c.pacotes {
+		opts = append(opts, usecase.WithPacotes(
+			infrastructure.NewXLSXPlanilhaService(),
+			infrastructure.NewZIPPacoteService(logger),
+		))
+	}
+
+	return usecase.NewReportGenerator(localidadeRepo, setorRepo, livroRepo, pdfService, append(opts, extra...)...), nil
+}
+
+func runGenerate(args []string) {
+	fs := flag.NewFlagSet("generate", flag.ExitOnError)
+	config := geracaoFlags(fs)
+	interativo := fs.Bool("interativo", false, "confirma as sugestões de apelidos pelo terminal")
+	progresso := fs.Bool("progresso", true, "exibe o progresso da geração no terminal")
+	newLogger := logFlags(fs)
+	fs.Parse(args)
+
+	logger := newLogger()
+
+	// Verifica se os arquivos existem
+	if err := checkFiles(config.inputPath, config.booksPath); err != nil {
+		fatal(logger, "arquivo de entrada ausente", err)
+	}
 
-	// Inicializa o serviço de PDF
-	pdfService := infrastructure.NewGofpdfService()
+	var opts []usecase.Option
+	if *progresso {
+		opts = append(opts, usecase.WithProgresso(barraProgresso(os.Stdout)))
+	}
+	if *interativo {
+		opts = append(opts, usecase.WithConfirmacaoAlias(confirmarAlias(bufio.NewReader(os.Stdin))))
+	}
 
 	// Inicializa o gerador de relatórios
-	reportGenerator := usecase.NewReportGenerator(localidadeRepo, setorRepo, livroRepo, pdfService)
+	reportGenerator, err := config.newReportGenerator(logger, opts...)
+	if err != nil {
+		fatal(logger, "erro ao carregar configuração", err)
+	}
+
+	// Interrompe a geração ao receber Ctrl+C
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
 
 	// Gera os relatórios
-	if err := reportGenerator.GenerateReports(); err != nil {
-		log.Fatal("Erro ao gerar relatórios:", err)
+	resultado, err := reportGenerator.GenerateReports(ctx)
+	if err != nil {
+		fatal(logger, "erro ao gerar relatórios", err)
+	}
+
+	if validacao := reportGenerator.Validacao(); validacao != nil && len(validacao.Resolucoes) > 0 {
+		fmt.Print(validacao)
+		if len(validacao.NaoResolvidas()) > 0 {
+			fmt.Printf("Para confirmar um apelido, marque S na coluna \"confirmar\" de %s\n", config.pendentesPath)
+		}
+	}
+
+	fmt.Print(resultado)
+	if len(resultado.Falhas) > 0 {
+		stop()
+		fatal(logger, "alguns relatórios não foram gerados", resultado.Err())
 	}
 
 	fmt.Println("Relatórios gerados com sucesso!")
 }
 
+// logFlags registra as opções de log em fs e retorna a função que cria o
+// logger a partir delas, depois de fs.Parse
+func logFlags(fs *flag.FlagSet) func() *slog.Logger {
+	logLevel := fs.String("log-level", "warn", "nível de log: debug, info, warn ou error")
+	logFormat := fs.String("log-format", "text", "formato do log: text ou json")
+
+	return func() *slog.Logger {
+		logger, err := newLogger(os.Stderr, *logLevel, *logFormat)
+		if err != nil {
+			fatal(slog.Default(), "configuração de log inválida", err)
+		}
+		slog.SetDefault(logger)
+		return logger
+	}
+}
+
+// newLogger cria o logger estruturado conforme o nível e o formato informados
+func newLogger(w io.Writer, level, format string) (*slog.Logger, error) {
+	var lvl slog.Level
+	if err := lvl.UnmarshalText([]byte(level)); err != nil {
+		return nil, fmt.Errorf("nível de log inválido: %s", level)
+	}
+
+	opts := &slog.HandlerOptions{Level: lvl}
+	switch format {
+	case "text":
+		return slog.New(slog.NewTextHandler(w, opts)), nil
+	case "json":
+		return slog.New(slog.NewJSONHandler(w, opts)), nil
+	default:
+		return nil, fmt.Errorf("formato de log inválido: %s", format)
+	}
+}
+
+func fatal(logger *slog.Logger, msg string, err error) {
+	logger.Error(msg, "erro", err)
+	os.Exit(1)
+}
+
+// barraProgresso exibe um contador de documentos concluídos na mesma linha do terminal
+func barraProgresso(w io.Writer) usecase.Progresso {
+	const largura = 30
+	return func(concluidos, total int, _ string) {
+		preenchido := largura * concluidos / total
+		fmt.Fprintf(w, "\r[%s%s] %d/%d", strings.Repeat("#", preenchido), strings.Repeat(" ", largura-preenchido), concluidos, total)
+		if concluidos == total {
+			fmt.Fprintln(w)
+		}
+	}
+}
+
+func confirmarAlias(reader *bufio.Reader) func(domain.SugestaoAlias) bool {
+	return func(sugestao domain.SugestaoAlias) bool {
+		fmt.Printf("\"%s\" corresponde a %s (%.0f%%)? [s/N] ", sugestao.Alias, sugestao.Localidade, sugestao.Similaridade*100)
+		resposta, _ := reader.ReadString('\n')
+		resposta = strings.ToLower(strings.TrimSpace(resposta))
+		return resposta == "s" || resposta == "sim"
+	}
+}
+
 func checkFiles(paths ...string) error {
 	for _, path := range paths {
 		if _, err := os.Stat(path); os.IsNotExist(err) {
@@ -0,0 +1,112 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"syscall"
+	"time"
+
+	"report/internal/domain"
+	"report/internal/infrastructure"
+	"report/internal/usecase"
+)
+
+// runSend envia aos setores os relatórios de um período já gerado
+func runSend(args []string) {
+	fs := flag.NewFlagSet("send", flag.ExitOnError)
+	outputDir := fs.String("output", usecase.DefaultOutputDir, "diretório dos relatórios gerados")
+	periodo := time.Now().Format(domain.LayoutPeriodo)
+	fs.Func("periodo", "período dos relatórios: AAAA-MM (padrão: o mês atual)", func(valor string) error {
+		periodo = valor
+		return domain.ValidarPeriodo(valor)
+	})
+	historicoDir := fs.String("historico", defaultHistoricoDir, "diretório do histórico de períodos")
+	contatosPath := fs.String("contatos", "./files/contatos.csv", "e-mails dos setores (colunas setor,email)")
+	registroPath := fs.String("registro", "./files/envios.json", "registro dos envios realizados")
+	modeloPath := fs.String("modelo", "", "modelo do corpo da mensagem (text/template)")
+	remetente := fs.String("remetente", "", "endereço do remetente")
+	smtpHost := fs.String("smtp-host", "localhost", "servidor SMTP")
+	smtpPorta := fs.Int("smtp-porta", 587, "porta do servidor SMTP")
+	smtpUsuario := fs.String("smtp-usuario", "", "usuário do servidor SMTP")
+	simular := fs.String("simular", "", "grava as mensagens como .eml no diretório informado, sem enviar")
+	reenviar := fs.Bool("reenviar", false, "envia mesmo aos setores que já receberam os relatórios do período")
+	timeout := fs.Duration("timeout", time.Minute, "tempo máximo por mensagem")
+	newLogger := logFlags(fs)
+	fs.Parse(args)
+
+	logger := newLogger()
+
+	if *remetente == "" {
+		fatal(logger, "remetente não informado", fmt.Errorf("use -remetente"))
+	}
+
+	modelo := ""
+	if *modeloPath != "" {
+		content, err := os.ReadFile(*modeloPath)
+		if err != nil {
+			fatal(logger, "erro ao ler modelo da mensagem", err)
+		}
+		modelo = string(content)
+	}
+
+	_, setorRepo, _ := infrastructure.NewCSVRepositories("", "", logger)
+	if err := setorRepo.LoadContatos(*contatosPath); err != nil {
+		fatal(logger, "erro ao carregar contatos", err)
+	}
+
+	// A senha vem do ambiente para não ficar no histórico do terminal
+	var emailService usecase.EmailService = infrastructure.NewSMTPEmailService(infrastructure.SMTPConfig{
+		Host:    *smtpHost,
+		Porta:   *smtpPorta,
+		Usuario: *smtpUsuario,
+		Senha:   os.Getenv("SMTP_SENHA"),
+	}, logger)
+	if *simular != "" {
+		emailService = infrastructure.NewEMLEmailService(filepath.Clean(*simular))
+	}
+
+	entrega := usecase.NewEntrega(
+		setorRepo,
+		infrastructure.NewJSONHistoricoRepository(*historicoDir),
+		infrastructure.NewJSONEnvioRepository(*registroPath),
+		infrastructure.NewJSONManifestService(),
+		limiteEnvio{emailService, *timeout},
+		logger,
+	)
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	resultado, err := entrega.Entregar(ctx, usecase.OpcoesEntrega{
+		Periodo:   periodo,
+		OutputDir: *outputDir,
+		Remetente: *remetente,
+		Modelo:    modelo,
+		Simular:   *simular != "",
+		Reenviar:  *reenviar,
+	})
+	if err != nil {
+		fatal(logger, "erro ao enviar relatórios", err)
+	}
+
+	fmt.Print(resultado)
+	if len(resultado.Falhas()) > 0 {
+		os.Exit(1)
+	}
+}
+
+// limiteEnvio aplica um tempo máximo ao envio de cada mensagem
+type limiteEnvio struct {
+	usecase.EmailService
+	timeout time.Duration
+}
+
+func (l limiteEnvio) Send(ctx context.Context, mensagem *usecase.Mensagem) error {
+	ctx, cancel := context.WithTimeout(ctx, l.timeout)
+	defer cancel()
+	return l.EmailService.Send(ctx, mensagem)
+}
@@ -0,0 +1,138 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"net/http"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"syscall"
+	"time"
+
+	"report/internal/infrastructure"
+	"report/internal/interfaces/web"
+	"report/internal/usecase"
+)
+
+// runServe inicia o servidor HTTP para envio da listagem e download dos relatórios
+func runServe(args []string) {
+	fs := flag.NewFlagSet("serve", flag.ExitOnError)
+	config := geracaoFlags(fs)
+	addr := fs.String("addr", "127.0.0.1:8080", "endereço em que o servidor escuta")
+	dataDir := fs.String("dados", "./files/runs", "diretório onde as execuções são guardadas")
+	maxRuns := fs.Int("manter", 20, "número de execuções guardadas (0 mantém todas)")
+	newLogger := logFlags(fs)
+	fs.Parse(args)
+
+	logger := newLogger()
+
+	// Cada execução usa a listagem enviada e grava os relatórios e o estado
+	// no próprio diretório, partindo de uma cópia do estado do operador
+	runner := func(ctx context.Context, req web.RunRequest) (*usecase.Resultado, error) {
+		execucao, err := config.estadoExecucao(req.EstadoDir)
+		if err != nil {
+			return nil, err
+		}
+		execucao.inputPath = req.InputPath
+		execucao.booksPath = req.BooksPath
+		execucao.outputDir = req.OutputDir
+		execucao.periodo = req.Periodo
+		generator, err := execucao.newReportGenerator(logger)
+		if err != nil {
+			return nil, err
+		}
+		return generator.GenerateReports(ctx)
+	}
+
+	// A API consulta o histórico gravado pelas execuções e o catálogo padrão
+	_, setorRepo, livroRepo := infrastructure.NewCSVRepositories("", config.booksPath, logger)
+	if err := setorRepo.LoadAdministracoes(config.administracoesPath); err != nil {
+		fatal(logger, "erro ao carregar administrações", err)
+	}
+	consulta := usecase.NewConsulta(setorRepo, livroRepo, infrastructure.NewJSONHistoricoRepository(config.historicoDir))
+
+	server, err := web.NewServer(web.Config{
+		DataDir:   *dataDir,
+		BooksPath: config.booksPath,
+		MaxRuns:   *maxRuns,
+		Consulta:  consulta,
+	}, runner, logger)
+	if err != nil {
+		fatal(logger, "erro ao iniciar servidor", err)
+	}
+
+	httpServer := &http.Server{
+		Addr:              *addr,
+		Handler:           server.Handler(),
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	encerrado := make(chan struct{})
+	go func() {
+		defer close(encerrado)
+		<-ctx.Done()
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+		httpServer.Shutdown(shutdownCtx)
+		server.Close()
+	}()
+
+	logger.Info("servidor iniciado", "endereco", *addr, "dados", *dataDir)
+	if err := httpServer.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+		fatal(logger, "erro no servidor", err)
+	}
+	<-encerrado
+}
+
+// estadoExecucao copia para dir o histórico, o registro da brigada e a tabela
+// de apelidos do operador e retorna a configuração que os usa. A execução lê
+// e atualiza as cópias, de modo que um envio pelo servidor não altera o
+// estado usado pela linha de comando nem o de outras execuções.
+func (c *geracaoConfig) estadoExecucao(dir string) (*geracaoConfig, error) {
+	execucao := *c
+	execucao.historicoDir = filepath.Join(dir, "historico")
+	execucao.brigadaPath = filepath.Join(dir, "brigada.json")
+	execucao.aliasesPath = filepath.Join(dir, "aliases.csv")
+	execucao.pendentesPath = filepath.Join(dir, "aliases_pendentes.csv")
+
+	if err := os.MkdirAll(execucao.historicoDir, os.ModePerm); err != nil {
+		return nil, fmt.Errorf("erro ao criar diretório: %v", err)
+	}
+	copias := map[string]string{
+		c.brigadaPath: execucao.brigadaPath,
+		c.aliasesPath: execucao.aliasesPath,
+	}
+	entradas, err := os.ReadDir(c.historicoDir)
+	if err != nil && !os.IsNotExist(err) {
+		return nil, fmt.Errorf("erro ao ler histórico: %v", err)
+	}
+	for _, entrada := range entradas {
+		if entrada.Type().IsRegular() {
+			copias[filepath.Join(c.historicoDir, entrada.Name())] = filepath.Join(execucao.historicoDir, entrada.Name())
+		}
+	}
+	for origem, destino := range copias {
+		if err := copiarArquivo(origem, destino); err != nil {
+			return nil, fmt.Errorf("erro ao copiar %s: %v", origem, err)
+		}
+	}
+	return &execucao, nil
+}
+
+// copiarArquivo copia origem para destino; uma origem inexistente é ignorada
+func copiarArquivo(origem, destino string) error {
+	conteudo, err := os.ReadFile(origem)
+	if os.IsNotExist(err) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(destino, conteudo, 0644)
+}
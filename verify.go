@@ -0,0 +1,33 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+
+	"report/internal/infrastructure"
+	"report/internal/usecase"
+)
+
+// runVerify confere um diretório de saída com o manifesto gravado nele
+func runVerify(args []string) {
+	fs := flag.NewFlagSet("verify", flag.ExitOnError)
+	outputDir := fs.String("output", usecase.DefaultOutputDir, "diretório dos relatórios a verificar")
+	newLogger := logFlags(fs)
+	fs.Parse(args)
+
+	logger := newLogger()
+
+	verifier := usecase.NewManifestVerifier(infrastructure.NewJSONManifestService())
+	verificacao, err := verifier.Verify(*outputDir)
+	if err != nil {
+		fatal(logger, "erro ao verificar manifesto", err)
+	}
+
+	fmt.Print(verificacao)
+	if !verificacao.OK() {
+		fmt.Println("O diretório não corresponde ao manifesto")
+		os.Exit(1)
+	}
+	fmt.Println("Todos os documentos conferem com o manifesto")
+}
@@ -0,0 +1,64 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+	"time"
+
+	"report/internal/infrastructure"
+)
+
+// runWatch gera os relatórios e os gera novamente a cada alteração da
+// listagem, do cadastro de livros, das metas, dos apelidos ou das sugestões
+// pendentes. Os documentos cujas entradas não mudaram são mantidos pelo cache.
+func runWatch(args []string) {
+	fs := flag.NewFlagSet("watch", flag.ExitOnError)
+	config := geracaoFlags(fs)
+	intervalo := fs.Duration("intervalo", 2*time.Second, "intervalo entre as verificações dos arquivos")
+	espera := fs.Duration("espera", 3*time.Second, "tempo sem alterações antes de gerar novamente")
+	newLogger := logFlags(fs)
+	fs.Parse(args)
+
+	logger := newLogger()
+
+	if err := checkFiles(config.inputPath, config.booksPath); err != nil {
+		fatal(logger, "arquivo de entrada ausente", err)
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	// Cada geração usa repositórios novos, que leem os arquivos atualizados
+	gerar := func() {
+		generator, err := config.newReportGenerator(logger)
+		if err != nil {
+			logger.Error("erro ao carregar configuração", "erro", err)
+			return
+		}
+		resultado, err := generator.GenerateReports(ctx)
+		if err != nil {
+			logger.Error("erro ao gerar relatórios", "erro", err)
+			return
+		}
+		fmt.Print(resultado)
+	}
+
+	gerar()
+
+	observados := []string{
+		config.inputPath, config.booksPath, config.metasPath, config.observacoesPath, config.manutencaoPath,
+		config.cultosPath, config.administracoesPath, config.aliasesPath, config.pendentesPath,
+	}
+	fmt.Printf("Observando %s. Pressione Ctrl+C para encerrar.\n", strings.Join(observados, ", "))
+
+	observador := infrastructure.NewObservador(observados, *intervalo, *espera, logger)
+	observador.Observar(ctx, func(arquivos []string) {
+		fmt.Printf("\n%s Alterado: %s\n", time.Now().Format("15:04:05"), strings.Join(arquivos, ", "))
+		gerar()
+	})
+}